@@ -11,9 +11,11 @@ func Insert(engine *core.Engine) {
 	if engine.Envs.ToBool(core.AutoMigrate) {
 		table.InsertCities(engine)
 		table.InsertRoles(engine)
+		table.InsertCompanies(engine)
 		table.InsertAccounts(engine)
 
 		table.InsertUsers(engine)
+		table.InsertUserCompanies(engine)
 		table.InsertSettings(engine)
 
 	}
@@ -6,7 +6,9 @@ import (
 	"omono/domain/subscriber/enum/accounttype"
 	"omono/domain/subscriber/submodel"
 	"omono/domain/subscriber/subrepo"
+	"omono/internal/consts"
 	"omono/internal/core"
+	"omono/internal/param"
 	"omono/pkg/glog"
 	"omono/pkg/helper"
 
@@ -24,20 +26,21 @@ func InsertAccounts(engine *core.Engine) {
 			Model: gorm.Model{
 				ID: 1,
 			},
-			NameEn: "Test Customer",
-			NameKu: helper.StrPointer("test customer"),
-			Type:   accounttype.VIP,
-			Status: accountstatus.Active,
+			CompanyID: consts.DefaultCompanyID,
+			NameEn:    "Test Customer",
+			NameKu:    helper.StrPointer("test customer"),
+			Type:      accounttype.VIP,
+			Status:    accountstatus.Active,
 		},
 	}
 
 	for _, v := range accounts {
-		if _, err := accountService.FindByID(v.ID); err == nil {
-			if _, _, err := accountService.Save(v); err != nil {
+		if _, err := accountService.FindByID(param.New(), v.ID); err == nil {
+			if _, _, err := accountService.Save(param.New(), v); err != nil {
 				glog.Fatal("error in saving accounts", err)
 			}
 		} else {
-			if _, err := accountService.Create(v); err != nil {
+			if _, err := accountService.Create(param.New(), v); err != nil {
 				glog.Fatal("error in creating accounts", err)
 			}
 		}
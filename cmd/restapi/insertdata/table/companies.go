@@ -0,0 +1,54 @@
+package table
+
+import (
+	"omono/domain/base/basrepo"
+	"omono/domain/segment/segmodel"
+	"omono/domain/segment/segrepo"
+	"omono/domain/service"
+	"omono/internal/consts"
+	"omono/internal/core"
+	"omono/internal/param"
+	"omono/pkg/glog"
+
+	"gorm.io/gorm"
+)
+
+// InsertCompanies for add required companies
+func InsertCompanies(engine *core.Engine) {
+	engine.DB.Exec("UPDATE seg_companies SET deleted_at = null WHERE id IN (1)")
+	companyRepo := segrepo.ProvideCompanyRepo(engine)
+	companyService := service.ProvideSegCompanyService(companyRepo)
+	companies := []segmodel.Company{
+		{
+			Model: gorm.Model{
+				ID: consts.DefaultCompanyID,
+			},
+			Name: "Main Company",
+		},
+	}
+
+	for _, v := range companies {
+		if _, err := companyService.FindByID(param.New(), v.ID); err == nil {
+			if _, _, err := companyService.Save(param.New(), v); err != nil {
+				glog.Fatal("error in saving companies", err)
+			}
+		} else {
+			if _, err := companyService.Create(param.New(), v); err != nil {
+				glog.Fatal("error in creating companies", err)
+			}
+		}
+	}
+
+}
+
+// InsertUserCompanies join the admin to the default company
+func InsertUserCompanies(engine *core.Engine) {
+	userCompanyService := service.ProvideBasUserCompanyService(
+		basrepo.ProvideUserCompanyRepo(engine))
+
+	if err := userCompanyService.TxJoin(engine.DB, consts.UserSuperAdminID,
+		consts.DefaultCompanyID); err != nil {
+		glog.Fatal("error in joining the admin to the default company", err)
+	}
+
+}
@@ -9,7 +9,7 @@ import (
 	"omono/domain/service"
 	"omono/domain/subscriber"
 	"omono/internal/core"
-	"omono/internal/types"
+	"omono/internal/core/corperm"
 	"omono/pkg/glog"
 
 	"gorm.io/gorm"
@@ -26,18 +26,22 @@ func InsertRoles(engine *core.Engine) {
 				ID: 1,
 			},
 			Name: "Admin",
-			Resources: types.ResourceJoin([]types.Resource{
-				base.SuperAccess, base.ReadDeleted,
+			Resources: corperm.RulesOf(
+				base.SuperAccess, base.ReadDeleted, base.RestoreDeleted,
 				base.SettingRead, base.SettingWrite, base.SettingExcel,
 				base.UserWrite, base.UserRead, base.UserExcel,
 				base.ActivitySelf,
 				base.RoleRead, base.RoleWrite, base.RoleExcel,
 				base.CityRead, base.CityWrite, base.CityExcel,
+				base.APIKeyRead, base.APIKeyWrite,
+				base.SessionRead, base.SessionWrite,
+				base.GrantRead, base.GrantWrite,
+				base.UserImpersonate, base.UserApprove,
 				notification.MessageWrite, notification.MessageExcel,
 				subscriber.AccountRead, subscriber.AccountWrite, subscriber.AccountExcel,
 				subscriber.PhoneRead, subscriber.PhoneWrite, subscriber.PhoneExcel,
 				segment.CompanyRead, segment.CompanyWrite, segment.CompanyExcel,
-			}),
+			),
 			Description: "admin has all privileges - do not edit",
 		},
 		{
@@ -45,11 +49,11 @@ func InsertRoles(engine *core.Engine) {
 				ID: 2,
 			},
 			Name: "Reader",
-			Resources: types.ResourceJoin([]types.Resource{
+			Resources: corperm.RulesOf(
 				base.SettingRead, base.SettingExcel,
 				base.UserRead, base.UserExcel,
 				base.RoleRead, base.RoleExcel,
-			}),
+			),
 			Description: "Reader can see all part without changes",
 		},
 	}
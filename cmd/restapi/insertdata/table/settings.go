@@ -27,6 +27,114 @@ func InsertSettings(engine *core.Engine) {
 			Type:        "string",
 			Description: "in case of user JWT not specified this value has been used",
 		},
+		{
+			Model: gorm.Model{
+				ID: 3,
+			},
+			Property:    base.MFARequiredRoles,
+			Value:       "0",
+			Type:        "string",
+			Description: "comma separated role's ID which are forced to use two-factor authentication, 0 means none",
+		},
+		{
+			Model: gorm.Model{
+				ID: 4,
+			},
+			Property:    base.LoginMaxAttempts,
+			Value:       "5",
+			Type:        "int",
+			Description: "number of failed logins for a username before lockout",
+		},
+		{
+			Model: gorm.Model{
+				ID: 5,
+			},
+			Property:    base.LoginIPMaxAttempts,
+			Value:       "50",
+			Type:        "int",
+			Description: "number of failed logins from an IP before lockout",
+		},
+		{
+			Model: gorm.Model{
+				ID: 6,
+			},
+			Property:    base.LoginLockoutDuration,
+			Value:       "900",
+			Type:        "int",
+			Description: "lockout duration in seconds, failures older than it are not counted",
+		},
+		{
+			Model: gorm.Model{
+				ID: 7,
+			},
+			Property:    base.LoginDelayStep,
+			Value:       "1",
+			Type:        "int",
+			Description: "seconds of delay added before checking the password for each failure",
+		},
+		{
+			Model: gorm.Model{
+				ID: 8,
+			},
+			Property:    base.PasswordMinLength,
+			Value:       "8",
+			Type:        "int",
+			Description: "minimum number of characters for password",
+		},
+		{
+			Model: gorm.Model{
+				ID: 9,
+			},
+			Property:    base.PasswordRequiredClasses,
+			Value:       "none",
+			Type:        "string",
+			Description: "comma separated character classes which password should contain: lower, upper, digit, symbol",
+		},
+		{
+			Model: gorm.Model{
+				ID: 10,
+			},
+			Property:    base.PasswordHistory,
+			Value:       "5",
+			Type:        "int",
+			Description: "number of last passwords which can't be reused, 0 means disabled",
+		},
+		{
+			Model: gorm.Model{
+				ID: 11,
+			},
+			Property:    base.PasswordMaxAge,
+			Value:       "0",
+			Type:        "int",
+			Description: "maximum age of password in days, after that login is refused till reset, 0 means disabled",
+		},
+		{
+			Model: gorm.Model{
+				ID: 12,
+			},
+			Property:    base.RegistrationVerifyEmail,
+			Value:       "false",
+			Type:        "bool",
+			Description: "registered users should verify their email before login",
+		},
+		{
+			Model: gorm.Model{
+				ID: 13,
+			},
+			Property:    base.RegistrationRequireApproval,
+			Value:       "false",
+			Type:        "bool",
+			Description: "registered users should be approved by a user with user:approve resource before login",
+		},
+		{
+			Model: gorm.Model{
+				ID: 14,
+			},
+			Property:    base.DeletedRetention,
+			Value:       "90",
+			Type:        "int",
+			Description: "days which the deleted rows are kept before purging them, 0 means they are kept forever",
+		},
 	}
 
 	for _, v := range settings {
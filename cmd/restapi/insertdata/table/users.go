@@ -8,6 +8,7 @@ import (
 	"omono/domain/service"
 	"omono/internal/consts"
 	"omono/internal/core"
+	"omono/internal/param"
 	"omono/pkg/glog"
 
 	"github.com/syronz/dict"
@@ -58,7 +59,7 @@ func InsertUsers(engine *core.Engine) {
 				glog.Fatal("error in saving users", err)
 			}
 		} else {
-			if _, err := userService.Create(v); err != nil {
+			if _, err := userService.Create(param.New(), v); err != nil {
 				glog.Fatal("error in creating users", err)
 			}
 		}
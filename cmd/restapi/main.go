@@ -27,6 +27,10 @@ func main() {
 	// load terms
 	dict.Init(engine.Envs[core.TermsPath], engine.Envs.ToBool(core.TranslateInBackend))
 
+	// load the keys for signing and verifying the tokens
+	startoff.LoadJWTKeys(engine)
+	go startoff.WatchJWTKeys(engine)
+
 	// connect the database
 	corstartoff.ConnectDB(engine, false)
 	corstartoff.ConnectActivityDB(engine)
@@ -37,14 +41,39 @@ func main() {
 	// insert basic data
 	insertdata.Insert(engine)
 
+	// cache of the users' resources, it should be ready before serving the requests
+	startoff.LoadPermissionCache(engine)
+
 	// ActivityWatcher is use a channel for checking all activities for recording
 	engine.ActivityCh = make(chan basmodel.Activity, 1)
 	activityRepo := basrepo.ProvideActivityRepo(engine)
 	basActivityServ := service.ProvideBasActivityService(activityRepo)
 	go basActivityServ.ActivityWatcher()
 
+	// PurgeWatcher delete expired refresh tokens and revoked access tokens
+	basTokenServ := service.ProvideBasTokenService(basrepo.ProvideTokenRepo(engine))
+	go basTokenServ.PurgeWatcher()
+
+	basPasswordServ := service.ProvideBasPasswordService(basrepo.ProvidePasswordRepo(engine))
+	go basPasswordServ.PurgeWatcher()
+
+	basSessionServ := service.ProvideBasSessionService(basrepo.ProvideSessionRepo(engine))
+	go basSessionServ.PurgeWatcher()
+
+	basRegistrationServ := service.ProvideBasRegistrationService(
+		basrepo.ProvideRegistrationRepo(engine))
+	go basRegistrationServ.PurgeWatcher()
+
+	// ExpireWatcher record the ended temporary grants in the activities
+	basGrantServ := service.ProvideBasGrantService(basrepo.ProvideGrantRepo(engine))
+	go basGrantServ.ExpireWatcher()
+
+	// PurgeDeletedWatcher hard delete the soft deleted rows after the deleted_retention
+	go service.BasPurgeDeletedWatcher(engine)
+
 	// load setting
 	corstartoff.LoadSetting(engine)
+	service.BasLoadPasswordPolicy(engine)
 
 	// start the API
 	server.Start(engine)
@@ -12,13 +12,28 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// jwksRoute is registered outside of the api group
+func jwksRoute(engine *core.Engine) gin.HandlerFunc {
+	basAuthAPI := initAuthAPI(engine)
+	return basAuthAPI.JWKS
+}
+
 // Route trigger router and api methods
 func Route(rg gin.RouterGroup, engine *core.Engine) {
 
 	// Base Domain
 	basAuthAPI := initAuthAPI(engine)
+	basMFAAPI := initMFAAPI(engine)
+	basPasswordAPI := initPasswordAPI(engine)
+	basAPIKeyAPI := initAPIKeyAPI(engine)
+	basSessionAPI := initSessionAPI(engine)
+	basRegistrationAPI := initRegistrationAPI(engine)
 	basUserAPI := initUserAPI(engine)
 	basRoleAPI := initRoleAPI(engine)
+	basAccessAPI := initAccessAPI(engine)
+	basUserCompanyAPI := initUserCompanyAPI(engine)
+	basGrantAPI := initGrantAPI(engine)
+	basViewAPI := initViewAPI(engine)
 	basSettingAPI := initSettingAPI(engine)
 	basActivityAPI := initActivityAPI(engine)
 	basCityAPI := initBasCityAPI(engine)
@@ -37,130 +52,223 @@ func Route(rg gin.RouterGroup, engine *core.Engine) {
 	rg.StaticFS("/public", http.Dir("public"))
 
 	rg.POST("/login", basAuthAPI.Login)
+	rg.POST("/login/mfa", basAuthAPI.LoginMFA)
+	rg.POST("/refresh", basAuthAPI.Refresh)
 	rg.POST("/register", basAuthAPI.Register)
+	rg.GET("/register/verify", basRegistrationAPI.Verify)
+	rg.POST("/register/resend", basRegistrationAPI.Resend)
+	rg.POST("/password/forgot", basPasswordAPI.Forgot)
+	rg.POST("/password/reset", basPasswordAPI.Reset)
+
+	// these routes accept mfa pending tokens for enrolling in case two-factor is required
+	mfaGroup := rg.Group("/mfa", basmid.MFAGuard(engine))
+	mfaGroup.POST("/enroll", basMFAAPI.Enroll)
+	mfaGroup.POST("/confirm", basMFAAPI.Confirm)
 
 	rg.Use(basmid.AuthGuard(engine))
 
 	rg.GET("/profile", basAuthAPI.Profile)
+	rg.GET("/profile/permissions", basAccessAPI.Permissions)
+	rg.GET("/profile/companies", basUserCompanyAPI.ListSelf)
+	rg.GET("/profile/grants", basGrantAPI.ListSelf)
+	rg.POST("/companies/:companyID/switch", basAuthAPI.SwitchCompany)
 
 	access := basmid.NewAccessMid(engine)
+	views := basmid.NewViewMid(engine)
 
 	rg.POST("/logout", basAuthAPI.Logout)
+	rg.POST("/mfa/disable", basMFAAPI.Disable)
+	rg.POST("/password/change", basPasswordAPI.Change)
+
+	rg.GET("/sessions/self", basSessionAPI.ListSelf)
+	rg.DELETE("/sessions/:sessionID", basSessionAPI.Terminate)
 
 	// Base Domain
 	rg.GET("/temporary/token", basAuthAPI.TemporaryToken)
 
 	rg.GET("/settings",
-		access.Check(base.SettingRead), basSettingAPI.List)
+		access.Check(base.SettingRead), views.Apply("settings"), basSettingAPI.List)
 	rg.GET("/settings/:settingID",
 		access.Check(base.SettingRead), basSettingAPI.FindByID)
 	rg.PUT("/settings/:settingID",
 		access.Check(base.SettingWrite), basSettingAPI.Update)
+	rg.PATCH("/settings/:settingID",
+		access.Check(base.SettingWrite), basSettingAPI.Update)
 	rg.GET("/excel/settings",
-		access.Check(base.SettingExcel), basSettingAPI.Excel)
+		access.Check(base.SettingExcel), views.Apply("settings"), basSettingAPI.Excel)
 
 	rg.GET("/roles",
-		access.Check(base.RoleRead), basRoleAPI.List)
+		access.Check(base.RoleRead), views.Apply("roles"), basRoleAPI.List)
 	rg.GET("/roles/:roleID",
 		access.Check(base.RoleRead), basRoleAPI.FindByID)
 	rg.POST("/roles",
 		access.Check(base.RoleWrite), basRoleAPI.Create)
 	rg.PUT("/roles/:roleID",
 		access.Check(base.RoleWrite), basRoleAPI.Update)
+	rg.PATCH("/roles/:roleID",
+		access.Check(base.RoleWrite), basRoleAPI.Update)
 	rg.DELETE("roles/:roleID",
 		access.Check(base.RoleWrite), basRoleAPI.Delete)
+	rg.POST("/roles/:roleID/restore",
+		access.Check(base.RoleWrite), access.Check(base.RestoreDeleted), basRoleAPI.Restore)
 	rg.GET("/excel/roles",
-		access.Check(base.RoleExcel), basRoleAPI.Excel)
+		access.Check(base.RoleExcel), views.Apply("roles"), basRoleAPI.Excel)
+	rg.GET("/resources",
+		access.Check(base.RoleRead), basAccessAPI.Resources)
 
 	rg.GET("/username/:username",
 		access.Check(base.UserRead), basUserAPI.FindByUsername)
 	rg.GET("/users",
-		access.Check(base.UserRead), basUserAPI.List)
+		access.Check(base.UserRead), views.Apply("users"), basUserAPI.List)
 	rg.GET("/users/:userID",
 		access.Check(base.UserRead), basUserAPI.FindByID)
 	rg.POST("/users",
 		access.Check(base.UserWrite), basUserAPI.Create)
 	rg.PUT("/users/:userID",
 		access.Check(base.UserWrite), basUserAPI.Update)
+	rg.PATCH("/users/:userID",
+		access.Check(base.UserWrite), basUserAPI.Update)
 	rg.DELETE("/users/:userID",
 		access.Check(base.UserWrite), basUserAPI.Delete)
+	rg.POST("/users/:userID/restore",
+		access.Check(base.UserWrite), access.Check(base.RestoreDeleted), basUserAPI.Restore)
+	rg.POST("/users/:userID/unlock",
+		access.Check(base.UserWrite), basUserAPI.Unlock)
+	rg.POST("/users/:userID/approve",
+		access.Check(base.UserApprove), basRegistrationAPI.Approve)
+	rg.POST("/users/:userID/impersonate",
+		access.Check(base.UserImpersonate), basAuthAPI.Impersonate)
+	rg.GET("/users/:userID/sessions",
+		access.Check(base.SessionRead), basSessionAPI.ListByUser)
+	rg.GET("/users/:userID/companies",
+		access.Check(base.UserRead), basUserCompanyAPI.ListByUser)
+	rg.PUT("/users/:userID/companies",
+		access.Check(base.UserWrite), basUserCompanyAPI.Replace)
+	rg.GET("/users/:userID/grants",
+		access.Check(base.GrantRead), basGrantAPI.ListByUser)
 	rg.GET("/excel/users",
-		access.Check(base.UserExcel), basUserAPI.Excel)
+		access.Check(base.UserExcel), views.Apply("users"), basUserAPI.Excel)
 
 	rg.GET("/activities",
-		access.Check(base.SuperAccess), basActivityAPI.ListAll)
+		access.Check(base.SuperAccess), views.Apply("activities"), basActivityAPI.ListAll)
 	rg.GET("/activities/self",
-		access.Check(base.ActivitySelf), basActivityAPI.ListSelf)
+		access.Check(base.ActivitySelf), views.Apply("activities"), basActivityAPI.ListSelf)
 
 	rg.GET("/cities",
-		access.Check(base.CityRead), basCityAPI.List)
+		access.Check(base.CityRead), views.Apply("cities"), basCityAPI.List)
 	rg.GET("/cities/:cityID",
 		access.Check(base.CityRead), basCityAPI.FindByID)
 	rg.POST("/cities",
 		access.Check(base.CityWrite), basCityAPI.Create)
 	rg.PUT("/cities/:cityID",
 		access.Check(base.CityWrite), basCityAPI.Update)
+	rg.PATCH("/cities/:cityID",
+		access.Check(base.CityWrite), basCityAPI.Update)
 	rg.DELETE("/cities/:cityID",
 		access.Check(base.CityWrite), basCityAPI.Delete)
+	rg.POST("/cities/:cityID/restore",
+		access.Check(base.CityWrite), access.Check(base.RestoreDeleted), basCityAPI.Restore)
 	rg.GET("/excel/cities",
-		access.Check(base.CityExcel), basCityAPI.Excel)
+		access.Check(base.CityExcel), views.Apply("cities"), basCityAPI.Excel)
+
+	rg.GET("/api-keys",
+		access.Check(base.APIKeyRead), views.Apply("api-keys"), basAPIKeyAPI.List)
+	rg.GET("/api-keys/:apiKeyID",
+		access.Check(base.APIKeyRead), basAPIKeyAPI.FindByID)
+	rg.POST("/api-keys",
+		access.Check(base.APIKeyWrite), basAPIKeyAPI.Create)
+	rg.DELETE("/api-keys/:apiKeyID",
+		access.Check(base.APIKeyWrite), basAPIKeyAPI.Revoke)
+
+	rg.GET("/grants",
+		access.Check(base.GrantRead), views.Apply("grants"), basGrantAPI.List)
+	rg.GET("/grants/:grantID",
+		access.Check(base.GrantRead), basGrantAPI.FindByID)
+	rg.POST("/grants",
+		access.Check(base.GrantWrite), basGrantAPI.Create)
+	rg.DELETE("/grants/:grantID",
+		access.Check(base.GrantWrite), basGrantAPI.Revoke)
+
+	// views are personal, the shared views are read only for the members of the role
+	rg.GET("/views", basViewAPI.List)
+	rg.GET("/views/:viewID", basViewAPI.FindByID)
+	rg.POST("/views", basViewAPI.Create)
+	rg.PUT("/views/:viewID", basViewAPI.Update)
+	rg.PATCH("/views/:viewID", basViewAPI.Update)
+	rg.DELETE("/views/:viewID", basViewAPI.Delete)
 
 	// Notification Domain
 	rg.GET("/messages",
-		notMessageAPI.List)
-	rg.GET("/messages/:cityID",
+		views.Apply("messages"), notMessageAPI.List)
+	rg.GET("/messages/:messageID",
 		access.Check(notification.MessageRead), notMessageAPI.FindByID)
 	rg.GET("/hash/messages/:hash", notMessageAPI.ViewByHash)
 	rg.POST("/messages",
 		access.Check(notification.MessageWrite), notMessageAPI.Create)
-	rg.PUT("/messages/:cityID",
+	rg.PUT("/messages/:messageID",
 		access.Check(notification.MessageWrite), notMessageAPI.Update)
-	rg.DELETE("messages/:cityID",
+	rg.PATCH("/messages/:messageID",
+		access.Check(notification.MessageWrite), notMessageAPI.Update)
+	rg.DELETE("messages/:messageID",
 		access.Check(notification.MessageWrite), notMessageAPI.Delete)
+	rg.POST("/messages/:messageID/restore",
+		access.Check(notification.MessageWrite), access.Check(base.RestoreDeleted), notMessageAPI.Restore)
 	rg.GET("/excel/messages",
-		access.Check(notification.MessageExcel), notMessageAPI.Excel)
+		access.Check(notification.MessageExcel), views.Apply("messages"), notMessageAPI.Excel)
 
 	// Subscriber Domain
 	rg.GET("/accounts",
-		access.Check(subscriber.AccountRead), basAccountAPI.List)
+		access.Check(subscriber.AccountRead), views.Apply("accounts"), basAccountAPI.List)
 	rg.GET("/accounts/:accountID",
 		access.Check(subscriber.AccountRead), basAccountAPI.FindByID)
 	rg.POST("/accounts",
 		access.Check(subscriber.AccountWrite), basAccountAPI.Create)
 	rg.PUT("/accounts/:accountID",
 		access.Check(subscriber.AccountWrite), basAccountAPI.Update)
+	rg.PATCH("/accounts/:accountID",
+		access.Check(subscriber.AccountWrite), basAccountAPI.Update)
 	rg.DELETE("/accounts/:accountID",
 		access.Check(subscriber.AccountWrite), basAccountAPI.Delete)
+	rg.POST("/accounts/:accountID/restore",
+		access.Check(subscriber.AccountWrite), access.Check(base.RestoreDeleted), basAccountAPI.Restore)
 	rg.GET("/excel/accounts",
-		access.Check(subscriber.AccountExcel), basAccountAPI.Excel)
+		access.Check(subscriber.AccountExcel), views.Apply("accounts"), basAccountAPI.Excel)
 
 	rg.GET("/phones",
-		access.Check(base.SuperAccess), basPhoneAPI.List)
+		access.Check(base.SuperAccess), views.Apply("phones"), basPhoneAPI.List)
 	rg.GET("/phones/:phoneID",
 		access.Check(subscriber.PhoneRead), basPhoneAPI.FindByID)
 	rg.POST("/phones",
 		access.Check(subscriber.PhoneWrite), basPhoneAPI.Create)
 	rg.PUT("/phones/:phoneID",
 		access.Check(base.SuperAccess), basPhoneAPI.Update)
+	rg.PATCH("/phones/:phoneID",
+		access.Check(base.SuperAccess), basPhoneAPI.Update)
 	rg.DELETE("/phones/:phoneID",
 		access.Check(subscriber.PhoneWrite), basPhoneAPI.Delete)
+	rg.POST("/phones/:phoneID/restore",
+		access.Check(subscriber.PhoneWrite), access.Check(base.RestoreDeleted), basPhoneAPI.Restore)
 	rg.GET("/excel/phones",
-		access.Check(subscriber.PhoneExcel), basPhoneAPI.Excel)
+		access.Check(subscriber.PhoneExcel), views.Apply("phones"), basPhoneAPI.Excel)
 	rg.DELETE("/separate/:accountPhoneID",
 		access.Check(subscriber.PhoneWrite), basPhoneAPI.Separate)
 
 	// Segment Domain
 	rg.GET("/companies",
-		access.Check(segment.CompanyRead), segCompanyAPI.List)
+		access.Check(segment.CompanyRead), views.Apply("companies"), segCompanyAPI.List)
 	rg.GET("/companies/:companyID",
 		access.Check(segment.CompanyRead), segCompanyAPI.FindByID)
 	rg.POST("/companies",
 		access.Check(segment.CompanyWrite), segCompanyAPI.Create)
 	rg.PUT("/companies/:companyID",
 		access.Check(segment.CompanyWrite), segCompanyAPI.Update)
+	rg.PATCH("/companies/:companyID",
+		access.Check(segment.CompanyWrite), segCompanyAPI.Update)
 	rg.DELETE("/companies/:companyID",
 		access.Check(segment.CompanyWrite), segCompanyAPI.Delete)
+	rg.POST("/companies/:companyID/restore",
+		access.Check(segment.CompanyWrite), access.Check(base.RestoreDeleted), segCompanyAPI.Restore)
 	rg.GET("/excel/companies",
-		access.Check(segment.CompanyExcel), segCompanyAPI.Excel)
+		access.Check(segment.CompanyExcel), views.Apply("companies"), segCompanyAPI.Excel)
 
 }
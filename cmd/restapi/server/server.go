@@ -32,7 +32,7 @@ func Start(engine *core.Engine) *gin.Engine {
 		AllowOrigins:     []string{"*"},
 		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE"},
 		AllowHeaders:     []string{"*"},
-		ExposeHeaders:    []string{"Content-Length"},
+		ExposeHeaders:    []string{"Content-Length", "ETag"},
 		AllowCredentials: true,
 		AllowOriginFunc: func(origin string) bool {
 			return origin == "127.0.0.1"
@@ -44,6 +44,9 @@ func Start(engine *core.Engine) *gin.Engine {
 	// No Route "Not Found"
 	notFoundRoute(r, engine)
 
+	// public keys of the tokens, the path is fixed for being discoverable by other services
+	r.GET("/.well-known/jwks.json", jwksRoute(engine))
+
 	rg := r.Group("/api/restapi/v1")
 	{
 		Route(*rg, engine)
@@ -31,6 +31,28 @@ func initRoleAPI(e *core.Engine) basapi.RoleAPI {
 	return basapi.RoleAPI{}
 }
 
+func initAccessAPI(e *core.Engine) basapi.AccessAPI {
+	wire.Build(basrepo.ProvideAccessRepo, service.ProvideBasAccessService,
+		basapi.ProvideAccessAPI)
+	return basapi.AccessAPI{}
+}
+
+func initUserCompanyAPI(e *core.Engine) basapi.UserCompanyAPI {
+	wire.Build(basrepo.ProvideUserCompanyRepo, service.ProvideBasUserCompanyService,
+		basapi.ProvideUserCompanyAPI)
+	return basapi.UserCompanyAPI{}
+}
+
+func initGrantAPI(e *core.Engine) basapi.GrantAPI {
+	wire.Build(basrepo.ProvideGrantRepo, service.ProvideBasGrantService, basapi.ProvideGrantAPI)
+	return basapi.GrantAPI{}
+}
+
+func initViewAPI(e *core.Engine) basapi.ViewAPI {
+	wire.Build(basrepo.ProvideViewRepo, service.ProvideBasViewService, basapi.ProvideViewAPI)
+	return basapi.ViewAPI{}
+}
+
 func initUserAPI(engine *core.Engine) basapi.UserAPI {
 	wire.Build(basrepo.ProvideUserRepo, service.ProvideBasUserService, basapi.ProvideUserAPI)
 	return basapi.UserAPI{}
@@ -41,6 +63,35 @@ func initAuthAPI(e *core.Engine) basapi.AuthAPI {
 	return basapi.AuthAPI{}
 }
 
+func initMFAAPI(e *core.Engine) basapi.MFAAPI {
+	wire.Build(basrepo.ProvideMFARepo, service.ProvideBasMFAService, basapi.ProvideMFAAPI)
+	return basapi.MFAAPI{}
+}
+
+func initPasswordAPI(e *core.Engine) basapi.PasswordAPI {
+	wire.Build(basrepo.ProvidePasswordRepo, service.ProvideBasPasswordService,
+		basapi.ProvidePasswordAPI)
+	return basapi.PasswordAPI{}
+}
+
+func initAPIKeyAPI(e *core.Engine) basapi.APIKeyAPI {
+	wire.Build(basrepo.ProvideAPIKeyRepo, service.ProvideBasAPIKeyService,
+		basapi.ProvideAPIKeyAPI)
+	return basapi.APIKeyAPI{}
+}
+
+func initSessionAPI(e *core.Engine) basapi.SessionAPI {
+	wire.Build(basrepo.ProvideSessionRepo, service.ProvideBasSessionService,
+		basapi.ProvideSessionAPI)
+	return basapi.SessionAPI{}
+}
+
+func initRegistrationAPI(e *core.Engine) basapi.RegistrationAPI {
+	wire.Build(basrepo.ProvideRegistrationRepo, service.ProvideBasRegistrationService,
+		basapi.ProvideRegistrationAPI)
+	return basapi.RegistrationAPI{}
+}
+
 func initActivityAPI(engine *core.Engine) basapi.ActivityAPI {
 	wire.Build(basrepo.ProvideActivityRepo, service.ProvideBasActivityService, basapi.ProvideActivityAPI)
 	return basapi.ActivityAPI{}
@@ -35,6 +35,34 @@ func initRoleAPI(e *core.Engine) basapi.RoleAPI {
 	return roleAPI
 }
 
+func initAccessAPI(e *core.Engine) basapi.AccessAPI {
+	accessRepo := basrepo.ProvideAccessRepo(e)
+	basAccessServ := service.ProvideBasAccessService(accessRepo)
+	accessAPI := basapi.ProvideAccessAPI(basAccessServ)
+	return accessAPI
+}
+
+func initUserCompanyAPI(e *core.Engine) basapi.UserCompanyAPI {
+	userCompanyRepo := basrepo.ProvideUserCompanyRepo(e)
+	basUserCompanyServ := service.ProvideBasUserCompanyService(userCompanyRepo)
+	userCompanyAPI := basapi.ProvideUserCompanyAPI(basUserCompanyServ)
+	return userCompanyAPI
+}
+
+func initGrantAPI(e *core.Engine) basapi.GrantAPI {
+	grantRepo := basrepo.ProvideGrantRepo(e)
+	basGrantServ := service.ProvideBasGrantService(grantRepo)
+	grantAPI := basapi.ProvideGrantAPI(basGrantServ)
+	return grantAPI
+}
+
+func initViewAPI(e *core.Engine) basapi.ViewAPI {
+	viewRepo := basrepo.ProvideViewRepo(e)
+	basViewServ := service.ProvideBasViewService(viewRepo)
+	viewAPI := basapi.ProvideViewAPI(basViewServ)
+	return viewAPI
+}
+
 func initUserAPI(engine *core.Engine) basapi.UserAPI {
 	userRepo := basrepo.ProvideUserRepo(engine)
 	basUserServ := service.ProvideBasUserService(userRepo)
@@ -48,6 +76,41 @@ func initAuthAPI(e *core.Engine) basapi.AuthAPI {
 	return authAPI
 }
 
+func initMFAAPI(e *core.Engine) basapi.MFAAPI {
+	mfaRepo := basrepo.ProvideMFARepo(e)
+	basMFAServ := service.ProvideBasMFAService(mfaRepo)
+	mfaAPI := basapi.ProvideMFAAPI(basMFAServ)
+	return mfaAPI
+}
+
+func initPasswordAPI(e *core.Engine) basapi.PasswordAPI {
+	passwordRepo := basrepo.ProvidePasswordRepo(e)
+	basPasswordServ := service.ProvideBasPasswordService(passwordRepo)
+	passwordAPI := basapi.ProvidePasswordAPI(basPasswordServ)
+	return passwordAPI
+}
+
+func initAPIKeyAPI(e *core.Engine) basapi.APIKeyAPI {
+	apiKeyRepo := basrepo.ProvideAPIKeyRepo(e)
+	basAPIKeyServ := service.ProvideBasAPIKeyService(apiKeyRepo)
+	apiKeyAPI := basapi.ProvideAPIKeyAPI(basAPIKeyServ)
+	return apiKeyAPI
+}
+
+func initSessionAPI(e *core.Engine) basapi.SessionAPI {
+	sessionRepo := basrepo.ProvideSessionRepo(e)
+	basSessionServ := service.ProvideBasSessionService(sessionRepo)
+	sessionAPI := basapi.ProvideSessionAPI(basSessionServ)
+	return sessionAPI
+}
+
+func initRegistrationAPI(e *core.Engine) basapi.RegistrationAPI {
+	registrationRepo := basrepo.ProvideRegistrationRepo(e)
+	basRegistrationServ := service.ProvideBasRegistrationService(registrationRepo)
+	registrationAPI := basapi.ProvideRegistrationAPI(basRegistrationServ)
+	return registrationAPI
+}
+
 func initActivityAPI(engine *core.Engine) basapi.ActivityAPI {
 	activityRepo := basrepo.ProvideActivityRepo(engine)
 	basActivityServ := service.ProvideBasActivityService(activityRepo)
@@ -0,0 +1,48 @@
+package startoff
+
+import (
+	"omono/domain/base"
+	"omono/internal/core"
+	"omono/pkg/glog"
+	"omono/pkg/helper/jwtkey"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// LoadJWTKeys load the signing keys from the JWT_KEYS_DIR, in case it is not defined the
+// JWT_SECRET_KEY is used for signing the tokens with HS256
+func LoadJWTKeys(engine *core.Engine) {
+	dir := engine.Envs[base.JWTKeysDir]
+	if dir == "" {
+		glog.Debug("JWT_KEYS_DIR is not defined, tokens are signed by HS256")
+		engine.JWTKeys = jwtkey.NewHMAC(engine.Envs.ToByte(base.JWTSecretKey))
+		return
+	}
+
+	var err error
+	if engine.JWTKeys, err = jwtkey.LoadDir(dir, engine.Envs[base.JWTActiveKey]); err != nil {
+		glog.Fatal("error in loading the jwt keys", err)
+	}
+}
+
+// WatchJWTKeys reload the keys after receiving SIGHUP, new keys can be added and the active
+// key can be changed via the active file inside the keys directory without restarting the
+// server, the env of the process doesn't change after starting it
+func WatchJWTKeys(engine *core.Engine) {
+	if engine.Envs[base.JWTKeysDir] == "" {
+		return
+	}
+
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, syscall.SIGHUP)
+
+	for range ch {
+		if err := engine.JWTKeys.Reload(engine.Envs[base.JWTActiveKey]); err != nil {
+			glog.CheckError(err, "jwt keys not reloaded")
+			continue
+		}
+
+		glog.Info("jwt keys reloaded, active key is", engine.JWTKeys.Active())
+	}
+}
@@ -45,12 +45,23 @@ func LoadEnvs() *core.Engine {
 	envs[base.PasswordSalt] = os.Getenv("OMONO_BASE_PASSWORD_SALT")
 	envs[base.JWTSecretKey] = os.Getenv("OMONO_BASE_JWT_SECRET_KEY")
 	envs[base.JWTExpiration] = os.Getenv("OMONO_BASE_JWT_EXPIRATION")
+	envs[base.JWTRefreshExpiration] = os.Getenv("OMONO_BASE_JWT_REFRESH_EXPIRATION")
+	envs[base.JWTKeysDir] = os.Getenv("OMONO_BASE_JWT_KEYS_DIR")
+	envs[base.JWTActiveKey] = os.Getenv("OMONO_BASE_JWT_ACTIVE_KEY")
+	envs[base.PermissionCacheTTL] = os.Getenv("OMONO_BASE_PERMISSION_CACHE_TTL")
+	envs[base.PermissionCacheBackend] = os.Getenv("OMONO_BASE_PERMISSION_CACHE_BACKEND")
 	envs[base.RecordRead] = os.Getenv("OMONO_BASE_RECORD_READ")
 	envs[base.RecordWrite] = os.Getenv("OMONO_BASE_RECORD_WRITE")
 	envs[base.ActivityFileCounter] = os.Getenv("OMONO_BASE_ACTIVITY_FILE_COUNTER")
 	envs[base.ActivityTickTimer] = os.Getenv("OMONO_BASE_ACTIVITY_TICK_TIMER")
 	envs[base.AdminUsername] = os.Getenv("OMONO_BASE_ADMIN_USERNAME")
 	envs[base.AdminPassword] = os.Getenv("OMONO_BASE_ADMIN_PASSWORD")
+	envs[base.PasswordResetExpiration] = os.Getenv("OMONO_BASE_PASSWORD_RESET_EXPIRATION")
+	envs[base.SMTPHost] = os.Getenv("OMONO_BASE_SMTP_HOST")
+	envs[base.SMTPPort] = os.Getenv("OMONO_BASE_SMTP_PORT")
+	envs[base.SMTPUsername] = os.Getenv("OMONO_BASE_SMTP_USERNAME")
+	envs[base.SMTPPassword] = os.Getenv("OMONO_BASE_SMTP_PASSWORD")
+	envs[base.SMTPFrom] = os.Getenv("OMONO_BASE_SMTP_FROM")
 
 	envs[notification.AppURL] = os.Getenv("OMONO_NOTIFICATION_APP_URL")
 
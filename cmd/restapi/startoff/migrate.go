@@ -1,33 +1,104 @@
 package startoff
 
 import (
+	"fmt"
 	"omono/domain/base/basmodel"
 	"omono/domain/notification/notmodel"
 	"omono/domain/segment/segmodel"
 	"omono/domain/subscriber/submodel"
+	"omono/internal/consts"
 	"omono/internal/core"
 )
 
-// Migrate the database for creating tables
+// Migrate the database for creating tables, search_idx is the FULLTEXT index of the columns
+// which have the fulltext search tag
 func Migrate(engine *core.Engine) {
 
 	// Base Domain
 	engine.DB.Table(basmodel.SettingTable).AutoMigrate(&basmodel.Setting{})
+	engine.DB.Exec("ALTER TABLE bas_settings ADD FULLTEXT INDEX `search_idx` (property, value, description);")
 
 	engine.DB.Table(basmodel.RoleTable).AutoMigrate(&basmodel.Role{})
+	engine.DB.Exec("ALTER TABLE bas_roles ADD FULLTEXT INDEX `search_idx` (name, description);")
+	uniqueAlive(engine, basmodel.RoleTable, "name")
 
 	engine.DB.Table(basmodel.UserTable).AutoMigrate(&basmodel.User{})
+	engine.DB.Exec("ALTER TABLE bas_users ADD FULLTEXT INDEX `search_idx` (username, email);")
+	uniqueAlive(engine, basmodel.UserTable, "username")
 	engine.DB.Exec("ALTER TABLE bas_users ADD CONSTRAINT `fk_bas_users_bas_roles` FOREIGN KEY (role_id) REFERENCES bas_roles(id) ON DELETE RESTRICT ON UPDATE RESTRICT;")
+	// the users before the password max age don't have password_changed_at, their creation is used
+	engine.DB.Exec("UPDATE bas_users SET password_changed_at = created_at WHERE password_changed_at IS NULL;")
+
+	engine.DB.Table(basmodel.UserRoleTable).AutoMigrate(&basmodel.UserRole{})
+	engine.DB.Exec("ALTER TABLE bas_user_roles ADD CONSTRAINT `fk_bas_user_roles_bas_users` FOREIGN KEY (user_id) REFERENCES bas_users(id) ON DELETE CASCADE ON UPDATE CASCADE;")
+	engine.DB.Exec("ALTER TABLE bas_user_roles ADD CONSTRAINT `fk_bas_user_roles_bas_roles` FOREIGN KEY (role_id) REFERENCES bas_roles(id) ON DELETE RESTRICT ON UPDATE RESTRICT;")
+	// the role_id of the users is copied to the bas_user_roles, the existing rows are kept
+	engine.DB.Exec("INSERT IGNORE INTO bas_user_roles (user_id, role_id, created_at) SELECT id, role_id, NOW() FROM bas_users WHERE role_id <> 0;")
+
+	engine.DB.Table(basmodel.RoleParentTable).AutoMigrate(&basmodel.RoleParent{})
+	engine.DB.Exec("ALTER TABLE bas_role_parents ADD CONSTRAINT `fk_bas_role_parents_bas_roles` FOREIGN KEY (role_id) REFERENCES bas_roles(id) ON DELETE CASCADE ON UPDATE CASCADE;")
+	engine.DB.Exec("ALTER TABLE bas_role_parents ADD CONSTRAINT `fk_bas_role_parents_parent_bas_roles` FOREIGN KEY (parent_id) REFERENCES bas_roles(id) ON DELETE CASCADE ON UPDATE CASCADE;")
+
+	engine.DB.Table(basmodel.RefreshTokenTable).AutoMigrate(&basmodel.RefreshToken{})
+	engine.DB.Exec("ALTER TABLE bas_refresh_tokens ADD CONSTRAINT `fk_bas_refresh_tokens_bas_users` FOREIGN KEY (user_id) REFERENCES bas_users(id) ON DELETE CASCADE ON UPDATE CASCADE;")
+
+	engine.DB.Table(basmodel.RevokedTokenTable).AutoMigrate(&basmodel.RevokedToken{})
+
+	engine.DB.Table(basmodel.MFATable).AutoMigrate(&basmodel.MFA{})
+	engine.DB.Exec("ALTER TABLE bas_mfas ADD CONSTRAINT `fk_bas_mfas_bas_users` FOREIGN KEY (user_id) REFERENCES bas_users(id) ON DELETE CASCADE ON UPDATE CASCADE;")
+
+	engine.DB.Table(basmodel.RecoveryCodeTable).AutoMigrate(&basmodel.RecoveryCode{})
+	engine.DB.Exec("ALTER TABLE bas_recovery_codes ADD CONSTRAINT `fk_bas_recovery_codes_bas_users` FOREIGN KEY (user_id) REFERENCES bas_users(id) ON DELETE CASCADE ON UPDATE CASCADE;")
+
+	engine.DB.Table(basmodel.LoginAttemptTable).AutoMigrate(&basmodel.LoginAttempt{})
+
+	engine.DB.Table(basmodel.PasswordResetTable).AutoMigrate(&basmodel.PasswordReset{})
+	engine.DB.Exec("ALTER TABLE bas_password_resets ADD CONSTRAINT `fk_bas_password_resets_bas_users` FOREIGN KEY (user_id) REFERENCES bas_users(id) ON DELETE CASCADE ON UPDATE CASCADE;")
+
+	engine.DB.Table(basmodel.PasswordHistoryTable).AutoMigrate(&basmodel.PasswordHistory{})
+	engine.DB.Exec("ALTER TABLE bas_password_histories ADD CONSTRAINT `fk_bas_password_histories_bas_users` FOREIGN KEY (user_id) REFERENCES bas_users(id) ON DELETE CASCADE ON UPDATE CASCADE;")
+
+	engine.DB.Table(basmodel.APIKeyTable).AutoMigrate(&basmodel.APIKey{})
+	engine.DB.Exec("ALTER TABLE bas_api_keys ADD CONSTRAINT `fk_bas_api_keys_bas_users` FOREIGN KEY (user_id) REFERENCES bas_users(id) ON DELETE CASCADE ON UPDATE CASCADE;")
+	engine.DB.Exec("ALTER TABLE bas_api_keys ADD CONSTRAINT `fk_bas_api_keys_bas_roles` FOREIGN KEY (role_id) REFERENCES bas_roles(id) ON DELETE RESTRICT ON UPDATE RESTRICT;")
+	engine.DB.Exec("ALTER TABLE bas_api_keys ADD FULLTEXT INDEX `search_idx` (name);")
+
+	engine.DB.Table(basmodel.EmailVerificationTable).AutoMigrate(&basmodel.EmailVerification{})
+	engine.DB.Exec("ALTER TABLE bas_email_verifications ADD CONSTRAINT `fk_bas_email_verifications_bas_users` FOREIGN KEY (user_id) REFERENCES bas_users(id) ON DELETE CASCADE ON UPDATE CASCADE;")
+
+	engine.DB.Table(basmodel.SessionTable).AutoMigrate(&basmodel.Session{})
+	engine.DB.Exec("ALTER TABLE bas_sessions ADD CONSTRAINT `fk_bas_sessions_bas_users` FOREIGN KEY (user_id) REFERENCES bas_users(id) ON DELETE CASCADE ON UPDATE CASCADE;")
+	engine.DB.Exec("ALTER TABLE bas_sessions ADD FULLTEXT INDEX `search_idx` (user_agent);")
+
+	engine.DB.Table(basmodel.GrantTable).AutoMigrate(&basmodel.Grant{})
+	engine.DB.Exec("ALTER TABLE bas_grants ADD CONSTRAINT `fk_bas_grants_bas_users` FOREIGN KEY (user_id) REFERENCES bas_users(id) ON DELETE CASCADE ON UPDATE CASCADE;")
+	engine.DB.Exec("ALTER TABLE bas_grants ADD CONSTRAINT `fk_bas_grants_grantor_bas_users` FOREIGN KEY (grantor_id) REFERENCES bas_users(id) ON DELETE RESTRICT ON UPDATE RESTRICT;")
+	engine.DB.Exec("ALTER TABLE bas_grants ADD FULLTEXT INDEX `search_idx` (reason);")
+
+	engine.DB.Table(basmodel.ViewTable).AutoMigrate(&basmodel.View{})
+	engine.DB.Exec("ALTER TABLE bas_views ADD CONSTRAINT `fk_bas_views_bas_users` FOREIGN KEY (user_id) REFERENCES bas_users(id) ON DELETE CASCADE ON UPDATE CASCADE;")
+	engine.DB.Exec("ALTER TABLE bas_views ADD CONSTRAINT `fk_bas_views_bas_roles` FOREIGN KEY (role_id) REFERENCES bas_roles(id) ON DELETE SET NULL ON UPDATE CASCADE;")
+	engine.DB.Exec("ALTER TABLE bas_views ADD FULLTEXT INDEX `search_idx` (name);")
+
+	engine.DB.Table(basmodel.PermissionEventTable).AutoMigrate(&basmodel.PermissionEvent{})
 
 	engine.ActivityDB.Table(basmodel.ActivityTable).AutoMigrate(&basmodel.Activity{})
+	engine.ActivityDB.Exec("ALTER TABLE bas_activities ADD FULLTEXT INDEX `search_idx` (event, username, uri, `before`, `after`, actor_username);")
 
 	engine.DB.Table(basmodel.CityTable).AutoMigrate(&basmodel.City{})
+	engine.DB.Exec("ALTER TABLE bas_cities ADD FULLTEXT INDEX `search_idx` (city, notes);")
+	uniqueAlive(engine, basmodel.CityTable, "city")
 
 	// Subscriber Domain
 	engine.DB.Table(submodel.AccountTable).AutoMigrate(&submodel.Account{})
+	engine.DB.Exec("ALTER TABLE sub_accounts ADD FULLTEXT INDEX `search_idx` (name_en, name_ku);")
+	uniqueAlive(engine, submodel.AccountTable, "name_en")
+	uniqueAlive(engine, submodel.AccountTable, "name_ku")
 	engine.DB.Exec("ALTER TABLE sub_accounts ADD CONSTRAINT `fk_sub_accounts_self` FOREIGN KEY (parent_id) REFERENCES sub_accounts(id) ON DELETE RESTRICT ON UPDATE RESTRICT;")
 
 	engine.DB.Table(submodel.PhoneTable).AutoMigrate(&submodel.Phone{})
+	engine.DB.Exec("ALTER TABLE sub_phones ADD FULLTEXT INDEX `search_idx` (notes);")
+	uniqueAlive(engine, submodel.PhoneTable, "phone")
 
 	engine.DB.Table(submodel.AccountPhoneTable).AutoMigrate(&submodel.AccountPhone{})
 	engine.DB.Exec("ALTER TABLE sub_account_phones ADD CONSTRAINT `fk_sub_accounts_phones_sub_accounts` FOREIGN KEY (account_id) REFERENCES sub_accounts(id) ON DELETE RESTRICT ON UPDATE RESTRICT;")
@@ -35,9 +106,34 @@ func Migrate(engine *core.Engine) {
 
 	// Notification Domain
 	engine.DB.Table(notmodel.MessageTable).AutoMigrate(&notmodel.Message{})
+	engine.DB.Exec("ALTER TABLE not_messages ADD FULLTEXT INDEX `search_idx` (title, message);")
 	engine.DB.Exec("ALTER TABLE not_messages ADD CONSTRAINT `fk_not_messages_created_by_bas_users` FOREIGN KEY (created_by) REFERENCES bas_users(id) ON DELETE RESTRICT ON UPDATE RESTRICT;")
 	engine.DB.Exec("ALTER TABLE not_messages ADD CONSTRAINT `fk_not_messages_recipient_id_bas_users` FOREIGN KEY (recipient_id) REFERENCES bas_users(id) ON DELETE RESTRICT ON UPDATE RESTRICT;")
 
 	// Segment Domain
 	engine.DB.Table(segmodel.CompanyTable).AutoMigrate(&segmodel.Company{})
+	engine.DB.Exec("ALTER TABLE seg_companies ADD FULLTEXT INDEX `search_idx` (name);")
+	uniqueAlive(engine, segmodel.CompanyTable, "name")
+
+	// memberships of the users in the companies need both domains
+	engine.DB.Table(basmodel.UserCompanyTable).AutoMigrate(&basmodel.UserCompany{})
+	engine.DB.Exec("ALTER TABLE bas_user_companies ADD CONSTRAINT `fk_bas_user_companies_bas_users` FOREIGN KEY (user_id) REFERENCES bas_users(id) ON DELETE CASCADE ON UPDATE CASCADE;")
+	engine.DB.Exec("ALTER TABLE bas_user_companies ADD CONSTRAINT `fk_bas_user_companies_seg_companies` FOREIGN KEY (company_id) REFERENCES seg_companies(id) ON DELETE CASCADE ON UPDATE CASCADE;")
+
+	// the users, accounts and api keys before the companies are attached to the default company,
+	// otherwise after upgrading they resolve to the company 0 and lose their rows
+	engine.DB.Exec("INSERT IGNORE INTO seg_companies (id, name, created_at, updated_at) VALUES (?, 'Main Company', NOW(), NOW());", consts.DefaultCompanyID)
+	engine.DB.Exec("INSERT IGNORE INTO bas_user_companies (user_id, company_id, created_at) SELECT bas_users.id, seg_companies.id, NOW() FROM bas_users INNER JOIN seg_companies ON seg_companies.id = ? WHERE bas_users.id NOT IN (SELECT user_id FROM bas_user_companies);", consts.DefaultCompanyID)
+	engine.DB.Exec("UPDATE sub_accounts SET company_id = ? WHERE (company_id IS NULL OR company_id = 0) AND EXISTS (SELECT 1 FROM seg_companies WHERE id = ?);", consts.DefaultCompanyID, consts.DefaultCompanyID)
+	engine.DB.Exec("UPDATE bas_api_keys SET company_id = ? WHERE company_id IS NULL AND EXISTS (SELECT 1 FROM seg_companies WHERE id = ?);", consts.DefaultCompanyID, consts.DefaultCompanyID)
+}
+
+// uniqueAlive makes the column unique between the rows which are not soft deleted, so the values
+// of the deleted rows can be created again. The alive is 1 for the rows which are not deleted and
+// NULL for the deleted ones, the unique index ignores the NULLs. The old unique index of the
+// column is dropped after the new one is created
+func uniqueAlive(engine *core.Engine, table, col string) {
+	engine.DB.Exec(fmt.Sprintf("ALTER TABLE %v ADD COLUMN alive TINYINT AS (IF(deleted_at IS NULL, 1, NULL)) VIRTUAL;", table))
+	engine.DB.Exec(fmt.Sprintf("ALTER TABLE %v ADD UNIQUE INDEX `uniqueidx_%v` (%v, alive);", table, col, col))
+	engine.DB.Exec(fmt.Sprintf("ALTER TABLE %v DROP INDEX `%v`;", table, col))
 }
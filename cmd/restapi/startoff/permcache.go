@@ -0,0 +1,33 @@
+package startoff
+
+import (
+	"omono/domain/base"
+	"omono/domain/base/basrepo"
+	"omono/domain/service"
+	"omono/internal/core"
+	"omono/pkg/glog"
+	"omono/pkg/helper/permcache"
+	"time"
+)
+
+// LoadPermissionCache initiate the cache of the users' resources, in case the backend is
+// database the invalidations are shared with the other instances via bas_permission_events
+func LoadPermissionCache(engine *core.Engine) {
+	cache := permcache.NewMemory(engine.Envs.ToDuration(base.PermissionCacheTTL) * time.Second)
+
+	switch engine.Envs[base.PermissionCacheBackend] {
+	case "", "memory":
+		service.BasAccessSetCache(cache)
+
+	case "database":
+		bus := service.ProvideBasPermissionEventService(basrepo.ProvidePermissionEventRepo(engine))
+		service.BasAccessSetCache(permcache.NewShared(cache, bus, func(err error) {
+			glog.LogError(err, "permission cache invalidation not shared")
+		}))
+		go bus.Watcher()
+
+	default:
+		glog.Fatal("PERMISSION_CACHE_BACKEND is not valid, it should be memory or database",
+			engine.Envs[base.PermissionCacheBackend])
+	}
+}
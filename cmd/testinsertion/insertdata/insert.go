@@ -11,6 +11,7 @@ func Insert(engine *core.Engine) {
 	if engine.Envs.ToBool(core.AutoMigrate) {
 		table.InsertSettings(engine)
 		table.InsertRoles(engine)
+		table.InsertCompanies(engine)
 		table.InsertAccounts(engine)
 		table.InsertUsers(engine)
 		table.InsertPhones(engine)
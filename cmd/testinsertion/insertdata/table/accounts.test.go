@@ -6,7 +6,9 @@ import (
 	"omono/domain/subscriber/enum/accounttype"
 	"omono/domain/subscriber/submodel"
 	"omono/domain/subscriber/subrepo"
+	"omono/internal/consts"
 	"omono/internal/core"
+	"omono/internal/param"
 	"omono/pkg/glog"
 	"omono/pkg/helper"
 
@@ -170,7 +172,8 @@ func InsertAccounts(engine *core.Engine) {
 	}
 
 	for _, v := range accounts {
-		if _, err := accountService.Create(v); err != nil {
+		v.CompanyID = consts.DefaultCompanyID
+		if _, err := accountService.Create(param.New(), v); err != nil {
 			glog.Fatal("error in creating accounts", err)
 		}
 	}
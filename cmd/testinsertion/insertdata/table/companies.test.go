@@ -0,0 +1,40 @@
+package table
+
+import (
+	"omono/domain/segment/segmodel"
+	"omono/domain/segment/segrepo"
+	"omono/domain/service"
+	"omono/internal/consts"
+	"omono/internal/core"
+	"omono/internal/param"
+	"omono/pkg/glog"
+
+	"gorm.io/gorm"
+)
+
+// InsertCompanies for add the default company, the accounts and users are attached to it
+func InsertCompanies(engine *core.Engine) {
+	companyRepo := segrepo.ProvideCompanyRepo(engine)
+	companyService := service.ProvideSegCompanyService(companyRepo)
+
+	companyRepo.Engine.DB.Exec("SET FOREIGN_KEY_CHECKS = 0;")
+	companyRepo.Engine.DB.Exec("TRUNCATE TABLE bas_user_companies;")
+	companyRepo.Engine.DB.Exec("TRUNCATE TABLE seg_companies;")
+	companyRepo.Engine.DB.Exec("SET FOREIGN_KEY_CHECKS = 1;")
+
+	companies := []segmodel.Company{
+		{
+			Model: gorm.Model{
+				ID: consts.DefaultCompanyID,
+			},
+			Name: "Main Company",
+		},
+	}
+
+	for _, v := range companies {
+		if _, err := companyService.Create(param.New(), v); err != nil {
+			glog.Fatal("error in creating companies", err)
+		}
+	}
+
+}
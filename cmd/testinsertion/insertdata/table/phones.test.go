@@ -5,6 +5,7 @@ import (
 	"omono/domain/subscriber/submodel"
 	"omono/domain/subscriber/subrepo"
 	"omono/internal/core"
+	"omono/internal/param"
 	"omono/pkg/glog"
 
 	"gorm.io/gorm"
@@ -56,7 +57,7 @@ func InsertPhones(engine *core.Engine) {
 	}
 
 	for _, v := range phones {
-		if _, err := phoneService.Create(v); err != nil {
+		if _, err := phoneService.Create(param.New(), v); err != nil {
 			glog.Fatal(err)
 		}
 	}
@@ -6,7 +6,7 @@ import (
 	"omono/domain/base/basrepo"
 	"omono/domain/service"
 	"omono/internal/core"
-	"omono/internal/types"
+	"omono/internal/core/corperm"
 	"omono/pkg/glog"
 
 	"gorm.io/gorm"
@@ -33,12 +33,12 @@ func InsertRoles(engine *core.Engine) {
 				ID: 1,
 			},
 			Name: "Super-Admin",
-			Resources: types.ResourceJoin([]types.Resource{
+			Resources: corperm.RulesOf(
 				base.SettingRead, base.SettingWrite, base.SettingExcel,
 				base.UserWrite, base.UserRead, base.UserExcel,
 				base.ActivitySelf,
 				base.RoleRead, base.RoleWrite, base.RoleExcel,
-			}),
+			),
 			Description: "super-admin has all privileges - do not edit",
 		},
 		{
@@ -46,12 +46,12 @@ func InsertRoles(engine *core.Engine) {
 				ID: 2,
 			},
 			Name: "Admin",
-			Resources: types.ResourceJoin([]types.Resource{
+			Resources: corperm.RulesOf(
 				base.SettingRead, base.SettingWrite, base.SettingExcel,
 				base.UserWrite, base.UserRead, base.UserExcel,
 				base.ActivitySelf,
 				base.RoleRead, base.RoleWrite, base.RoleExcel,
-			}),
+			),
 			Description: "admin has all privileges - do not edit",
 		},
 		{
@@ -59,7 +59,7 @@ func InsertRoles(engine *core.Engine) {
 				ID: 3,
 			},
 			Name:        "Cashier",
-			Resources:   types.ResourceJoin([]types.Resource{base.ActivitySelf}),
+			Resources:   corperm.RulesOf(base.ActivitySelf),
 			Description: "cashier has all privileges - after migration reset",
 		},
 		{
@@ -67,7 +67,7 @@ func InsertRoles(engine *core.Engine) {
 				ID: 4,
 			},
 			Name:        "for foreign 1",
-			Resources:   string(base.SettingRead),
+			Resources:   corperm.RulesOf(base.SettingRead),
 			Description: "for foreign 1",
 		},
 		{
@@ -75,7 +75,7 @@ func InsertRoles(engine *core.Engine) {
 				ID: 5,
 			},
 			Name:        "for update 1",
-			Resources:   string(base.SettingRead),
+			Resources:   corperm.RulesOf(base.SettingRead),
 			Description: "for update 1",
 		},
 		{
@@ -83,7 +83,7 @@ func InsertRoles(engine *core.Engine) {
 				ID: 6,
 			},
 			Name:        "for update 2",
-			Resources:   string(base.SettingRead),
+			Resources:   corperm.RulesOf(base.SettingRead),
 			Description: "for update 2",
 		},
 		{
@@ -91,7 +91,7 @@ func InsertRoles(engine *core.Engine) {
 				ID: 7,
 			},
 			Name:        "for delete 1",
-			Resources:   string(base.SettingRead),
+			Resources:   corperm.RulesOf(base.SettingRead),
 			Description: "for delete 1",
 		},
 		{
@@ -99,7 +99,7 @@ func InsertRoles(engine *core.Engine) {
 				ID: 8,
 			},
 			Name:        "for search 1",
-			Resources:   string(base.SettingRead),
+			Resources:   corperm.RulesOf(base.SettingRead),
 			Description: "searchTerm1",
 		},
 		{
@@ -107,7 +107,7 @@ func InsertRoles(engine *core.Engine) {
 				ID: 9,
 			},
 			Name:        "for search 2",
-			Resources:   string(base.SettingRead),
+			Resources:   corperm.RulesOf(base.SettingRead),
 			Description: "searchTerm1",
 		},
 		{
@@ -115,7 +115,7 @@ func InsertRoles(engine *core.Engine) {
 				ID: 10,
 			},
 			Name:        "for search 3",
-			Resources:   string(base.SettingRead),
+			Resources:   corperm.RulesOf(base.SettingRead),
 			Description: "searchTerm1",
 		},
 		{
@@ -123,7 +123,7 @@ func InsertRoles(engine *core.Engine) {
 				ID: 11,
 			},
 			Name:        "for delete 2",
-			Resources:   string(base.SettingRead),
+			Resources:   corperm.RulesOf(base.SettingRead),
 			Description: "for delete 2",
 		},
 	}
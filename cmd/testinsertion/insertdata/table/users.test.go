@@ -5,7 +5,9 @@ import (
 	"omono/domain/base/basmodel"
 	"omono/domain/base/basrepo"
 	"omono/domain/service"
+	"omono/internal/consts"
 	"omono/internal/core"
+	"omono/internal/param"
 	"omono/pkg/glog"
 
 	"github.com/syronz/dict"
@@ -50,10 +52,17 @@ func InsertUsers(engine *core.Engine) {
 		},
 	}
 
+	userCompanyService := service.ProvideBasUserCompanyService(
+		basrepo.ProvideUserCompanyRepo(engine))
+
 	for _, v := range users {
-		if _, err := userService.Create(v); err != nil {
+		if _, err := userService.Create(param.New(), v); err != nil {
 			glog.Fatal("error in creating users", err)
 		}
+
+		if err := userCompanyService.TxJoin(engine.DB, v.ID, consts.DefaultCompanyID); err != nil {
+			glog.Fatal("error in joining the users to the default company", err)
+		}
 	}
 
 }
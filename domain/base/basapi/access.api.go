@@ -0,0 +1,50 @@
+package basapi
+
+import (
+	"net/http"
+	"omono/domain/base"
+	"omono/domain/base/basterm"
+	"omono/domain/service"
+	"omono/internal/core"
+	"omono/internal/core/corterm"
+	"omono/internal/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AccessAPI for injecting access service
+type AccessAPI struct {
+	Service service.BasAccessServ
+	Engine  *core.Engine
+}
+
+// ProvideAccessAPI for access is used in wire
+func ProvideAccessAPI(c service.BasAccessServ) AccessAPI {
+	return AccessAPI{Service: c, Engine: c.Engine}
+}
+
+// Resources returns the catalog of the registered resources for the role editor
+func (p *AccessAPI) Resources(c *gin.Context) {
+	resp, params := response.NewParam(p.Engine, c, corterm.Resources, base.Domain)
+
+	resp.Record(base.ListResource)
+	resp.Status(http.StatusOK).
+		MessageT(corterm.ListOfV, corterm.Resources).
+		JSON(p.Service.Catalog(params.Lang))
+}
+
+// Permissions returns the effective resources of the caller
+func (p *AccessAPI) Permissions(c *gin.Context) {
+	resp, params := response.NewParam(p.Engine, c, corterm.Resources, base.Domain)
+
+	perms, err := p.Service.Effective(c, params.UserID)
+	if err != nil {
+		resp.Error(err).JSON()
+		return
+	}
+
+	resp.Record(base.ViewPermissions)
+	resp.Status(http.StatusOK).
+		MessageT(basterm.YourPermissions).
+		JSON(perms)
+}
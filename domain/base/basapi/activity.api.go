@@ -59,6 +59,7 @@ func (p *ActivityAPI) ListAll(c *gin.Context) {
 	resp.Record(base.AllActivity)
 	resp.Status(http.StatusOK).
 		MessageT(corterm.ListOfV, basterm.Activities).
+		Extra(params.Keyset(data["list"], p.Service.Repo.Cols)).
 		JSON(data)
 }
 
@@ -78,5 +79,6 @@ func (p *ActivityAPI) ListSelf(c *gin.Context) {
 	resp.Record(base.AllActivity)
 	resp.Status(http.StatusOK).
 		MessageT(corterm.ListOfV, basterm.Activities).
+		Extra(params.Keyset(data["list"], p.Service.Repo.Cols)).
 		JSON(data)
 }
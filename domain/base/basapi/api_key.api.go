@@ -0,0 +1,118 @@
+package basapi
+
+import (
+	"net/http"
+	"omono/domain/base"
+	"omono/domain/base/basmodel"
+	"omono/domain/base/basterm"
+	"omono/domain/service"
+	"omono/internal/core"
+	"omono/internal/core/corterm"
+	"omono/internal/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// APIKeyAPI for injecting api key service
+type APIKeyAPI struct {
+	Service service.BasAPIKeyServ
+	Engine  *core.Engine
+}
+
+// ProvideAPIKeyAPI for api key is used in wire
+func ProvideAPIKeyAPI(c service.BasAPIKeyServ) APIKeyAPI {
+	return APIKeyAPI{Service: c, Engine: c.Engine}
+}
+
+// FindByID is used for fetch an api key by it's id
+func (p *APIKeyAPI) FindByID(c *gin.Context) {
+	resp, params := response.NewParam(p.Engine, c, basmodel.APIKeyTable, base.Domain)
+	var err error
+	var apiKey basmodel.APIKey
+	var id uint
+
+	if id, err = resp.GetID(c.Param("apiKeyID"), "E1053843", basterm.APIKey); err != nil {
+		return
+	}
+
+	if apiKey, err = p.Service.FindByID(params, id); err != nil {
+		resp.Error(err).JSON()
+		return
+	}
+
+	resp.Record(base.ViewAPIKey)
+	resp.Status(http.StatusOK).
+		MessageT(corterm.VInfo, basterm.APIKey).
+		JSON(apiKey)
+}
+
+// List of api keys
+func (p *APIKeyAPI) List(c *gin.Context) {
+	resp, params := response.NewParam(p.Engine, c, basmodel.APIKeyTable, base.Domain)
+
+	data := make(map[string]interface{})
+	var err error
+
+	if params.Aggregated() {
+		data["list"], err = p.Service.Aggregate(params)
+	} else {
+		data["list"], data["count"], err = p.Service.List(params)
+	}
+
+	if err != nil {
+		resp.Error(err).JSON()
+		return
+	}
+
+	resp.Record(base.ListAPIKey)
+	resp.Status(http.StatusOK).
+		MessageT(corterm.ListOfV, basterm.APIKeys).
+		Extra(params.Keyset(data["list"], p.Service.Repo.Cols)).
+		JSON(data)
+}
+
+// Create api key, the key is just shown in this response
+func (p *APIKeyAPI) Create(c *gin.Context) {
+	resp, params := response.NewParam(p.Engine, c, basmodel.APIKeyTable, base.Domain)
+	var apiKey, createdAPIKey basmodel.APIKey
+	var err error
+
+	if err = resp.Bind(&apiKey, "E1034948", base.Domain, basterm.APIKey); err != nil {
+		return
+	}
+
+	if createdAPIKey, err = p.Service.Create(params, apiKey); err != nil {
+		resp.Error(err).JSON()
+		return
+	}
+
+	// the raw key shouldn't be saved inside the activities
+	recorded := createdAPIKey
+	recorded.Secret = ""
+	resp.RecordCreate(base.CreateAPIKey, recorded)
+	resp.Status(http.StatusOK).
+		MessageT(basterm.APIKeyCreatedKeepTheKeySafe).
+		JSON(createdAPIKey)
+}
+
+// Revoke api key
+func (p *APIKeyAPI) Revoke(c *gin.Context) {
+	resp, params := response.NewParam(p.Engine, c, basmodel.APIKeyTable, base.Domain)
+	var err error
+	var apiKey basmodel.APIKey
+	var id uint
+
+	if id, err = resp.GetID(c.Param("apiKeyID"), "E1028321", basterm.APIKey); err != nil {
+		return
+	}
+
+	if apiKey, err = p.Service.Revoke(params, id); err != nil {
+		resp.Error(err).JSON()
+		return
+	}
+
+	resp.Record(base.RevokeAPIKey, apiKey)
+	resp.Status(http.StatusOK).
+		MessageT(basterm.APIKeyRevoked).
+		JSON()
+}
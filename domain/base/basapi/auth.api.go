@@ -11,10 +11,8 @@ import (
 	"omono/internal/core/corterm"
 	"omono/internal/param"
 	"omono/internal/response"
-	"omono/pkg/helper"
-	"strconv"
+	"omono/internal/types"
 
-	"github.com/syronz/dict"
 	"github.com/syronz/limberr"
 
 	"github.com/gin-gonic/gin"
@@ -40,10 +38,12 @@ func (p *AuthAPI) Login(c *gin.Context) {
 		return
 	}
 
+	auth.IP = c.ClientIP()
+	auth.UserAgent = c.Request.UserAgent()
 	user, err := p.Service.Login(auth, params)
 	if err != nil {
 		resp.Error(err).JSON()
-		resp.Record(base.LoginFailed, auth.Username, len(auth.Password))
+		recordLoginFailure(resp, err, base.LoginFailed, auth.Username, len(auth.Password))
 		return
 	}
 
@@ -51,11 +51,71 @@ func (p *AuthAPI) Login(c *gin.Context) {
 	tmpUser.Extra = nil
 
 	resp.Record(base.BasLogin, tmpUser)
+
+	if _, ok := user.Extra.(basmodel.MFAChallenge); ok {
+		resp.Status(http.StatusOK).
+			MessageT(basterm.EnterTwoFactorCode).
+			JSON(user)
+		return
+	}
+
 	resp.Status(http.StatusOK).
 		Message(basterm.UserLogedInSuccessfully).
 		JSON(user)
 }
 
+// LoginMFA complete the login by checking the two-factor code
+func (p *AuthAPI) LoginMFA(c *gin.Context) {
+	var auth basmodel.Auth
+	resp := response.New(p.Engine, c, base.Domain)
+
+	if err := resp.Bind(&auth, "E1076787", base.Domain, basterm.TwoFactorCode); err != nil {
+		return
+	}
+
+	auth.IP = c.ClientIP()
+	auth.UserAgent = c.Request.UserAgent()
+	user, err := p.Service.LoginMFA(auth)
+	if err != nil {
+		resp.Error(err).JSON()
+		recordLoginFailure(resp, err, base.LoginMFAFailed)
+		return
+	}
+
+	tmpUser := user
+	tmpUser.Extra = nil
+
+	resp.Record(base.BasLoginMFA, tmpUser)
+	resp.Status(http.StatusOK).
+		Message(basterm.UserLogedInSuccessfully).
+		JSON(user)
+}
+
+// Refresh issue a new access token and rotate the refresh token
+func (p *AuthAPI) Refresh(c *gin.Context) {
+	var auth basmodel.Auth
+	resp := response.New(p.Engine, c, base.Domain)
+
+	if err := resp.Bind(&auth, "E1028444", base.Domain, basterm.RefreshToken); err != nil {
+		return
+	}
+
+	user, err := p.Service.Refresh(auth)
+	if err != nil {
+		resp.Error(err).JSON()
+		resp.Record(base.RefreshFailed)
+		return
+	}
+
+	tmpUser := user
+	tmpUser.Extra = nil
+
+	resp.Record(base.BasRefresh, tmpUser)
+	resp.Status(http.StatusOK).
+		MessageT(basterm.TokenRefreshedSuccessfully).
+		JSON(user)
+}
+
 // Profile returns the user's information
 func (p *AuthAPI) Profile(c *gin.Context) {
 	resp, params := response.NewParam(p.Engine, c, basmodel.UserTable, base.Domain)
@@ -73,73 +133,81 @@ func (p *AuthAPI) Profile(c *gin.Context) {
 		JSON(user)
 }
 
-// Logout will erase the resources from access.Cache
+// Logout will revoke the tokens and erase the resources from access.Cache
 func (p *AuthAPI) Logout(c *gin.Context) {
 	resp := response.New(p.Engine, c, base.Domain)
 	params := param.Get(c, p.Engine, basterm.Users)
-	p.Service.Logout(params)
+	if err := p.Service.Logout(params, c.GetString("JTI")); err != nil {
+		resp.Error(err).JSON()
+		return
+	}
+
 	resp.Record(base.BasLogout)
 	resp.Status(http.StatusOK).
 		Message("user logged out").
 		JSON()
 }
 
-// TemporaryToken is used for creating temporary access token for download excel and etc
-func (p *AuthAPI) TemporaryToken(c *gin.Context) {
-	// var auth basmodel.Auth
-	resp := response.New(p.Engine, c, base.Domain)
+// Impersonate issue a token for acting as another user, activities of the token are recorded
+// with the real actor
+func (p *AuthAPI) Impersonate(c *gin.Context) {
+	resp, params := response.NewParam(p.Engine, c, basmodel.UserTable, base.Domain)
+	var err error
+	var user basmodel.User
+	var id uint
 
-	params := param.Get(c, p.Engine, "users")
+	if id, err = resp.GetID(c.Param("userID"), "E1013803", basterm.User); err != nil {
+		return
+	}
 
-	tmpKey, err := p.Service.TemporaryToken(params)
-	if err != nil {
-		resp.Status(http.StatusInternalServerError).Error(corerr.YouDontHavePermission).JSON()
+	if user, err = p.Service.Impersonate(params, id); err != nil {
+		resp.Error(err).JSON()
 		return
 	}
 
-	resp.Status(http.StatusOK).
-		Message(corterm.TemporaryToken).
-		JSON(tmpKey)
+	tmpUser := user
+	tmpUser.Extra = nil
 
+	resp.Record(base.Impersonate, tmpUser)
+	resp.Status(http.StatusOK).
+		MessageT(basterm.ImpersonatingV, user.Username).
+		JSON(user)
 }
 
-// TemporaryTokenHour is used for creating temporary access token for download excel and etc
-func (p *AuthAPI) TemporaryTokenHour(c *gin.Context) {
-	// var auth basmodel.Auth
-	resp := response.New(p.Engine, c, base.Domain)
-
-	var hour int
+// SwitchCompany issue new tokens for the company, the zero company activates the cross tenant
+// mode of the super admin
+func (p *AuthAPI) SwitchCompany(c *gin.Context) {
+	resp, params := response.NewParam(p.Engine, c, basmodel.UserTable, base.Domain)
 	var err error
-	if hour, err = strconv.Atoi(c.Param("hour")); err != nil {
-		err := limberr.New("hour is not a number", "E4269633").
-			Domain(base.Domain).
-			Message(corerr.VisNotValid, "hour").
-			Custom(corerr.ForbiddenErr).Build()
-		resp.Error(err).JSON()
+	var user basmodel.User
+	var id uint
+
+	if id, err = resp.GetID(c.Param("companyID"), "E1041892", basterm.Company); err != nil {
 		return
 	}
 
-	if p.Engine.Envs.ToInt(base.MaxHourTemporaryToken) < hour {
-		err := limberr.New("hour exceeds the limit, choose smaller number", "E4285615").
-			Domain(base.Domain).
-			Message(corerr.VisNotValid, "hour").
-			Custom(corerr.ForbiddenErr).Build()
+	if user, err = p.Service.SwitchCompany(params, id, c.GetString("JTI")); err != nil {
 		resp.Error(err).JSON()
 		return
 	}
 
-	lang := dict.Lang(c.Param("lang"))
+	tmpUser := user
+	tmpUser.Extra = nil
 
-	if ok, _ := helper.Includes(dict.Langs, lang); !ok {
-		err := limberr.New("language is not accepted", "E4244811").
-			Domain(base.Domain).
-			Message(corerr.AcceptedValueForVareV, dict.R(corterm.Language), dict.Langs).
-			Custom(corerr.ValidationFailedErr).Build()
-		resp.Error(err).JSON()
-		return
-	}
+	resp.Record(base.SwitchCompany, tmpUser)
+	resp.Status(http.StatusOK).
+		MessageT(basterm.CompanySwitched).
+		JSON(user)
+}
+
+// TemporaryToken is used for creating temporary access token for download excel and etc
+func (p *AuthAPI) TemporaryToken(c *gin.Context) {
+	// var auth basmodel.Auth
+	resp := response.New(p.Engine, c, base.Domain)
+
+	params := param.Get(c, p.Engine, "users")
 
-	tmpKey, err := p.Service.TemporaryTokenHour(hour, lang)
+	tmpKey, err := p.Service.TemporaryToken(params)
 	if err != nil {
 		resp.Status(http.StatusInternalServerError).Error(corerr.YouDontHavePermission).JSON()
 		return
@@ -148,6 +216,7 @@ func (p *AuthAPI) TemporaryTokenHour(c *gin.Context) {
 	resp.Status(http.StatusOK).
 		Message(corterm.TemporaryToken).
 		JSON(tmpKey)
+
 }
 
 // Register a user
@@ -171,5 +240,21 @@ func (p *AuthAPI) Register(c *gin.Context) {
 	resp.RecordCreate(base.Register, createdUser)
 	resp.Status(http.StatusOK).
 		MessageT(basterm.UserRegisteredSuccessfully).
-		JSON(user)
+		JSON(createdUser)
+}
+
+// JWKS publish the public keys for verifying the tokens by other services, the response is
+// in the standard format of RFC 7517 and it is not wrapped by the response package
+func (p *AuthAPI) JWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, p.Engine.JWTKeys.JWKS())
+}
+
+// recordLoginFailure save the failure or the lockout in the activities
+func recordLoginFailure(resp *response.Response, err error, ev types.Event, data ...interface{}) {
+	if limberr.GetCustom(err) == corerr.TooManyRequestsErr {
+		ev = base.LoginLocked
+	}
+
+	resp.Record(ev, data...)
 }
@@ -43,7 +43,7 @@ func (p *CityAPI) FindByID(c *gin.Context) {
 	}
 
 	resp.Record(base.ViewCity)
-	resp.Status(http.StatusOK).
+	resp.SetETag(city.Model).Status(http.StatusOK).
 		MessageT(corterm.VInfo, basterm.City).
 		JSON(city)
 }
@@ -55,7 +55,13 @@ func (p *CityAPI) List(c *gin.Context) {
 	data := make(map[string]interface{})
 	var err error
 
-	if data["list"], data["count"], err = p.Service.List(params); err != nil {
+	if params.Aggregated() {
+		data["list"], err = p.Service.Aggregate(params)
+	} else {
+		data["list"], data["count"], err = p.Service.List(params)
+	}
+
+	if err != nil {
 		resp.Error(err).JSON()
 		return
 	}
@@ -63,6 +69,7 @@ func (p *CityAPI) List(c *gin.Context) {
 	resp.Record(base.ListCity)
 	resp.Status(http.StatusOK).
 		MessageT(corterm.ListOfV, basterm.Cities).
+		Extra(params.Keyset(data["list"], p.Service.Repo.Cols)).
 		JSON(data)
 }
 
@@ -82,7 +89,7 @@ func (p *CityAPI) Create(c *gin.Context) {
 	}
 
 	resp.RecordCreate(base.CreateCity, city)
-	resp.Status(http.StatusOK).
+	resp.SetETag(createdCity.Model).Status(http.StatusOK).
 		MessageT(corterm.VCreatedSuccessfully, basterm.City).
 		JSON(createdCity)
 }
@@ -99,19 +106,29 @@ func (p *CityAPI) Update(c *gin.Context) {
 		return
 	}
 
-	if err = resp.Bind(&city, "E1093884", base.Domain, basterm.City); err != nil {
+	if cityBefore, err = p.Service.FindByID(id); err != nil {
+		resp.Error(err).JSON()
+		return
+	}
+
+	if err = resp.IfMatch(cityBefore.Model, cityBefore, "E1038732", basterm.City); err != nil {
+		return
+	}
+
+	if err = resp.BindUpdate(&city, cityBefore, "E1093884", base.Domain, basterm.City); err != nil {
 		return
 	}
 
 	city.ID = id
 	city.CreatedAt = cityBefore.CreatedAt
+	city.UpdatedAt = cityBefore.UpdatedAt
 	if cityUpdated, cityBefore, err = p.Service.Save(city); err != nil {
 		resp.Error(err).JSON()
 		return
 	}
 
-	resp.Record(base.UpdateCity, cityBefore, city)
-	resp.Status(http.StatusOK).
+	resp.Record(base.UpdateCity, cityBefore, cityUpdated)
+	resp.SetETag(cityUpdated.Model).Status(http.StatusOK).
 		MessageT(corterm.VUpdatedSuccessfully, basterm.City).
 		JSON(cityUpdated)
 }
@@ -138,6 +155,28 @@ func (p *CityAPI) Delete(c *gin.Context) {
 		JSON()
 }
 
+// Restore the deleted city
+func (p *CityAPI) Restore(c *gin.Context) {
+	resp := response.New(p.Engine, c, base.Domain)
+	var err error
+	var city basmodel.City
+	var id uint
+
+	if id, err = resp.GetID(c.Param("cityID"), "E1011103", basterm.City); err != nil {
+		return
+	}
+
+	if city, err = p.Service.Restore(id); err != nil {
+		resp.Error(err).JSON()
+		return
+	}
+
+	resp.Record(base.RestoreCity, city)
+	resp.Status(http.StatusOK).
+		MessageT(corterm.VRestoredSuccessfully, basterm.City).
+		JSON(city)
+}
+
 // Excel generate excel files based on search
 func (p *CityAPI) Excel(c *gin.Context) {
 	resp, params := response.NewParam(p.Engine, c, basterm.Cities, base.Domain)
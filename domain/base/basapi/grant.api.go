@@ -0,0 +1,153 @@
+package basapi
+
+import (
+	"net/http"
+	"omono/domain/base"
+	"omono/domain/base/basmodel"
+	"omono/domain/base/basterm"
+	"omono/domain/service"
+	"omono/internal/core"
+	"omono/internal/core/corterm"
+	"omono/internal/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GrantAPI for injecting grant service
+type GrantAPI struct {
+	Service service.BasGrantServ
+	Engine  *core.Engine
+}
+
+// ProvideGrantAPI for grant is used in wire
+func ProvideGrantAPI(c service.BasGrantServ) GrantAPI {
+	return GrantAPI{Service: c, Engine: c.Engine}
+}
+
+// FindByID is used for fetch a grant by it's id
+func (p *GrantAPI) FindByID(c *gin.Context) {
+	resp, params := response.NewParam(p.Engine, c, basmodel.GrantTable, base.Domain)
+	var err error
+	var grant basmodel.Grant
+	var id uint
+
+	if id, err = resp.GetID(c.Param("grantID"), "E1051656", basterm.Grant); err != nil {
+		return
+	}
+
+	if grant, err = p.Service.FindByID(params, id); err != nil {
+		resp.Error(err).JSON()
+		return
+	}
+
+	resp.Record(base.ViewGrant)
+	resp.Status(http.StatusOK).
+		MessageT(corterm.VInfo, basterm.Grant).
+		JSON(grant)
+}
+
+// List of grants
+func (p *GrantAPI) List(c *gin.Context) {
+	resp, params := response.NewParam(p.Engine, c, basmodel.GrantTable, base.Domain)
+
+	data := make(map[string]interface{})
+	var err error
+
+	if params.Aggregated() {
+		data["list"], err = p.Service.Aggregate(params)
+	} else {
+		data["list"], data["count"], err = p.Service.List(params)
+	}
+
+	if err != nil {
+		resp.Error(err).JSON()
+		return
+	}
+
+	resp.Record(base.ListGrant)
+	resp.Status(http.StatusOK).
+		MessageT(corterm.ListOfV, basterm.Grants).
+		Extra(params.Keyset(data["list"], p.Service.Repo.Cols)).
+		JSON(data)
+}
+
+// ListSelf returns the active and upcoming grants of the current user
+func (p *GrantAPI) ListSelf(c *gin.Context) {
+	resp, params := response.NewParam(p.Engine, c, basmodel.GrantTable, base.Domain)
+	var err error
+	var grants []basmodel.Grant
+
+	if grants, err = p.Service.ListByUser(params.UserID); err != nil {
+		resp.Error(err).JSON()
+		return
+	}
+
+	resp.Status(http.StatusOK).
+		MessageT(basterm.YourGrants).
+		JSON(grants)
+}
+
+// ListByUser returns the active and upcoming grants of a user
+func (p *GrantAPI) ListByUser(c *gin.Context) {
+	resp, params := response.NewParam(p.Engine, c, basmodel.GrantTable, base.Domain)
+	var err error
+	var grants []basmodel.Grant
+	var userID uint
+
+	if userID, err = resp.GetID(c.Param("userID"), "E1082221", basterm.User); err != nil {
+		return
+	}
+
+	if grants, err = p.Service.ListOfUser(params, userID); err != nil {
+		resp.Error(err).JSON()
+		return
+	}
+
+	resp.Record(base.ListUserGrant)
+	resp.Status(http.StatusOK).
+		MessageT(basterm.GrantsOfUser).
+		JSON(grants)
+}
+
+// Create grant
+func (p *GrantAPI) Create(c *gin.Context) {
+	resp, params := response.NewParam(p.Engine, c, basmodel.GrantTable, base.Domain)
+	var grant, createdGrant basmodel.Grant
+	var err error
+
+	if err = resp.Bind(&grant, "E1036574", base.Domain, basterm.Grant); err != nil {
+		return
+	}
+
+	if createdGrant, err = p.Service.Create(params, grant); err != nil {
+		resp.Error(err).JSON()
+		return
+	}
+
+	resp.RecordCreate(base.CreateGrant, createdGrant)
+	resp.Status(http.StatusOK).
+		MessageT(corterm.VCreatedSuccessfully, basterm.Grant).
+		JSON(createdGrant)
+}
+
+// Revoke grant
+func (p *GrantAPI) Revoke(c *gin.Context) {
+	resp, params := response.NewParam(p.Engine, c, basmodel.GrantTable, base.Domain)
+	var err error
+	var grant basmodel.Grant
+	var id uint
+
+	if id, err = resp.GetID(c.Param("grantID"), "E1052902", basterm.Grant); err != nil {
+		return
+	}
+
+	if grant, err = p.Service.Revoke(params, id); err != nil {
+		resp.Error(err).JSON()
+		return
+	}
+
+	resp.Record(base.RevokeGrant, grant)
+	resp.Status(http.StatusOK).
+		MessageT(basterm.GrantRevoked).
+		JSON()
+}
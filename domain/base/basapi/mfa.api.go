@@ -0,0 +1,81 @@
+package basapi
+
+import (
+	"net/http"
+	"omono/domain/base"
+	"omono/domain/base/basmodel"
+	"omono/domain/base/basterm"
+	"omono/domain/service"
+	"omono/internal/core"
+	"omono/internal/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MFAAPI for injecting mfa service
+type MFAAPI struct {
+	Service service.BasMFAServ
+	Engine  *core.Engine
+}
+
+// ProvideMFAAPI for mfa is used in wire
+func ProvideMFAAPI(c service.BasMFAServ) MFAAPI {
+	return MFAAPI{Service: c, Engine: c.Engine}
+}
+
+// Enroll generate the secret and the provisioning uri for authenticator apps
+func (p *MFAAPI) Enroll(c *gin.Context) {
+	resp, params := response.NewParam(p.Engine, c, basmodel.MFATable, base.Domain)
+
+	enroll, err := p.Service.Enroll(params)
+	if err != nil {
+		resp.Error(err).JSON()
+		return
+	}
+
+	resp.Record(base.MFAEnroll)
+	resp.Status(http.StatusOK).
+		MessageT(basterm.ScanTheQRCodeAndConfirm).
+		JSON(enroll)
+}
+
+// Confirm enable the two-factor and return the recovery codes
+func (p *MFAAPI) Confirm(c *gin.Context) {
+	var auth basmodel.Auth
+	resp, params := response.NewParam(p.Engine, c, basmodel.MFATable, base.Domain)
+
+	if err := resp.Bind(&auth, "E1015799", base.Domain, basterm.TwoFactorCode); err != nil {
+		return
+	}
+
+	codes, err := p.Service.Confirm(params, auth.Code)
+	if err != nil {
+		resp.Error(err).JSON()
+		return
+	}
+
+	resp.Record(base.MFAConfirm)
+	resp.Status(http.StatusOK).
+		MessageT(basterm.KeepRecoveryCodesSafe).
+		JSON(codes)
+}
+
+// Disable remove the two-factor after checking a code
+func (p *MFAAPI) Disable(c *gin.Context) {
+	var auth basmodel.Auth
+	resp, params := response.NewParam(p.Engine, c, basmodel.MFATable, base.Domain)
+
+	if err := resp.Bind(&auth, "E1066454", base.Domain, basterm.TwoFactorCode); err != nil {
+		return
+	}
+
+	if err := p.Service.Disable(params, auth.Code); err != nil {
+		resp.Error(err).JSON()
+		return
+	}
+
+	resp.Record(base.MFADisable)
+	resp.Status(http.StatusOK).
+		MessageT(basterm.TwoFactorDisabled).
+		JSON()
+}
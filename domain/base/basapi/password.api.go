@@ -0,0 +1,87 @@
+package basapi
+
+import (
+	"net/http"
+	"omono/domain/base"
+	"omono/domain/base/basmodel"
+	"omono/domain/base/basterm"
+	"omono/domain/service"
+	"omono/internal/core"
+	"omono/internal/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PasswordAPI for injecting password service
+type PasswordAPI struct {
+	Service service.BasPasswordServ
+	Engine  *core.Engine
+}
+
+// ProvidePasswordAPI for password is used in wire
+func ProvidePasswordAPI(c service.BasPasswordServ) PasswordAPI {
+	return PasswordAPI{Service: c, Engine: c.Engine}
+}
+
+// Change the password of the logged in user
+func (p *PasswordAPI) Change(c *gin.Context) {
+	var pass basmodel.Password
+	resp, params := response.NewParam(p.Engine, c, basmodel.UserTable, base.Domain)
+
+	if err := resp.Bind(&pass, "E1029954", base.Domain, basterm.NewPassword); err != nil {
+		return
+	}
+
+	if err := p.Service.Change(params, pass); err != nil {
+		resp.Error(err).JSON()
+		resp.Record(base.PasswordChangeFailed)
+		return
+	}
+
+	resp.Record(base.PasswordChange)
+	resp.Status(http.StatusOK).
+		MessageT(basterm.PasswordChangedSuccessfully).
+		JSON()
+}
+
+// Forgot send the reset token to the email
+func (p *PasswordAPI) Forgot(c *gin.Context) {
+	var pass basmodel.Password
+	resp := response.New(p.Engine, c, base.Domain)
+
+	if err := resp.Bind(&pass, "E1023718", base.Domain, basterm.PasswordReset); err != nil {
+		return
+	}
+
+	if err := p.Service.Forgot(pass); err != nil {
+		resp.Error(err).JSON()
+		return
+	}
+
+	resp.Record(base.PasswordForgot, pass.Email)
+	resp.Status(http.StatusOK).
+		MessageT(basterm.ResetTokenSentIfEmailExists).
+		JSON()
+}
+
+// Reset the password via the token
+func (p *PasswordAPI) Reset(c *gin.Context) {
+	var pass basmodel.Password
+	resp := response.New(p.Engine, c, base.Domain)
+
+	if err := resp.Bind(&pass, "E1086716", base.Domain, basterm.PasswordReset); err != nil {
+		return
+	}
+
+	user, err := p.Service.Reset(pass)
+	if err != nil {
+		resp.Error(err).JSON()
+		resp.Record(base.PasswordResetFailed)
+		return
+	}
+
+	resp.Record(base.PasswordReset, user)
+	resp.Status(http.StatusOK).
+		MessageT(basterm.PasswordResetSuccessfully).
+		JSON()
+}
@@ -0,0 +1,84 @@
+package basapi
+
+import (
+	"net/http"
+	"omono/domain/base"
+	"omono/domain/base/basmodel"
+	"omono/domain/base/basterm"
+	"omono/domain/service"
+	"omono/internal/core"
+	"omono/internal/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegistrationAPI for injecting registration service
+type RegistrationAPI struct {
+	Service service.BasRegistrationServ
+	Engine  *core.Engine
+}
+
+// ProvideRegistrationAPI for registration is used in wire
+func ProvideRegistrationAPI(c service.BasRegistrationServ) RegistrationAPI {
+	return RegistrationAPI{Service: c, Engine: c.Engine}
+}
+
+// Verify the email via the token inside the link, it is a GET for being opened from the email
+func (p *RegistrationAPI) Verify(c *gin.Context) {
+	resp := response.New(p.Engine, c, base.Domain)
+	verification := basmodel.Verification{Token: c.Query("token")}
+
+	user, err := p.Service.Verify(verification)
+	if err != nil {
+		resp.Error(err).JSON()
+		resp.Record(base.VerifyEmailFailed)
+		return
+	}
+
+	resp.Record(base.VerifyEmail, user)
+	resp.Status(http.StatusOK).
+		MessageT(basterm.EmailVerifiedSuccessfully).
+		JSON(user)
+}
+
+// Resend the verification link to the email
+func (p *RegistrationAPI) Resend(c *gin.Context) {
+	var verification basmodel.Verification
+	resp := response.New(p.Engine, c, base.Domain)
+
+	if err := resp.Bind(&verification, "E1053794", base.Domain,
+		basterm.EmailVerification); err != nil {
+		return
+	}
+
+	if err := p.Service.Resend(verification); err != nil {
+		resp.Error(err).JSON()
+		return
+	}
+
+	resp.Status(http.StatusOK).
+		MessageT(basterm.VerificationSentIfEmailIsPending).
+		JSON()
+}
+
+// Approve the registered user
+func (p *RegistrationAPI) Approve(c *gin.Context) {
+	resp := response.New(p.Engine, c, base.Domain)
+	var err error
+	var user basmodel.User
+	var id uint
+
+	if id, err = resp.GetID(c.Param("userID"), "E1063035", basterm.User); err != nil {
+		return
+	}
+
+	if user, err = p.Service.Approve(id); err != nil {
+		resp.Error(err).JSON()
+		return
+	}
+
+	resp.Record(base.ApproveUser, user)
+	resp.Status(http.StatusOK).
+		MessageT(basterm.UserApprovedSuccessfully).
+		JSON(user)
+}
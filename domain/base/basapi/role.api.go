@@ -45,7 +45,7 @@ func (p *RoleAPI) FindByID(c *gin.Context) {
 	}
 
 	resp.Record(base.ViewRole)
-	resp.Status(http.StatusOK).
+	resp.SetETag(role.Model).Status(http.StatusOK).
 		MessageT(corterm.VInfo, basterm.Role).
 		JSON(role)
 }
@@ -57,7 +57,13 @@ func (p *RoleAPI) List(c *gin.Context) {
 	data := make(map[string]interface{})
 	var err error
 
-	if data["list"], data["count"], err = p.Service.List(params); err != nil {
+	if params.Aggregated() {
+		data["list"], err = p.Service.Aggregate(params)
+	} else {
+		data["list"], data["count"], err = p.Service.List(params)
+	}
+
+	if err != nil {
 		resp.Error(err).JSON()
 		return
 	}
@@ -65,6 +71,7 @@ func (p *RoleAPI) List(c *gin.Context) {
 	resp.Record(base.ListRole)
 	resp.Status(http.StatusOK).
 		MessageT(corterm.ListOfV, basterm.Roles).
+		Extra(params.Keyset(data["list"], p.Service.Repo.Cols)).
 		JSON(data)
 }
 
@@ -84,7 +91,7 @@ func (p *RoleAPI) Create(c *gin.Context) {
 	}
 
 	resp.RecordCreate(base.CreateRole, role)
-	resp.Status(http.StatusOK).
+	resp.SetETag(createdRole.Model).Status(http.StatusOK).
 		MessageT(corterm.VCreatedSuccessfully, basterm.Role).
 		JSON(createdRole)
 }
@@ -101,19 +108,29 @@ func (p *RoleAPI) Update(c *gin.Context) {
 		return
 	}
 
-	if err = resp.Bind(&role, "E1076117", base.Domain, basterm.Role); err != nil {
+	if roleBefore, err = p.Service.FindByID(id); err != nil {
+		resp.Error(err).JSON()
+		return
+	}
+
+	if err = resp.IfMatch(roleBefore.Model, roleBefore, "E1075781", basterm.Role); err != nil {
+		return
+	}
+
+	if err = resp.BindUpdate(&role, roleBefore, "E1076117", base.Domain, basterm.Role); err != nil {
 		return
 	}
 
 	role.ID = id
 	role.CreatedAt = roleBefore.CreatedAt
+	role.UpdatedAt = roleBefore.UpdatedAt
 	if roleUpdated, roleBefore, err = p.Service.Save(role); err != nil {
 		resp.Error(err).JSON()
 		return
 	}
 
-	resp.Record(base.UpdateRole, roleBefore, role)
-	resp.Status(http.StatusOK).
+	resp.Record(base.UpdateRole, roleBefore, roleUpdated)
+	resp.SetETag(roleUpdated.Model).Status(http.StatusOK).
 		MessageT(corterm.VUpdatedSuccessfully, basterm.Role).
 		JSON(roleUpdated)
 }
@@ -140,6 +157,28 @@ func (p *RoleAPI) Delete(c *gin.Context) {
 		JSON()
 }
 
+// Restore the deleted role
+func (p *RoleAPI) Restore(c *gin.Context) {
+	resp := response.New(p.Engine, c, base.Domain)
+	var err error
+	var role basmodel.Role
+	var id uint
+
+	if id, err = resp.GetID(c.Param("roleID"), "E1029417", basterm.Role); err != nil {
+		return
+	}
+
+	if role, err = p.Service.Restore(id); err != nil {
+		resp.Error(err).JSON()
+		return
+	}
+
+	resp.Record(base.RestoreRole, role)
+	resp.Status(http.StatusOK).
+		MessageT(corterm.VRestoredSuccessfully, basterm.Role).
+		JSON(role)
+}
+
 // Excel generate excel files based on search
 func (p *RoleAPI) Excel(c *gin.Context) {
 	resp, params := response.NewParam(p.Engine, c, basterm.Roles, base.Domain)
@@ -0,0 +1,93 @@
+package basapi
+
+import (
+	"net/http"
+	"omono/domain/base"
+	"omono/domain/base/basmodel"
+	"omono/domain/base/basrepo"
+	"omono/domain/base/basterm"
+	"omono/domain/service"
+	"omono/internal/core"
+	"omono/internal/core/corterm"
+	"omono/internal/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SessionAPI for injecting session service
+type SessionAPI struct {
+	Service service.BasSessionServ
+	Engine  *core.Engine
+}
+
+// ProvideSessionAPI for session is used in wire
+func ProvideSessionAPI(c service.BasSessionServ) SessionAPI {
+	return SessionAPI{Service: c, Engine: c.Engine}
+}
+
+// ListSelf returns the active sessions of the current user
+func (p *SessionAPI) ListSelf(c *gin.Context) {
+	resp, params := response.NewParam(p.Engine, c, basmodel.SessionTable, base.Domain)
+
+	data := make(map[string]interface{})
+	var err error
+
+	if data["list"], data["count"], err = p.Service.ListSelf(params); err != nil {
+		resp.Error(err).JSON()
+		return
+	}
+
+	resp.Record(base.ListSession)
+	resp.Status(http.StatusOK).
+		MessageT(corterm.ListOfV, basterm.Sessions).
+		Extra(params.Keyset(data["list"], p.Service.Repo.Cols)).
+		JSON(data)
+}
+
+// ListByUser returns the sessions of a user for admins
+func (p *SessionAPI) ListByUser(c *gin.Context) {
+	resp, params := response.NewParam(p.Engine, c, basmodel.SessionTable, base.Domain)
+	var err error
+	var userID uint
+
+	if userID, err = resp.GetID(c.Param("userID"), "E1093263", basterm.User); err != nil {
+		return
+	}
+
+	data := make(map[string]interface{})
+	if data["list"], data["count"], err = p.Service.ListByUser(params, userID); err != nil {
+		resp.Error(err).JSON()
+		return
+	}
+
+	resp.Record(base.ListSession, userID)
+	resp.Status(http.StatusOK).
+		MessageT(corterm.ListOfV, basterm.Sessions).
+		Extra(params.Keyset(data["list"], p.Service.Repo.Cols)).
+		JSON(data)
+}
+
+// Terminate sign out a session, users with SessionWrite can terminate others' sessions
+func (p *SessionAPI) Terminate(c *gin.Context) {
+	resp, params := response.NewParam(p.Engine, c, basmodel.SessionTable, base.Domain)
+	var err error
+	var session basmodel.Session
+	var id uint
+
+	if id, err = resp.GetID(c.Param("sessionID"), "E1019757", basterm.Session); err != nil {
+		return
+	}
+
+	accessServ := service.ProvideBasAccessService(basrepo.ProvideAccessRepo(p.Engine))
+	manageOthers := !accessServ.CheckAccess(c, base.SessionWrite)
+
+	if session, err = p.Service.Terminate(params, id, manageOthers); err != nil {
+		resp.Error(err).JSON()
+		return
+	}
+
+	resp.Record(base.TerminateSession, session)
+	resp.Status(http.StatusOK).
+		MessageT(basterm.SessionTerminated).
+		JSON()
+}
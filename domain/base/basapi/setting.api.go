@@ -4,13 +4,17 @@ import (
 	"net/http"
 	"omono/domain/base"
 	"omono/domain/base/basmodel"
+	"omono/domain/base/basrepo"
 	"omono/domain/base/basterm"
 	"omono/domain/service"
 	"omono/internal/core"
+	"omono/internal/core/corerr"
 	"omono/internal/core/corterm"
 	"omono/internal/response"
 	"omono/pkg/helper/excel"
 
+	"github.com/syronz/limberr"
+
 	"github.com/gin-gonic/gin"
 )
 
@@ -42,7 +46,7 @@ func (p *SettingAPI) FindByID(c *gin.Context) {
 	}
 
 	resp.Record(base.ViewSetting)
-	resp.Status(http.StatusOK).
+	resp.SetETag(setting.Model).Status(http.StatusOK).
 		MessageT(corterm.VInfo, basterm.Setting).
 		JSON(setting)
 }
@@ -54,7 +58,13 @@ func (p *SettingAPI) List(c *gin.Context) {
 	data := make(map[string]interface{})
 	var err error
 
-	if data["list"], data["count"], err = p.Service.List(params); err != nil {
+	if params.Aggregated() {
+		data["list"], err = p.Service.Aggregate(params)
+	} else {
+		data["list"], data["count"], err = p.Service.List(params)
+	}
+
+	if err != nil {
 		resp.Error(err).JSON()
 		return
 	}
@@ -62,12 +72,13 @@ func (p *SettingAPI) List(c *gin.Context) {
 	resp.Record(base.ListSetting)
 	resp.Status(http.StatusOK).
 		MessageT(corterm.ListOfV, basterm.Settings).
+		Extra(params.Keyset(data["list"], p.Service.Repo.Cols)).
 		JSON(data)
 }
 
 // Update setting
 func (p *SettingAPI) Update(c *gin.Context) {
-	resp := response.New(p.Engine, c, base.Domain)
+	resp, params := response.NewParam(p.Engine, c, basterm.Settings, base.Domain)
 	var err error
 
 	var setting, settingBefore, settingUpdated basmodel.Setting
@@ -77,23 +88,45 @@ func (p *SettingAPI) Update(c *gin.Context) {
 		return
 	}
 
-	if err = resp.Bind(&setting, "E1049049", base.Domain, basterm.Setting); err != nil {
+	if settingBefore, err = p.Service.FindByID(id); err != nil {
+		resp.Error(err).JSON()
+		return
+	}
+
+	if err = resp.IfMatch(settingBefore.Model, settingBefore, "E1036275", basterm.Setting); err != nil {
 		return
 	}
 
-	if settingBefore, err = p.Service.FindByID(id); err != nil {
-		resp.Error(err).JSON()
+	if err = resp.BindUpdate(&setting, settingBefore, "E1049049", base.Domain, basterm.Setting); err != nil {
 		return
 	}
 
+	if settingBefore.Property == base.MFARequiredRoles {
+		accessServ := service.ProvideBasAccessService(basrepo.ProvideAccessRepo(p.Engine))
+		var super bool
+		if super, err = accessServ.HasSuperAccess(params.UserID); err != nil {
+			resp.Error(err).JSON()
+			return
+		}
+
+		if !super {
+			err = limberr.New("just super admin can change mfa_required_roles", "E1078840").
+				Message(basterm.OnlySuperAdminCanChangeV, base.MFARequiredRoles).
+				Custom(corerr.ForbiddenErr).Build()
+			resp.Error(err).JSON()
+			return
+		}
+	}
+
 	setting.ID = id
+	setting.UpdatedAt = settingBefore.UpdatedAt
 	if settingUpdated, err = p.Service.Update(setting); err != nil {
 		resp.Error(err).JSON()
 		return
 	}
 
 	resp.Record(base.UpdateSetting, settingBefore, settingUpdated)
-	resp.Status(http.StatusOK).
+	resp.SetETag(settingUpdated.Model).Status(http.StatusOK).
 		MessageT(corterm.VUpdatedSuccessfully, basterm.Setting).
 		JSON(settingUpdated)
 }
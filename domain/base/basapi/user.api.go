@@ -12,6 +12,7 @@ import (
 	"omono/internal/response"
 	"omono/pkg/glog"
 	"omono/pkg/helper/excel"
+	"omono/pkg/helper/sqluri"
 
 	"github.com/syronz/dict"
 
@@ -40,7 +41,7 @@ func (p *UserAPI) FindByID(c *gin.Context) {
 		return
 	}
 
-	if user, err = p.Service.FindByID(id); err != nil {
+	if user, err = p.Service.FindInCompany(resp.Params(basmodel.UserTable), id); err != nil {
 		resp.Error(err).JSON()
 		return
 	}
@@ -48,7 +49,7 @@ func (p *UserAPI) FindByID(c *gin.Context) {
 	user.Password = ""
 
 	resp.Record(base.ViewUser)
-	resp.Status(http.StatusOK).
+	resp.SetETag(user.Model).Status(http.StatusOK).
 		MessageT(corterm.VInfo, basterm.User).
 		JSON(user)
 }
@@ -75,13 +76,19 @@ func (p *UserAPI) List(c *gin.Context) {
 	resp, params := response.NewParam(p.Engine, c, basmodel.UserTable, base.Domain)
 
 	if username := c.Query("username"); username != "" {
-		params.Filter = fmt.Sprintf("bas_users.username[eq]'%v'", username)
+		params.Filter = "bas_users.username[eq]" + sqluri.Quote(username)
 	}
 
 	data := make(map[string]interface{})
 	var err error
 
-	if data["list"], data["count"], err = p.Service.List(params); err != nil {
+	if params.Aggregated() {
+		data["list"], err = p.Service.Aggregate(params)
+	} else {
+		data["list"], data["count"], err = p.Service.List(params)
+	}
+
+	if err != nil {
 		resp.Error(err).JSON()
 		return
 	}
@@ -89,6 +96,7 @@ func (p *UserAPI) List(c *gin.Context) {
 	resp.Record(base.ListUser)
 	resp.Status(http.StatusOK).
 		MessageT(corterm.ListOfV, basterm.Users).
+		Extra(params.Keyset(data["list"], p.Service.Repo.Cols)).
 		JSON(data)
 }
 
@@ -102,7 +110,7 @@ func (p *UserAPI) Create(c *gin.Context) {
 		return
 	}
 
-	if createdUser, err = p.Service.Create(user); err != nil {
+	if createdUser, err = p.Service.Create(resp.Params(basmodel.UserTable), user); err != nil {
 		resp.Error(err).JSON()
 		return
 	}
@@ -110,7 +118,7 @@ func (p *UserAPI) Create(c *gin.Context) {
 	user.Password = ""
 
 	resp.RecordCreate(base.CreateUser, user)
-	resp.Status(http.StatusOK).
+	resp.SetETag(createdUser.Model).Status(http.StatusOK).
 		MessageT(corterm.VCreatedSuccessfully, dict.R(basterm.User)).
 		JSON(createdUser)
 }
@@ -127,19 +135,30 @@ func (p *UserAPI) Update(c *gin.Context) {
 		return
 	}
 
-	if err = resp.Bind(&user, "E1065844", base.Domain, basterm.User); err != nil {
+	if userBefore, err = p.Service.FindInCompany(resp.Params(basmodel.UserTable), id); err != nil {
+		resp.Error(err).JSON()
+		return
+	}
+
+	userBefore.Password = ""
+	if err = resp.IfMatch(userBefore.Model, userBefore, "E1032970", basterm.User); err != nil {
+		return
+	}
+
+	if err = resp.BindUpdate(&user, userBefore, "E1065844", base.Domain, basterm.User); err != nil {
 		return
 	}
 
 	user.ID = id
 	user.CreatedAt = userBefore.CreatedAt
+	user.UpdatedAt = userBefore.UpdatedAt
 	if userUpdated, userBefore, err = p.Service.Save(user); err != nil {
 		resp.Error(err).JSON()
 		return
 	}
 
 	resp.Record(base.UpdateUser, userBefore, userUpdated)
-	resp.Status(http.StatusOK).
+	resp.SetETag(userUpdated.Model).Status(http.StatusOK).
 		MessageT(corterm.VUpdatedSuccessfully, dict.R(basterm.User)).
 		JSON(userUpdated)
 }
@@ -155,6 +174,11 @@ func (p *UserAPI) Delete(c *gin.Context) {
 		return
 	}
 
+	if _, err = p.Service.FindInCompany(resp.Params(basmodel.UserTable), id); err != nil {
+		resp.Error(err).JSON()
+		return
+	}
+
 	if user, err = p.Service.Delete(id); err != nil {
 		resp.Error(err).JSON()
 		return
@@ -166,6 +190,57 @@ func (p *UserAPI) Delete(c *gin.Context) {
 		JSON()
 }
 
+// Restore the deleted user
+func (p *UserAPI) Restore(c *gin.Context) {
+	resp := response.New(p.Engine, c, base.Domain)
+	var err error
+	var user basmodel.User
+	var id uint
+
+	if id, err = resp.GetID(c.Param("userID"), "E1047706", basterm.User); err != nil {
+		return
+	}
+
+	if user, err = p.Service.Restore(resp.Params(basmodel.UserTable), id); err != nil {
+		resp.Error(err).JSON()
+		return
+	}
+
+	resp.Record(base.RestoreUser, user)
+	resp.Status(http.StatusOK).
+		MessageT(corterm.VRestoredSuccessfully, basterm.User).
+		JSON(user)
+}
+
+// Unlock user after lockout because of failed logins
+func (p *UserAPI) Unlock(c *gin.Context) {
+	resp := response.New(p.Engine, c, base.Domain)
+	var err error
+	var user basmodel.User
+	var id uint
+
+	if id, err = resp.GetID(c.Param("userID"), "E1023950", basterm.User); err != nil {
+		return
+	}
+
+	if _, err = p.Service.FindInCompany(resp.Params(basmodel.UserTable), id); err != nil {
+		resp.Error(err).JSON()
+		return
+	}
+
+	if user, err = p.Service.Unlock(id); err != nil {
+		resp.Error(err).JSON()
+		return
+	}
+
+	user.Password = ""
+
+	resp.Record(base.UnlockUser, user)
+	resp.Status(http.StatusOK).
+		MessageT(basterm.UserUnlockedSuccessfully).
+		JSON()
+}
+
 // Excel generate excel files based on search
 func (p *UserAPI) Excel(c *gin.Context) {
 	resp, params := response.NewParam(p.Engine, c, basterm.Users, base.Domain)
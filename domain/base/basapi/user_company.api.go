@@ -0,0 +1,126 @@
+package basapi
+
+import (
+	"net/http"
+	"omono/domain/base"
+	"omono/domain/base/basmodel"
+	"omono/domain/base/basrepo"
+	"omono/domain/base/basterm"
+	"omono/domain/service"
+	"omono/internal/core"
+	"omono/internal/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UserCompanyAPI for injecting user company service
+type UserCompanyAPI struct {
+	Service service.BasUserCompanyServ
+	Engine  *core.Engine
+}
+
+// ProvideUserCompanyAPI for user company is used in wire
+func ProvideUserCompanyAPI(c service.BasUserCompanyServ) UserCompanyAPI {
+	return UserCompanyAPI{Service: c, Engine: c.Engine}
+}
+
+// ListSelf returns the companies of the current user beside the active one
+func (p *UserCompanyAPI) ListSelf(c *gin.Context) {
+	resp, params := response.NewParam(p.Engine, c, basmodel.UserCompanyTable, base.Domain)
+
+	data := make(map[string]interface{})
+	var err error
+
+	if data["list"], err = p.Service.ListByUser(params.UserID); err != nil {
+		resp.Error(err).JSON()
+		return
+	}
+
+	data["active"] = basmodel.Tenant{
+		CompanyID:   params.CompanyID,
+		CrossTenant: !params.CompanyScoped,
+	}
+
+	resp.Status(http.StatusOK).
+		MessageT(basterm.YourCompanies).
+		JSON(data)
+}
+
+// ListByUser returns the companies of a user
+func (p *UserCompanyAPI) ListByUser(c *gin.Context) {
+	resp, params := response.NewParam(p.Engine, c, basmodel.UserCompanyTable, base.Domain)
+	var err error
+	var companies []basmodel.UserCompany
+	var userID uint
+
+	if userID, err = resp.GetID(c.Param("userID"), "E1068939", basterm.User); err != nil {
+		return
+	}
+
+	if companies, err = p.Service.ListOfUser(params, userID); err != nil {
+		resp.Error(err).JSON()
+		return
+	}
+
+	// the companies are changed via the version of the user
+	userServ := service.ProvideBasUserService(basrepo.ProvideUserRepo(p.Engine))
+	var user basmodel.User
+	if user, err = userServ.FindByID(userID); err != nil {
+		resp.Error(err).JSON()
+		return
+	}
+
+	resp.Record(base.ListUserCompany)
+	resp.SetETag(user.Model).Status(http.StatusOK).
+		MessageT(basterm.CompaniesOfUser).
+		JSON(companies)
+}
+
+// Replace set the companies of a user
+func (p *UserCompanyAPI) Replace(c *gin.Context) {
+	resp, params := response.NewParam(p.Engine, c, basmodel.UserCompanyTable, base.Domain)
+	var err error
+	var userCompanies basmodel.UserCompanies
+	var companiesBefore, companies []basmodel.UserCompany
+	var userID uint
+
+	if userID, err = resp.GetID(c.Param("userID"), "E1047067", basterm.User); err != nil {
+		return
+	}
+
+	if companiesBefore, err = p.Service.ListOfUser(params, userID); err != nil {
+		resp.Error(err).JSON()
+		return
+	}
+
+	userServ := service.ProvideBasUserService(basrepo.ProvideUserRepo(p.Engine))
+	var user basmodel.User
+	if user, err = userServ.FindByID(userID); err != nil {
+		resp.Error(err).JSON()
+		return
+	}
+
+	if err = resp.IfMatch(user.Model, companiesBefore, "E1050701", basterm.Companies); err != nil {
+		return
+	}
+
+	if err = resp.Bind(&userCompanies, "E1023510", base.Domain, basterm.Companies); err != nil {
+		return
+	}
+
+	if companies, err = p.Service.Replace(params, userID, user.UpdatedAt,
+		userCompanies.CompanyIDs); err != nil {
+		resp.Error(err).JSON()
+		return
+	}
+
+	if user, err = userServ.FindByID(userID); err != nil {
+		resp.Error(err).JSON()
+		return
+	}
+
+	resp.Record(base.UpdateUserCompany, companiesBefore, companies)
+	resp.SetETag(user.Model).Status(http.StatusOK).
+		MessageT(basterm.CompaniesOfUser).
+		JSON(companies)
+}
@@ -0,0 +1,153 @@
+package basapi
+
+import (
+	"net/http"
+	"omono/domain/base"
+	"omono/domain/base/basmodel"
+	"omono/domain/base/basterm"
+	"omono/domain/service"
+	"omono/internal/core"
+	"omono/internal/core/corterm"
+	"omono/internal/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ViewAPI for injecting view service
+type ViewAPI struct {
+	Service service.BasViewServ
+	Engine  *core.Engine
+}
+
+// ProvideViewAPI for view is used in wire
+func ProvideViewAPI(c service.BasViewServ) ViewAPI {
+	return ViewAPI{Service: c, Engine: c.Engine}
+}
+
+// FindByID is used for fetch a view by it's id
+func (p *ViewAPI) FindByID(c *gin.Context) {
+	resp, params := response.NewParam(p.Engine, c, basmodel.ViewTable, base.Domain)
+	var err error
+	var view basmodel.View
+	var id uint
+
+	if id, err = resp.GetID(c.Param("viewID"), "E1060797", basterm.View); err != nil {
+		return
+	}
+
+	if view, err = p.Service.FindByID(params, id); err != nil {
+		resp.Error(err).JSON()
+		return
+	}
+
+	resp.Record(base.ViewView)
+	resp.SetETag(view.Model).Status(http.StatusOK).
+		MessageT(corterm.VInfo, basterm.View).
+		JSON(view)
+}
+
+// List of views, the views of the user beside the views shared with their roles
+func (p *ViewAPI) List(c *gin.Context) {
+	resp, params := response.NewParam(p.Engine, c, basmodel.ViewTable, base.Domain)
+
+	data := make(map[string]interface{})
+	var err error
+
+	if params.Aggregated() {
+		data["list"], err = p.Service.Aggregate(params)
+	} else {
+		data["list"], data["count"], err = p.Service.List(params)
+	}
+
+	if err != nil {
+		resp.Error(err).JSON()
+		return
+	}
+
+	resp.Record(base.ListView)
+	resp.Status(http.StatusOK).
+		MessageT(corterm.ListOfV, basterm.Views).
+		Extra(params.Keyset(data["list"], p.Service.Repo.Cols)).
+		JSON(data)
+}
+
+// Create view
+func (p *ViewAPI) Create(c *gin.Context) {
+	resp, params := response.NewParam(p.Engine, c, basmodel.ViewTable, base.Domain)
+	var view, createdView basmodel.View
+	var err error
+
+	if err = resp.Bind(&view, "E1083392", base.Domain, basterm.View); err != nil {
+		return
+	}
+
+	if createdView, err = p.Service.Create(params, view); err != nil {
+		resp.Error(err).JSON()
+		return
+	}
+
+	resp.RecordCreate(base.CreateView, createdView)
+	resp.SetETag(createdView.Model).Status(http.StatusOK).
+		MessageT(corterm.VCreatedSuccessfully, basterm.View).
+		JSON(createdView)
+}
+
+// Update view
+func (p *ViewAPI) Update(c *gin.Context) {
+	resp, params := response.NewParam(p.Engine, c, basmodel.ViewTable, base.Domain)
+	var err error
+
+	var view, viewBefore, viewUpdated basmodel.View
+	var id uint
+
+	if id, err = resp.GetID(c.Param("viewID"), "E1026515", basterm.View); err != nil {
+		return
+	}
+
+	if viewBefore, err = p.Service.FindByID(params, id); err != nil {
+		resp.Error(err).JSON()
+		return
+	}
+
+	if err = resp.IfMatch(viewBefore.Model, viewBefore, "E1032377", basterm.View); err != nil {
+		return
+	}
+
+	if err = resp.BindUpdate(&view, viewBefore, "E1096138", base.Domain, basterm.View); err != nil {
+		return
+	}
+
+	view.ID = id
+	view.UpdatedAt = viewBefore.UpdatedAt
+	if viewUpdated, viewBefore, err = p.Service.Save(params, view); err != nil {
+		resp.Error(err).JSON()
+		return
+	}
+
+	resp.Record(base.UpdateView, viewBefore, viewUpdated)
+	resp.SetETag(viewUpdated.Model).Status(http.StatusOK).
+		MessageT(corterm.VUpdatedSuccessfully, basterm.View).
+		JSON(viewUpdated)
+}
+
+// Delete view
+func (p *ViewAPI) Delete(c *gin.Context) {
+	resp, params := response.NewParam(p.Engine, c, basmodel.ViewTable, base.Domain)
+	var err error
+	var view basmodel.View
+	var id uint
+
+	if id, err = resp.GetID(c.Param("viewID"), "E1024514", basterm.View); err != nil {
+		return
+	}
+
+	if view, err = p.Service.Delete(params, id); err != nil {
+		resp.Error(err).JSON()
+		return
+	}
+
+	resp.Record(base.DeleteView, view)
+	resp.Status(http.StatusOK).
+		MessageT(corterm.VDeletedSuccessfully, basterm.View).
+		JSON()
+}
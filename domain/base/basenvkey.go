@@ -7,6 +7,7 @@ const (
 	PasswordSalt          types.Envkey = "PASSWORD_SALT"
 	JWTSecretKey          types.Envkey = "JWT_SECRET_KEY"
 	JWTExpiration         types.Envkey = "JWT_EXPIRATION"
+	JWTRefreshExpiration  types.Envkey = "JWT_REFRESH_EXPIRATION"
 	RecordRead            types.Envkey = "RECORD_READ"
 	RecordWrite           types.Envkey = "RECORD_WRITE"
 	ActivityFileCounter   types.Envkey = "ACTIVITY_FILE_COUNTER"
@@ -14,4 +15,17 @@ const (
 	AdminUsername         types.Envkey = "ADMIN_USERNAME"
 	AdminPassword         types.Envkey = "ADMIN_PASSWORD"
 	MaxHourTemporaryToken types.Envkey = "MAX_HOUR_TEMPORARY_TOKEN"
+
+	PasswordResetExpiration types.Envkey = "PASSWORD_RESET_EXPIRATION"
+	SMTPHost                types.Envkey = "SMTP_HOST"
+	SMTPPort                types.Envkey = "SMTP_PORT"
+	SMTPUsername            types.Envkey = "SMTP_USERNAME"
+	SMTPPassword            types.Envkey = "SMTP_PASSWORD"
+	SMTPFrom                types.Envkey = "SMTP_FROM"
+
+	JWTKeysDir   types.Envkey = "JWT_KEYS_DIR"
+	JWTActiveKey types.Envkey = "JWT_ACTIVE_KEY"
+
+	PermissionCacheTTL     types.Envkey = "PERMISSION_CACHE_TTL"
+	PermissionCacheBackend types.Envkey = "PERMISSION_CACHE_BACKEND"
 )
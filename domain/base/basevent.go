@@ -4,19 +4,21 @@ import "omono/internal/types"
 
 // types for base domain
 const (
-	CreateUser types.Event = "user-create"
-	UpdateUser types.Event = "user-update"
-	DeleteUser types.Event = "user-delete"
-	ListUser   types.Event = "user-list"
-	ViewUser   types.Event = "user-view"
-	ExcelUser  types.Event = "user-excel"
-
-	CreateRole types.Event = "role-create"
-	UpdateRole types.Event = "role-update"
-	DeleteRole types.Event = "role-delete"
-	ListRole   types.Event = "role-list"
-	ViewRole   types.Event = "role-view"
-	ExcelRole  types.Event = "role-excel"
+	CreateUser  types.Event = "user-create"
+	UpdateUser  types.Event = "user-update"
+	DeleteUser  types.Event = "user-delete"
+	RestoreUser types.Event = "user-restore"
+	ListUser    types.Event = "user-list"
+	ViewUser    types.Event = "user-view"
+	ExcelUser   types.Event = "user-excel"
+
+	CreateRole  types.Event = "role-create"
+	UpdateRole  types.Event = "role-update"
+	DeleteRole  types.Event = "role-delete"
+	RestoreRole types.Event = "role-restore"
+	ListRole    types.Event = "role-list"
+	ViewRole    types.Event = "role-view"
+	ExcelRole   types.Event = "role-excel"
 
 	CreateSetting types.Event = "setting-create"
 	UpdateSetting types.Event = "setting-update"
@@ -27,16 +29,68 @@ const (
 
 	AllActivity types.Event = "activity-all"
 
-	BasLogin    types.Event = "login"
-	BasLogout   types.Event = "logout"
-	LoginFailed types.Event = "login-failed"
-	Register    types.Event = "register"
-	ViewProfile types.Event = "profile-view"
-
-	CreateCity types.Event = "city-create"
-	UpdateCity types.Event = "city-update"
-	DeleteCity types.Event = "city-delete"
-	ListCity   types.Event = "city-list"
-	ViewCity   types.Event = "city-view"
-	ExcelCity  types.Event = "city-excel"
+	BasLogin      types.Event = "login"
+	BasLogout     types.Event = "logout"
+	LoginFailed   types.Event = "login-failed"
+	BasRefresh    types.Event = "token-refresh"
+	RefreshFailed types.Event = "token-refresh-failed"
+	Register      types.Event = "register"
+	ViewProfile   types.Event = "profile-view"
+
+	BasLoginMFA    types.Event = "login-mfa"
+	LoginMFAFailed types.Event = "login-mfa-failed"
+	MFAEnroll      types.Event = "mfa-enroll"
+	MFAConfirm     types.Event = "mfa-confirm"
+	MFADisable     types.Event = "mfa-disable"
+
+	LoginLocked types.Event = "login-locked"
+	UnlockUser  types.Event = "user-unlock"
+
+	PasswordChange       types.Event = "password-change"
+	PasswordChangeFailed types.Event = "password-change-failed"
+	PasswordForgot       types.Event = "password-forgot"
+	PasswordReset        types.Event = "password-reset"
+	PasswordResetFailed  types.Event = "password-reset-failed"
+
+	CreateAPIKey types.Event = "api-key-create"
+	ListAPIKey   types.Event = "api-key-list"
+	ViewAPIKey   types.Event = "api-key-view"
+	RevokeAPIKey types.Event = "api-key-revoke"
+
+	ListSession      types.Event = "session-list"
+	TerminateSession types.Event = "session-terminate"
+
+	Impersonate types.Event = "user-impersonate"
+
+	VerifyEmail       types.Event = "register-verify"
+	VerifyEmailFailed types.Event = "register-verify-failed"
+	ApproveUser       types.Event = "user-approve"
+
+	ListResource    types.Event = "resource-list"
+	ViewPermissions types.Event = "profile-permissions"
+
+	SwitchCompany     types.Event = "company-switch"
+	ListUserCompany   types.Event = "user-company-list"
+	UpdateUserCompany types.Event = "user-company-update"
+
+	CreateGrant   types.Event = "grant-create"
+	ListGrant     types.Event = "grant-list"
+	ViewGrant     types.Event = "grant-view"
+	ListUserGrant types.Event = "user-grant-list"
+	RevokeGrant   types.Event = "grant-revoke"
+	ExpireGrant   types.Event = "grant-expire"
+
+	CreateCity  types.Event = "city-create"
+	UpdateCity  types.Event = "city-update"
+	DeleteCity  types.Event = "city-delete"
+	RestoreCity types.Event = "city-restore"
+	ListCity    types.Event = "city-list"
+	ViewCity    types.Event = "city-view"
+	ExcelCity   types.Event = "city-excel"
+
+	CreateView types.Event = "view-create"
+	UpdateView types.Event = "view-update"
+	DeleteView types.Event = "view-delete"
+	ListView   types.Event = "view-list"
+	ViewView   types.Event = "view-view"
 )
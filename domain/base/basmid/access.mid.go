@@ -30,7 +30,8 @@ func (p *accessMid) Check(resource types.Resource) gin.HandlerFunc {
 		accessService := service.ProvideBasAccessService(basrepo.ProvideAccessRepo(p.engine))
 		accessResult := accessService.CheckAccess(c, resource)
 
-		if c.Query("deleted") == "true" {
+		// the deleted rows need the resource of the route beside the deleted:read
+		if !accessResult && c.Query("deleted") == "true" {
 			accessResult = accessService.CheckAccess(c, base.ReadDeleted)
 		}
 
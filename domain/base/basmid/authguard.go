@@ -3,8 +3,11 @@ package basmid
 import (
 	"github.com/syronz/limberr"
 	"omono/domain/base"
+	"omono/domain/base/basrepo"
+	"omono/domain/service"
 	"omono/internal/core"
 	"omono/internal/core/corerr"
+	"omono/internal/core/corperm"
 	"strings"
 
 	"omono/internal/response"
@@ -16,11 +19,45 @@ import (
 
 // AuthGuard is used for decode the token and get public and private information
 func AuthGuard(engine *core.Engine) gin.HandlerFunc {
-	jwtKey := []byte(engine.Envs[base.JWTSecretKey])
-	fJWT := func(token *jwt.Token) (interface{}, error) { return jwtKey, nil }
+	return guard(engine, false)
+}
+
+// MFAGuard is like AuthGuard but it accepts the mfa pending tokens too, it is used for
+// enrolling the two-factor authentication in case it is required for the user's role
+func MFAGuard(engine *core.Engine) gin.HandlerFunc {
+	return guard(engine, true)
+}
+
+func guard(engine *core.Engine, acceptMFAPending bool) gin.HandlerFunc {
+	tokenServ := service.ProvideBasTokenService(basrepo.ProvideTokenRepo(engine))
+	apiKeyServ := service.ProvideBasAPIKeyService(basrepo.ProvideAPIKeyRepo(engine))
+	sessionServ := service.ProvideBasSessionService(basrepo.ProvideSessionRepo(engine))
 
 	return func(c *gin.Context) {
 
+		if key := strings.TrimSpace(c.GetHeader("X-API-Key")); key != "" {
+			apiKey, err := apiKeyServ.Authenticate(key, c.ClientIP())
+			if err != nil {
+				response.New(engine, c, base.Domain).Error(err).Abort().JSON()
+				return
+			}
+
+			resources := corperm.Compile(apiKey.Resources)
+			c.Set("USERNAME", apiKey.Username)
+			c.Set("USER_ID", apiKey.UserID)
+			c.Set("LANGUAGE", apiKey.Lang)
+			c.Set("RESOURCES", resources)
+			c.Set("API_KEY_ID", apiKey.ID)
+			c.Set("COMPANY_ID", apiKey.CompanyID)
+
+			// keys of the super admin which are created in the cross tenant mode are not limited
+			if apiKey.CompanyID == 0 && resources.Has(base.SuperAccess) {
+				c.Set("CROSS_TENANT", true)
+			}
+			c.Next()
+			return
+		}
+
 		token := strings.TrimSpace(c.Query("temporary_token"))
 		if token == "" {
 			tokenArr, ok := c.Request.Header["Authorization"]
@@ -37,7 +74,7 @@ func AuthGuard(engine *core.Engine) gin.HandlerFunc {
 
 		claims := &types.JWTClaims{}
 
-		if tkn, err := jwt.ParseWithClaims(token, claims, fJWT); err != nil {
+		if tkn, err := jwt.ParseWithClaims(token, claims, engine.JWTKeys.Keyfunc); err != nil {
 			checkErr(c, err, engine)
 			return
 		} else if !tkn.Valid {
@@ -45,9 +82,47 @@ func AuthGuard(engine *core.Engine) gin.HandlerFunc {
 			return
 		}
 
+		if claims.MFAPending && !acceptMFAPending {
+			err := limberr.New("mfa pending token is used", "E1055322").
+				Custom(corerr.UnauthorizedErr).
+				Message(corerr.TokenIsNotValid).Build()
+			response.New(engine, c, base.Domain).Error(err).Abort().JSON()
+			return
+		}
+
+		if revoked, err := tokenServ.IsRevoked(claims.Id); err != nil {
+			response.New(engine, c, base.Domain).Error(err).Abort().JSON()
+			return
+		} else if revoked {
+			err = limberr.New("token is revoked", "E1056366").
+				Custom(corerr.UnauthorizedErr).
+				Message(corerr.TokenIsRevoked).Build()
+			response.New(engine, c, base.Domain).Error(err).Abort().JSON()
+			return
+		}
+
+		if claims.SessionID != 0 {
+			if err := sessionServ.Check(claims.SessionID); err != nil {
+				response.New(engine, c, base.Domain).Error(err).Abort().JSON()
+				return
+			}
+		}
+
 		c.Set("USERNAME", claims.Username)
 		c.Set("USER_ID", claims.ID)
 		c.Set("LANGUAGE", claims.Lang)
+		c.Set("JTI", claims.Id)
+		c.Set("SESSION_ID", claims.SessionID)
+		c.Set("COMPANY_ID", claims.CompanyID)
+		if claims.CrossTenant {
+			c.Set("CROSS_TENANT", true)
+		}
+
+		// in impersonation USER_ID is the impersonated user and ACTOR_ID is the real one
+		if claims.ActorID != 0 {
+			c.Set("ACTOR_ID", claims.ActorID)
+			c.Set("ACTOR_USERNAME", claims.ActorUsername)
+		}
 		c.Next()
 	}
 }
@@ -55,7 +130,10 @@ func AuthGuard(engine *core.Engine) gin.HandlerFunc {
 func checkErr(c *gin.Context, err error, engine *core.Engine) {
 	if err != nil {
 
-		if err == jwt.ErrSignatureInvalid {
+		// just expired tokens should ask for refresh, an unknown kid or a wrong alg or
+		// signature means the token is not valid
+		if vErr, ok := err.(*jwt.ValidationError); !ok ||
+			vErr.Errors&jwt.ValidationErrorExpired == 0 {
 			err = limberr.Take(err).Custom(corerr.UnauthorizedErr).
 				Message(corerr.TokenIsNotValid).Build()
 			response.New(engine, c, base.Domain).Error(err).Abort().JSON()
@@ -0,0 +1,54 @@
+package basmid
+
+import (
+	"omono/domain/base"
+	"omono/domain/base/basmodel"
+	"omono/domain/base/basrepo"
+	"omono/domain/base/basterm"
+	"omono/domain/service"
+	"omono/internal/core"
+	"omono/internal/param"
+	"omono/internal/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+type viewMid struct {
+	engine *core.Engine
+}
+
+// NewViewMid is a simpler way for access to the struct
+func NewViewMid(engine *core.Engine) viewMid {
+	return viewMid{
+		engine: engine,
+	}
+}
+
+// Apply load the view of the ?view=<id> and put it in the context, param.Get merges it with the
+// query. The entity is the path of the list and the view should be saved for it
+func (p *viewMid) Apply(entity string) gin.HandlerFunc {
+	viewServ := service.ProvideBasViewService(basrepo.ProvideViewRepo(p.engine))
+
+	return func(c *gin.Context) {
+		if c.Query("view") == "" {
+			c.Next()
+			return
+		}
+
+		resp, params := response.NewParam(p.engine, c, basmodel.ViewTable, base.Domain)
+		id, err := resp.GetID(c.Query("view"), "E1028655", basterm.View)
+		if err != nil {
+			c.Abort()
+			return
+		}
+
+		var view param.View
+		if view, err = viewServ.Param(params, id, entity); err != nil {
+			resp.Error(err).Abort().JSON()
+			return
+		}
+
+		c.Set("VIEW", view)
+		c.Next()
+	}
+}
@@ -14,27 +14,16 @@ const (
 // Activity model
 type Activity struct {
 	gorm.Model
-	Event    string `gorm:"index:event_idx" json:"event"`
+	Event    string `gorm:"index:event_idx" json:"event" search:"fulltext"`
 	UserID   uint   `json:"user_id"`
-	Username string `gorm:"index:username_idx" json:"username"`
-	IP       string `json:"ip"`
-	URI      string `gorm:"type:text" json:"uri"`
-	Before   string `gorm:"type:text" json:"before"`
-	After    string `gorm:"type:text" json:"after"`
-}
-
-// Pattern returns the search pattern to be used inside the gorm's where
-func (p Activity) Pattern() string {
-	return `(
-		bas_activities.id = '%[1]v' OR
-		bas_activities.event LIKE '%[1]v%%' OR
-		bas_activities.username LIKE '%[1]v%%' OR
-		bas_activities.ip LIKE '%[1]v' OR
-		bas_activities.uri LIKE '%[1]v%%' OR
-		cast(bas_activities.created_at as char) LIKE '%[1]v%%' OR
-		bas_activities.before LIKE '%%%[1]v%%' OR
-		bas_activities.after LIKE '%%%[1]v%%'
-	)`
+	Username string `gorm:"index:username_idx" json:"username" search:"fulltext"`
+	IP       string `json:"ip" search:"like"`
+	URI      string `gorm:"type:text" json:"uri" search:"fulltext"`
+	Before   string `gorm:"type:text" json:"before" search:"fulltext"`
+	After    string `gorm:"type:text" json:"after" search:"fulltext"`
+	// ActorID and ActorUsername are filled in case the user is impersonated by another user
+	ActorID       uint   `json:"actor_id,omitempty"`
+	ActorUsername string `gorm:"index:actor_username_idx" json:"actor_username,omitempty" search:"fulltext"`
 }
 
 // Columns return list of total columns according to request, useful for inner joins
@@ -44,6 +33,8 @@ func (p Activity) Columns(variate string) (string, error) {
 		"bas_activities.event",
 		"bas_activities.user_id",
 		"bas_activities.username",
+		"bas_activities.actor_id",
+		"bas_activities.actor_username",
 		"bas_activities.ip",
 		"bas_activities.uri",
 		"bas_activities.before",
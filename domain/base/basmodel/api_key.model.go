@@ -0,0 +1,117 @@
+package basmodel
+
+import (
+	"net"
+	"omono/domain/base/basterm"
+	"omono/internal/core/coract"
+	"omono/internal/core/corerr"
+	"omono/internal/core/corperm"
+	"omono/internal/core/corterm"
+	"omono/internal/types"
+	"strings"
+	"time"
+
+	"github.com/syronz/dict"
+	"github.com/syronz/limberr"
+	"gorm.io/gorm"
+)
+
+// APIKeyTable is used inside the repo layer
+const (
+	APIKeyTable = "bas_api_keys"
+)
+
+// APIKey model is used by machine clients instead of JWT, only the sha256 of the key is saved
+type APIKey struct {
+	gorm.Model
+	UserID     uint          `gorm:"not null;index:user_id_idx" json:"user_id"`
+	RoleID     uint          `gorm:"not null;index:role_id_idx" json:"role_id"`
+	Name       string        `gorm:"not null" json:"name,omitempty" search:"fulltext"`
+	Prefix     string        `gorm:"type:varchar(12)" json:"prefix,omitempty" search:"like"`
+	Hash       string        `gorm:"type:varchar(64);not null;unique" json:"-" table:"-"`
+	ExpiresAt  *time.Time    `json:"expires_at,omitempty"`
+	AllowedIPs string        `gorm:"type:text" json:"allowed_ips,omitempty"`
+	LastUsedAt *time.Time    `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time    `json:"revoked_at,omitempty"`
+	Secret     string        `gorm:"-" json:"key,omitempty" table:"-"`
+	Username   string        `gorm:"->" json:"username,omitempty" table:"bas_users.username" search:"like"`
+	Lang       dict.Lang     `gorm:"->" json:"-" table:"-"`
+	UserStatus types.Enum    `gorm:"->" json:"-" table:"-"`
+	Role       string        `gorm:"->" json:"role,omitempty" table:"bas_roles.name as role" search:"like"`
+	Resources  corperm.Rules `gorm:"-" json:"-" table:"-"`
+	// CompanyID is the active company of the creator, the key is limited to it
+	CompanyID uint `gorm:"index:company_id_idx" json:"company_id"`
+}
+
+// Validate check the type of fields
+func (p *APIKey) Validate(act coract.Action) (err error) {
+
+	switch act {
+	case coract.Create:
+		if p.Name == "" {
+			err = limberr.AddInvalidParam(err, "name",
+				corerr.VisRequired, dict.R(corterm.Name))
+		}
+
+		if len(p.Name) > 255 {
+			err = limberr.AddInvalidParam(err, "name",
+				corerr.MaximumAcceptedCharacterForVisV,
+				dict.R(corterm.Name), 255)
+		}
+
+		if p.RoleID == 0 {
+			err = limberr.AddInvalidParam(err, "role_id",
+				corerr.VisRequired, dict.R(basterm.Role))
+		}
+
+		if p.ExpiresAt != nil && p.ExpiresAt.Before(time.Now()) {
+			err = limberr.AddInvalidParam(err, "expires_at",
+				corerr.VisNotValid, dict.R(basterm.ExpiresAt))
+		}
+
+		for _, v := range p.IPList() {
+			if net.ParseIP(v) == nil {
+				if _, _, errCIDR := net.ParseCIDR(v); errCIDR != nil {
+					err = limberr.AddInvalidParam(err, "allowed_ips",
+						corerr.VisNotValid, dict.R(basterm.AllowedIPs))
+					break
+				}
+			}
+		}
+	}
+
+	return err
+}
+
+// IPList split the allowed IPs, each item is an IP or a CIDR
+func (p *APIKey) IPList() (ips []string) {
+	for _, v := range strings.Split(p.AllowedIPs, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			ips = append(ips, v)
+		}
+	}
+
+	return
+}
+
+// IsIPAllowed returns true in case the allowlist is empty or the ip matches one of the items
+func (p *APIKey) IsIPAllowed(ip string) bool {
+	ips := p.IPList()
+	if len(ips) == 0 {
+		return true
+	}
+
+	clientIP := net.ParseIP(ip)
+	for _, v := range ips {
+		if v == ip {
+			return true
+		}
+
+		if _, network, err := net.ParseCIDR(v); err == nil && clientIP != nil &&
+			network.Contains(clientIP) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -3,6 +3,7 @@ package basmodel
 import (
 	"github.com/syronz/dict"
 	"github.com/syronz/limberr"
+	"omono/domain/base/basterm"
 	"omono/internal/core/coract"
 	"omono/internal/core/corerr"
 	"omono/internal/core/corterm"
@@ -10,8 +11,20 @@ import (
 
 // Auth model
 type Auth struct {
-	Username string `json:"username"`
-	Password string `json:"password"`
+	Username     string `json:"username"`
+	Password     string `json:"password"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	MFAToken     string `json:"mfa_token,omitempty"`
+	Code         string `json:"code,omitempty"`
+	IP           string `json:"-"`
+	UserAgent    string `json:"-"`
+}
+
+// AuthToken is returned inside the user's extra after login and refresh
+type AuthToken struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresAt    int64  `json:"expires_at"`
 }
 
 // Validate check the type of fields for auth
@@ -28,6 +41,23 @@ func (p *Auth) Validate(act coract.Action) (err error) {
 			err = limberr.AddInvalidParam(err, "password",
 				corerr.VisRequired, dict.R(corterm.Password))
 		}
+
+	case coract.Refresh:
+		if p.RefreshToken == "" {
+			err = limberr.AddInvalidParam(err, "refresh_token",
+				corerr.VisRequired, dict.R(basterm.RefreshToken))
+		}
+
+	case coract.Verify:
+		if p.MFAToken == "" {
+			err = limberr.AddInvalidParam(err, "mfa_token",
+				corerr.VisRequired, dict.R(basterm.MFAToken))
+		}
+
+		if p.Code == "" {
+			err = limberr.AddInvalidParam(err, "code",
+				corerr.VisRequired, dict.R(basterm.TwoFactorCode))
+		}
 	}
 
 	return err
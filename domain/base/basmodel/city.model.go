@@ -19,8 +19,8 @@ const (
 // City model
 type City struct {
 	gorm.Model
-	City  string `gorm:"not null;unique" json:"city,omitempty"`
-	Notes string `json:"notes,omitempty"`
+	City  string `gorm:"type:varchar(191);not null" json:"city,omitempty" search:"fulltext"`
+	Notes string `json:"notes,omitempty" search:"fulltext"`
 }
 
 // Validate check the type of fields
@@ -0,0 +1,86 @@
+package basmodel
+
+import (
+	"omono/domain/base/basterm"
+	"omono/internal/core/coract"
+	"omono/internal/core/corerr"
+	"omono/internal/core/corperm"
+	"omono/internal/core/corterm"
+	"strings"
+	"time"
+
+	"github.com/syronz/dict"
+	"github.com/syronz/limberr"
+	"gorm.io/gorm"
+)
+
+// GrantTable is used inside the repo layer
+const (
+	GrantTable = "bas_grants"
+)
+
+// Grant gives extra resources to the user for a limited window without editing their roles,
+// the grantor should have the resources themselves
+type Grant struct {
+	gorm.Model
+	UserID    uint          `gorm:"not null;index:user_id_idx" json:"user_id"`
+	GrantorID uint          `gorm:"not null;index:grantor_id_idx" json:"grantor_id"`
+	Resources corperm.Rules `gorm:"type:text" json:"resources"`
+	StartsAt  time.Time     `gorm:"index:starts_at_idx" json:"starts_at"`
+	EndsAt    time.Time     `gorm:"index:ends_at_idx" json:"ends_at"`
+	Reason    string        `gorm:"type:text" json:"reason,omitempty" search:"fulltext"`
+	RevokedAt *time.Time    `json:"revoked_at,omitempty"`
+	ExpiredAt *time.Time    `json:"expired_at,omitempty"`
+	Username  string        `gorm:"->" json:"username,omitempty" table:"bas_users.username" search:"like"`
+	Grantor   string        `gorm:"->" json:"grantor,omitempty" table:"grantors.username as grantor" search:"like"`
+}
+
+// Validate check the type of fields
+func (p *Grant) Validate(act coract.Action) (err error) {
+
+	switch act {
+	case coract.Create:
+		if p.UserID == 0 {
+			err = limberr.AddInvalidParam(err, "user_id",
+				corerr.VisRequired, dict.R(basterm.User))
+		}
+
+		if len(p.Resources) == 0 {
+			err = limberr.AddInvalidParam(err, "resources",
+				corerr.VisRequired, dict.R(corterm.Resources))
+		}
+
+		// a grant just adds resources, limiting the user is the job of their roles
+		for _, v := range p.Resources {
+			if strings.HasPrefix(v, corperm.Deny) {
+				err = limberr.AddInvalidParam(err, "resources",
+					corerr.VisNotValid, v)
+			}
+		}
+
+		for _, v := range p.Resources.Invalid() {
+			err = limberr.AddInvalidParam(err, "resources",
+				corerr.VisNotValid, v)
+		}
+
+		if p.EndsAt.IsZero() {
+			err = limberr.AddInvalidParam(err, "ends_at",
+				corerr.VisRequired, dict.R(basterm.EndsAt))
+		} else if !p.EndsAt.After(p.StartsAt) || p.EndsAt.Before(time.Now()) {
+			err = limberr.AddInvalidParam(err, "ends_at",
+				corerr.VisNotValid, dict.R(basterm.EndsAt))
+		}
+
+		if strings.TrimSpace(p.Reason) == "" {
+			err = limberr.AddInvalidParam(err, "reason",
+				corerr.VisRequired, dict.R(basterm.Reason))
+		}
+	}
+
+	return err
+}
+
+// IsActive returns true in case the grant is not revoked and the time is inside its window
+func (p *Grant) IsActive(now time.Time) bool {
+	return p.RevokedAt == nil && !now.Before(p.StartsAt) && now.Before(p.EndsAt)
+}
@@ -0,0 +1,28 @@
+package basmodel
+
+import (
+	"time"
+)
+
+// LoginAttemptTable is used inside the repo layer
+const (
+	LoginAttemptTable = "bas_login_attempts"
+)
+
+// kinds of login attempt, failures are counted per username and per IP
+const (
+	AttemptUsername = "username"
+	AttemptIP       = "ip"
+)
+
+// LoginAttempt model keeps the number of consecutive failed logins
+type LoginAttempt struct {
+	ID           uint       `gorm:"primarykey" json:"id"`
+	Kind         string     `gorm:"type:varchar(10);not null;uniqueIndex:kind_identifier_idx" json:"kind"`
+	Identifier   string     `gorm:"type:varchar(191);not null;uniqueIndex:kind_identifier_idx" json:"identifier"`
+	Failures     int        `gorm:"not null;default:0" json:"failures"`
+	LastFailedAt time.Time  `json:"last_failed_at"`
+	LockedUntil  *time.Time `json:"locked_until"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
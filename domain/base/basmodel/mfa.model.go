@@ -0,0 +1,46 @@
+package basmodel
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// MFATable and RecoveryCodeTable are used inside the repo layer
+const (
+	MFATable          = "bas_mfas"
+	RecoveryCodeTable = "bas_recovery_codes"
+)
+
+// MFA model keeps the TOTP secret of the user, it is enabled after the first code is confirmed
+type MFA struct {
+	gorm.Model
+	UserID      uint       `gorm:"not null;unique" json:"user_id"`
+	Secret      string     `gorm:"type:varchar(64);not null" json:"-"`
+	Enabled     bool       `gorm:"not null;default:false" json:"enabled"`
+	ConfirmedAt *time.Time `json:"confirmed_at"`
+	// LastStep is the time-step of the last accepted code, the codes at or below it are rejected
+	LastStep int64 `gorm:"not null;default:0" json:"-"`
+}
+
+// RecoveryCode model, only the sha256 of the code is saved in the database
+type RecoveryCode struct {
+	gorm.Model
+	UserID uint       `gorm:"not null;index:user_id_idx" json:"user_id"`
+	Code   string     `gorm:"type:varchar(64);not null" json:"-"`
+	UsedAt *time.Time `json:"used_at"`
+}
+
+// MFAEnroll is returned to the user for adding the account to the authenticator app
+type MFAEnroll struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
+
+// MFAChallenge is returned inside the user's extra after login in case of two-factor
+// authentication is enabled or required for the user's role
+type MFAChallenge struct {
+	MFAToken       string `json:"mfa_token"`
+	ExpiresAt      int64  `json:"expires_at"`
+	EnrollRequired bool   `json:"enroll_required"`
+}
@@ -0,0 +1,137 @@
+package basmodel
+
+import (
+	"omono/domain/base/basterm"
+	"omono/internal/consts"
+	"omono/internal/core/coract"
+	"omono/internal/core/corerr"
+	"omono/internal/core/corterm"
+	"omono/pkg/helper/password"
+	"sync"
+	"time"
+
+	"github.com/syronz/dict"
+	"github.com/syronz/limberr"
+	"gorm.io/gorm"
+)
+
+// PasswordResetTable is used inside the repo layer
+const (
+	PasswordResetTable   = "bas_password_resets"
+	PasswordHistoryTable = "bas_password_histories"
+)
+
+// PasswordReset model, only the sha256 of the token is saved in the database
+type PasswordReset struct {
+	gorm.Model
+	UserID    uint       `gorm:"not null;index:user_id_idx" json:"user_id"`
+	Token     string     `gorm:"type:varchar(64);not null;unique" json:"-"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at"`
+}
+
+// PasswordHistory model keeps the hash of previous passwords for preventing reuse
+type PasswordHistory struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	UserID    uint      `gorm:"not null;index:user_id_idx" json:"user_id"`
+	Password  string    `gorm:"not null" json:"-"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Password is used for changing, forgetting and resetting the password
+type Password struct {
+	CurrentPassword string `json:"current_password,omitempty"`
+	NewPassword     string `json:"new_password,omitempty"`
+	Email           string `json:"email,omitempty"`
+	Token           string `json:"token,omitempty"`
+}
+
+// Validate check the type of fields for password
+func (p *Password) Validate(act coract.Action) (err error) {
+
+	switch act {
+	case coract.Update:
+		if p.CurrentPassword == "" {
+			err = limberr.AddInvalidParam(err, "current_password",
+				corerr.VisRequired, dict.R(basterm.CurrentPassword))
+		}
+
+		err = validateNewPassword(err, p.NewPassword)
+
+	case coract.Forgot:
+		if p.Email == "" {
+			err = limberr.AddInvalidParam(err, "email",
+				corerr.VisRequired, dict.R(corterm.Email))
+		}
+
+		err = validateUserEmail(err, p.Email)
+
+	case coract.Reset:
+		if p.Token == "" {
+			err = limberr.AddInvalidParam(err, "token",
+				corerr.VisRequired, dict.R(basterm.ResetToken))
+		}
+
+		err = validateNewPassword(err, p.NewPassword)
+	}
+
+	return err
+}
+
+func validateNewPassword(err error, pass string) error {
+	if pass == "" {
+		return limberr.AddInvalidParam(err, "new_password",
+			corerr.VisRequired, dict.R(basterm.NewPassword))
+	}
+
+	return validatePassword(err, "new_password", pass)
+}
+
+var passwordPolicy = struct {
+	sync.RWMutex
+	policy password.Policy
+}{policy: password.Policy{MinLength: consts.MinimumPasswordChar}}
+
+// SetPasswordPolicy is called after loading the settings, the policy is used in validating
+// the password of users
+func SetPasswordPolicy(policy password.Policy) {
+	passwordPolicy.Lock()
+	passwordPolicy.policy = policy
+	passwordPolicy.Unlock()
+}
+
+func validatePassword(err error, field, pass string) error {
+	passwordPolicy.RLock()
+	policy := passwordPolicy.policy
+	passwordPolicy.RUnlock()
+
+	for _, rule := range policy.Check(pass) {
+		switch rule {
+		case password.RuleLength:
+			err = limberr.AddInvalidParam(err, field,
+				corerr.MinimumAcceptedCharacterForVisV,
+				dict.R(corterm.Password), policy.MinLength)
+		case password.RuleLower:
+			err = limberr.AddInvalidParam(err, field,
+				basterm.VShouldContainAtLeastOneV,
+				dict.R(corterm.Password), dict.R(basterm.LowercaseLetter))
+		case password.RuleUpper:
+			err = limberr.AddInvalidParam(err, field,
+				basterm.VShouldContainAtLeastOneV,
+				dict.R(corterm.Password), dict.R(basterm.UppercaseLetter))
+		case password.RuleDigit:
+			err = limberr.AddInvalidParam(err, field,
+				basterm.VShouldContainAtLeastOneV,
+				dict.R(corterm.Password), dict.R(basterm.Digit))
+		case password.RuleSymbol:
+			err = limberr.AddInvalidParam(err, field,
+				basterm.VShouldContainAtLeastOneV,
+				dict.R(corterm.Password), dict.R(basterm.Symbol))
+		case password.RuleBanned:
+			err = limberr.AddInvalidParam(err, field,
+				basterm.VIsTooCommonChooseAnotherOne, dict.R(corterm.Password))
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,18 @@
+package basmodel
+
+import (
+	"time"
+)
+
+// PermissionEventTable is used inside the repo layer
+const (
+	PermissionEventTable = "bas_permission_events"
+)
+
+// PermissionEvent is an invalidation of the permission cache which is shared between the
+// instances, UserIDs is a comma separated list and empty means all users
+type PermissionEvent struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	UserIDs   string    `gorm:"type:text" json:"user_ids"`
+	CreatedAt time.Time `gorm:"index:created_at_idx" json:"created_at"`
+}
@@ -0,0 +1,26 @@
+package basmodel
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// RefreshTokenTable is used inside the repo layer
+const (
+	RefreshTokenTable = "bas_refresh_tokens"
+)
+
+// RefreshToken model, only the sha256 of the token is saved in the database
+type RefreshToken struct {
+	gorm.Model
+	UserID    uint       `gorm:"not null;index:user_id_idx" json:"user_id"`
+	Token     string     `gorm:"type:varchar(64);not null;unique" json:"-"`
+	AccessJTI string     `gorm:"type:varchar(64);index:access_jti_idx" json:"access_jti"`
+	SessionID uint       `gorm:"index:session_id_idx" json:"session_id"`
+	ExpiresAt time.Time  `gorm:"index:expires_at_idx" json:"expires_at"`
+	RevokedAt *time.Time `json:"revoked_at"`
+	// the tenant is kept for issuing the next access token with the same company
+	CompanyID   uint `json:"company_id"`
+	CrossTenant bool `json:"cross_tenant"`
+}
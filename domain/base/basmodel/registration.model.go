@@ -0,0 +1,56 @@
+package basmodel
+
+import (
+	"omono/domain/base/basterm"
+	"omono/internal/core/coract"
+	"omono/internal/core/corerr"
+	"omono/internal/core/corterm"
+	"time"
+
+	"github.com/syronz/dict"
+	"github.com/syronz/limberr"
+	"gorm.io/gorm"
+)
+
+// EmailVerificationTable is used inside the repo layer
+const (
+	EmailVerificationTable = "bas_email_verifications"
+)
+
+// EmailVerification model, the token is sent to the email of the registered user and only
+// its sha256 is saved in the database
+type EmailVerification struct {
+	gorm.Model
+	UserID    uint       `gorm:"not null;index:user_id_idx" json:"user_id"`
+	Token     string     `gorm:"type:varchar(64);not null;unique" json:"-"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at"`
+}
+
+// Verification is used for verifying the email and resending the link
+type Verification struct {
+	Email string `json:"email,omitempty"`
+	Token string `json:"token,omitempty"`
+}
+
+// Validate check the type of fields
+func (p *Verification) Validate(act coract.Action) (err error) {
+
+	switch act {
+	case coract.Verify:
+		if p.Token == "" {
+			err = limberr.AddInvalidParam(err, "token",
+				corerr.VisRequired, dict.R(basterm.VerificationToken))
+		}
+
+	case coract.Resend:
+		if p.Email == "" {
+			err = limberr.AddInvalidParam(err, "email",
+				corerr.VisRequired, dict.R(corterm.Email))
+		}
+
+		err = validateUserEmail(err, p.Email)
+	}
+
+	return err
+}
@@ -0,0 +1,35 @@
+package basmodel
+
+import (
+	"omono/internal/types"
+)
+
+// ResourceDomain is a section of the resource catalog which is used in the role editor, the
+// wildcards can be used in the resources of the roles instead of listing all of them
+type ResourceDomain struct {
+	Domain   string          `json:"domain"`
+	Wildcard string          `json:"wildcard"`
+	Groups   []ResourceGroup `json:"groups"`
+}
+
+// ResourceGroup keeps the resources of an entity
+type ResourceGroup struct {
+	Group     string         `json:"group"`
+	Title     string         `json:"title"`
+	Wildcard  string         `json:"wildcard,omitempty"`
+	Resources []ResourceItem `json:"resources"`
+}
+
+// ResourceItem is a registered resource with its translated description
+type ResourceItem struct {
+	Resource    types.Resource `json:"resource"`
+	Description string         `json:"description"`
+}
+
+// Permissions are the registered resources granted to the user, in case of api key they are
+// the resources of the key's role
+type Permissions struct {
+	UserID    uint             `json:"user_id"`
+	APIKey    bool             `json:"api_key"`
+	Resources []types.Resource `json:"resources"`
+}
@@ -0,0 +1,20 @@
+package basmodel
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// RevokedTokenTable is used inside the repo layer
+const (
+	RevokedTokenTable = "bas_revoked_tokens"
+)
+
+// RevokedToken model, keeps the jti of access tokens which are invalidated before expiration
+type RevokedToken struct {
+	gorm.Model
+	JTI       string    `gorm:"type:varchar(64);not null;unique" json:"jti"`
+	UserID    uint      `json:"user_id"`
+	ExpiresAt time.Time `gorm:"index:expires_at_idx" json:"expires_at"`
+}
@@ -3,6 +3,7 @@ package basmodel
 import (
 	"omono/internal/core/coract"
 	"omono/internal/core/corerr"
+	"omono/internal/core/corperm"
 	"omono/internal/core/corterm"
 
 	"github.com/syronz/dict"
@@ -18,9 +19,11 @@ const (
 // Role model
 type Role struct {
 	gorm.Model
-	Name        string `gorm:"not null;unique" json:"name,omitempty"`
-	Resources   string `gorm:"type:text" json:"resources,omitempty"`
-	Description string `json:"description,omitempty"`
+	Name        string        `gorm:"type:varchar(191);not null" json:"name,omitempty" search:"fulltext"`
+	Resources   corperm.Rules `gorm:"type:text" json:"resources,omitempty"`
+	Description string        `json:"description,omitempty" search:"fulltext"`
+	// ParentIDs are the roles which their resources are inherited
+	ParentIDs []uint `gorm:"-" json:"parent_ids" table:"-"`
 }
 
 // Validate check the type of fields
@@ -41,11 +44,17 @@ func (p *Role) Validate(act coract.Action) (err error) {
 				dict.R(corterm.Name), 255)
 		}
 
-		if p.Resources == "" {
+		// a role which extends other roles can have no resource of its own
+		if len(p.Resources) == 0 && len(p.ParentIDs) == 0 {
 			err = limberr.AddInvalidParam(err, "resources",
 				corerr.VisRequired, dict.R(corterm.Resources))
 		}
 
+		for _, v := range p.Resources.Invalid() {
+			err = limberr.AddInvalidParam(err, "resources",
+				corerr.VisNotValid, v)
+		}
+
 		if len(p.Description) > 255 {
 			err = limberr.AddInvalidParam(err, "description",
 				corerr.MaximumAcceptedCharacterForVisV,
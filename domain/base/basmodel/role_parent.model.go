@@ -0,0 +1,14 @@
+package basmodel
+
+// RoleParentTable is used inside the repo layer
+const (
+	RoleParentTable = "bas_role_parents"
+)
+
+// RoleParent makes the role extend the resources of the parent, the parent can extend other
+// roles as well but a role can't be the ancestor of itself
+type RoleParent struct {
+	ID       uint `gorm:"primarykey" json:"id"`
+	RoleID   uint `gorm:"not null;uniqueIndex:uniqueidx_role_parent" json:"role_id"`
+	ParentID uint `gorm:"not null;uniqueIndex:uniqueidx_role_parent;index:parent_id_idx" json:"parent_id"`
+}
@@ -0,0 +1,26 @@
+package basmodel
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// SessionTable is used inside the repo layer
+const (
+	SessionTable = "bas_sessions"
+)
+
+// Session is created in each login and it is referred by the sid claim of the tokens, the
+// refresh tokens extend it and terminating it invalidates all of its tokens
+type Session struct {
+	gorm.Model
+	UserID       uint       `gorm:"not null;index:user_id_idx" json:"user_id"`
+	IP           string     `gorm:"type:varchar(45)" json:"ip" search:"like"`
+	UserAgent    string     `gorm:"type:varchar(255)" json:"user_agent" search:"fulltext"`
+	LastSeenAt   time.Time  `json:"last_seen_at"`
+	ExpiresAt    time.Time  `gorm:"index:expires_at_idx" json:"expires_at"`
+	TerminatedAt *time.Time `json:"terminated_at,omitempty"`
+	Username     string     `gorm:"->" json:"username,omitempty" table:"bas_users.username" search:"like"`
+	Current      bool       `gorm:"-" json:"current" table:"-"`
+}
@@ -19,10 +19,10 @@ const (
 // Setting model
 type Setting struct {
 	gorm.Model
-	Property    types.Setting `gorm:"not null;unique" json:"property,omitempty"`
-	Value       string        `gorm:"type:text" json:"value,omitempty"`
+	Property    types.Setting `gorm:"not null;unique" json:"property,omitempty" search:"fulltext"`
+	Value       string        `gorm:"type:text" json:"value,omitempty" search:"fulltext"`
 	Type        string        `json:"type,omitempty"`
-	Description string        `json:"description,omitempty"`
+	Description string        `json:"description,omitempty" search:"fulltext"`
 }
 
 // Validate check the type of fields
@@ -2,7 +2,6 @@ package basmodel
 
 import (
 	"omono/domain/base/basterm"
-	"omono/internal/consts"
 	"omono/internal/core/coract"
 	"omono/internal/core/corerr"
 	"omono/internal/core/corterm"
@@ -10,6 +9,7 @@ import (
 	"omono/pkg/helper"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/syronz/dict"
 	"github.com/syronz/limberr"
@@ -23,18 +23,37 @@ const (
 
 // User model
 type User struct {
-	gorm.Model `gorm:"embedded"`
-	RoleID     uint        `gorm:"index:role_id_idx" json:"role_id"`
-	Username   string      `gorm:"not null;unique" json:"username,omitempty"`
-	Password   string      `gorm:"not null" json:"password,omitempty"`
-	Lang       dict.Lang   `gorm:"type:varchar(2);default:'en'" json:"lang,omitempty"`
-	Email      string      `json:"email,omitempty"`
-	Name       string      `gorm:"<-:false" json:"name,omitempty" table:"-"`
-	Extra      interface{} `gorm:"-" json:"user_extra,omitempty" table:"-"`
-	Resources  string      `gorm:"-" json:"resources,omitempty" table:"bas_roles.resources"`
-	Role       string      `gorm:"->" json:"role,omitempty" table:"bas_roles.name as role"`
-	Phone      string      `gorm:"-" json:"phone,omitempty" table:"-"`
-	Status     types.Enum  `gorm:"default:'active';type:enum('active','inactive','terminate')" json:"status,omitempty"`
+	gorm.Model        `gorm:"embedded"`
+	RoleID            uint        `gorm:"index:role_id_idx" json:"role_id"`
+	Username          string      `gorm:"type:varchar(191);not null" json:"username,omitempty" search:"fulltext"`
+	Password          string      `gorm:"not null" json:"password,omitempty" table:"-"`
+	Lang              dict.Lang   `gorm:"type:varchar(2);default:'en'" json:"lang,omitempty"`
+	Email             string      `json:"email,omitempty" search:"fulltext"`
+	Name              string      `gorm:"<-:false" json:"name,omitempty" table:"-"`
+	Extra             interface{} `gorm:"-" json:"user_extra,omitempty" table:"-"`
+	Resources         string      `gorm:"-" json:"resources,omitempty" table:"bas_roles.resources"`
+	Role              string      `gorm:"->" json:"role,omitempty" table:"bas_roles.name as role" search:"like"`
+	Phone             string      `gorm:"-" json:"phone,omitempty" table:"-"`
+	Status            types.Enum  `gorm:"default:'active';type:enum('active','inactive','terminate','pending_verification','pending_approval')" json:"status,omitempty"`
+	PasswordChangedAt *time.Time  `json:"password_changed_at,omitempty"`
+	// RoleIDs are all roles of the user, the RoleID is the primary one and it is always included
+	RoleIDs []uint `gorm:"-" json:"role_ids,omitempty" table:"-"`
+	// Grants are the active and upcoming temporary grants, they are filled for the profile
+	Grants []Grant `gorm:"-" json:"grants,omitempty" table:"-"`
+}
+
+// AllRoleIDs returns the primary role beside the other roles without duplication
+func (p *User) AllRoleIDs() (roleIDs []uint) {
+	exist := make(map[uint]bool, len(p.RoleIDs)+1)
+	for _, v := range append([]uint{p.RoleID}, p.RoleIDs...) {
+		if v == 0 || exist[v] {
+			continue
+		}
+		exist[v] = true
+		roleIDs = append(roleIDs, v)
+	}
+
+	return
 }
 
 // Validate check the type of
@@ -74,12 +93,7 @@ func (p *User) Validate(act coract.Action) (err error) {
 }
 
 func validateUserPassword(err error, password string) error {
-	if len(password) < consts.MinimumPasswordChar {
-		return limberr.AddInvalidParam(err, "password",
-			corerr.MinimumAcceptedCharacterForVisV,
-			dict.R(corterm.Password), consts.MinimumPasswordChar)
-	}
-	return err
+	return validatePassword(err, "password", password)
 }
 
 func validateUserUsername(err error, username string) error {
@@ -0,0 +1,32 @@
+package basmodel
+
+import (
+	"time"
+)
+
+// UserCompanyTable is used inside the repo layer
+const (
+	UserCompanyTable = "bas_user_companies"
+)
+
+// UserCompany is the membership of a user in a company, the data of the tenants are limited to
+// the active company of the user which is carried by the token
+type UserCompany struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	UserID    uint      `gorm:"not null;uniqueIndex:uniqueidx_user_company" json:"user_id"`
+	CompanyID uint      `gorm:"not null;uniqueIndex:uniqueidx_user_company;index:company_id_idx" json:"company_id"`
+	CreatedAt time.Time `json:"created_at"`
+	Company   string    `gorm:"->" json:"company,omitempty"`
+}
+
+// UserCompanies is used for replacing the companies of a user
+type UserCompanies struct {
+	CompanyIDs []uint `json:"company_ids"`
+}
+
+// Tenant is the active company of the token, the CrossTenant is just issued for the super admin
+// and it is not limited to any company
+type Tenant struct {
+	CompanyID   uint `json:"company_id"`
+	CrossTenant bool `json:"cross_tenant"`
+}
@@ -0,0 +1,19 @@
+package basmodel
+
+import (
+	"time"
+)
+
+// UserRoleTable is used inside the repo layer
+const (
+	UserRoleTable = "bas_user_roles"
+)
+
+// UserRole assigns a role to the user, the role_id of the user is always one of them and the
+// resources of the user are the union of all roles and their parents
+type UserRole struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	UserID    uint      `gorm:"not null;uniqueIndex:uniqueidx_user_role" json:"user_id"`
+	RoleID    uint      `gorm:"not null;uniqueIndex:uniqueidx_user_role;index:role_id_idx" json:"role_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
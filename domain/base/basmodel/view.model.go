@@ -0,0 +1,101 @@
+package basmodel
+
+import (
+	"omono/domain/base/basterm"
+	"omono/internal/core/coract"
+	"omono/internal/core/corerr"
+	"omono/internal/core/corterm"
+	"omono/pkg/helper/sqluri"
+	"strings"
+
+	"github.com/syronz/dict"
+	"github.com/syronz/limberr"
+	"gorm.io/gorm"
+)
+
+// ViewTable is used inside the repo layer
+const (
+	ViewTable = "bas_views"
+)
+
+// ViewEntities are the lists which accept the ?view=<id>, the entity is the path of the list
+var ViewEntities = map[string]bool{
+	"users":      true,
+	"roles":      true,
+	"settings":   true,
+	"activities": true,
+	"cities":     true,
+	"api-keys":   true,
+	"grants":     true,
+	"messages":   true,
+	"accounts":   true,
+	"phones":     true,
+	"companies":  true,
+}
+
+// View is a saved list of the user, it keeps the filter, select, sort and page size of an
+// entity. Sharing it with a role makes it visible to the members of the role but just the owner
+// can change it
+type View struct {
+	gorm.Model
+	UserID   uint   `gorm:"not null;index:user_id_idx" json:"user_id"`
+	RoleID   *uint  `gorm:"index:role_id_idx" json:"role_id,omitempty"`
+	Name     string `gorm:"not null" json:"name,omitempty" search:"fulltext"`
+	Entity   string `gorm:"not null;index:entity_idx" json:"entity,omitempty"`
+	Filter   string `gorm:"type:text" json:"filter,omitempty"`
+	Search   string `json:"search,omitempty"`
+	Select   string `gorm:"type:text" json:"select,omitempty"`
+	Sort     string `json:"sort,omitempty"`
+	PageSize int    `json:"page_size,omitempty"`
+	Username string `gorm:"->" json:"username,omitempty" table:"bas_users.username" search:"like"`
+}
+
+// Validate check the type of fields, the columns of the filter and sort are checked by the list
+// of the entity when the view is applied
+func (p *View) Validate(act coract.Action) (err error) {
+
+	switch act {
+	case coract.Save:
+		if strings.TrimSpace(p.Name) == "" {
+			err = limberr.AddInvalidParam(err, "name",
+				corerr.VisRequired, dict.R(corterm.Name))
+		}
+
+		if len(p.Name) > 255 {
+			err = limberr.AddInvalidParam(err, "name",
+				corerr.MaximumAcceptedCharacterForVisV,
+				dict.R(corterm.Name), 255)
+		}
+
+		if !ViewEntities[p.Entity] {
+			err = limberr.AddInvalidParam(err, "entity",
+				corerr.VisNotValid, dict.R(basterm.Entity))
+		}
+
+		if p.Filter != "" {
+			if _, errParse := sqluri.Parse(p.Filter); errParse != nil {
+				err = limberr.AddInvalidParam(err, "filter",
+					corerr.VisNotValid, p.Filter)
+			}
+		}
+
+		if len(p.Search) > 255 {
+			err = limberr.AddInvalidParam(err, "search",
+				corerr.MaximumAcceptedCharacterForVisV,
+				dict.R(basterm.Search), 255)
+		}
+
+		if len(p.Sort) > 255 {
+			err = limberr.AddInvalidParam(err, "sort",
+				corerr.MaximumAcceptedCharacterForVisV,
+				dict.R(basterm.Sort), 255)
+		}
+
+		if p.PageSize < 0 {
+			err = limberr.AddInvalidParam(err, "page_size",
+				corerr.VisNotValid, dict.R(basterm.PageSize))
+		}
+	}
+
+	return err
+}
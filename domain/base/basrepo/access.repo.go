@@ -3,6 +3,9 @@ package basrepo
 import (
 	"omono/domain/base/basmodel"
 	"omono/internal/core"
+	"omono/internal/core/corperm"
+
+	"gorm.io/gorm"
 )
 
 // AccessRepo for injecting engine
@@ -15,17 +18,65 @@ func ProvideAccessRepo(engine *core.Engine) AccessRepo {
 	return AccessRepo{Engine: engine}
 }
 
-// GetUserResources is used for finding all resources
-func (p *AccessRepo) GetUserResources(userID uint) (result string, err error) {
-	resources := struct {
-		Resources string
-	}{}
+// GetUserResources returns the resources of all roles of the user and their ancestors, the
+// role_id of the user is considered in case it is not copied to the bas_user_roles yet
+func (p *AccessRepo) GetUserResources(userID uint) (result []corperm.Rules, err error) {
+	var roleIDs []uint
+	if err = p.Engine.ReadDB.Raw("SELECT role_id FROM bas_user_roles WHERE user_id = ? "+
+		"UNION SELECT role_id FROM bas_users WHERE id = ?", userID, userID).
+		Scan(&roleIDs).Error; err != nil {
+		return
+	}
+
+	return p.GetRoleResources(roleIDs...)
+}
+
+// GetRoleResources returns the resources of the roles and their ancestors
+func (p *AccessRepo) GetRoleResources(roleIDs ...uint) (result []corperm.Rules, err error) {
+	if roleIDs, err = expandRoles(p.Engine.ReadDB, roleIDs); err != nil || len(roleIDs) == 0 {
+		return
+	}
+
+	var roles []struct {
+		Resources corperm.Rules
+	}
+
+	err = p.Engine.ReadDB.Table(basmodel.RoleTable).Select("bas_roles.resources").
+		Where("bas_roles.id IN (?) AND bas_roles.deleted_at IS NULL", roleIDs).Scan(&roles).Error
+
+	for _, v := range roles {
+		result = append(result, v.Resources)
+	}
+
+	return
+}
+
+// expandRoles returns the roles beside all of their ancestors, visited roles are skipped so a
+// cycle in the stored data can't make it loop
+func expandRoles(db *gorm.DB, roleIDs []uint) (expanded []uint, err error) {
+	visited := make(map[uint]bool)
+
+	for len(roleIDs) > 0 {
+		var next []uint
+		for _, v := range roleIDs {
+			if !visited[v] {
+				visited[v] = true
+				next = append(next, v)
+			}
+		}
 
-	err = p.Engine.ReadDB.Table(basmodel.UserTable).Select("bas_roles.resources").
-		Joins("INNER JOIN bas_roles ON bas_users.role_id = bas_roles.id").
-		Where("bas_users.id = ?", userID).Scan(&resources).Error
+		if len(next) == 0 {
+			break
+		}
+		expanded = append(expanded, next...)
 
-	result = resources.Resources
+		roleIDs = nil
+		if err = db.Table(basmodel.RoleParentTable).
+			Where("role_id IN (?)", next).
+			Pluck("parent_id", &roleIDs).Error; err != nil {
+			return
+		}
+	}
 
 	return
 }
@@ -10,12 +10,14 @@ import (
 	"reflect"
 
 	"github.com/syronz/limberr"
+	"gorm.io/gorm"
 )
 
 // ActivityRepo for injecting engine
 type ActivityRepo struct {
 	Engine *core.Engine
 	Cols   []string
+	Search helper.Searchable
 }
 
 // ProvideActivityRepo is used in wire
@@ -23,6 +25,7 @@ func ProvideActivityRepo(engine *core.Engine) ActivityRepo {
 	return ActivityRepo{
 		Engine: engine,
 		Cols:   helper.TagExtracter(reflect.TypeOf(basmodel.Activity{}), basmodel.ActivityTable),
+		Search: helper.SearchExtracter(reflect.TypeOf(basmodel.Activity{}), basmodel.ActivityTable),
 	}
 }
 
@@ -51,8 +54,15 @@ func (p *ActivityRepo) List(params param.Param) (activities []basmodel.Activity,
 		return
 	}
 
+	params.ScopeSearch(p.Search)
+	if err = params.ScopeSort(p.Cols); err != nil {
+		err = limberr.Take(err, "E1072933").Custom(corerr.ValidationFailedErr).Build()
+		return
+	}
+
 	var whereStr string
-	if whereStr, err = params.ParseWhere(p.Cols); err != nil {
+	var args []interface{}
+	if whereStr, args, err = params.ParseWhere(p.Cols); err != nil {
 		err = limberr.Take(err, "E9367965").Custom(corerr.ValidationFailedErr).Build()
 		return
 	}
@@ -60,19 +70,24 @@ func (p *ActivityRepo) List(params param.Param) (activities []basmodel.Activity,
 	err = p.Engine.ActivityDB.
 		Table(basmodel.ActivityTable).
 		Select(colsStr).
-		Where(whereStr).
-		Order(params.Order).
+		Where(whereStr, args...).
+		Clauses(params.OrderBy()).
 		Limit(params.Limit).
 		Offset(params.Offset).
 		Find(&activities).Error
 
+	params.Arrange(activities)
+
 	return
 }
 
 // Count of activities
 func (p *ActivityRepo) Count(params param.Param) (count int64, err error) {
+	params.ScopeSearch(p.Search)
+
 	var whereStr string
-	if whereStr, err = params.ParseWhere(p.Cols); err != nil {
+	var args []interface{}
+	if whereStr, args, err = params.ParseWhere(p.Cols); err != nil {
 		err = limberr.Take(err, "E9367965").Custom(corerr.ValidationFailedErr).Build()
 		return
 	}
@@ -80,7 +95,36 @@ func (p *ActivityRepo) Count(params param.Param) (count int64, err error) {
 	err = p.Engine.ActivityDB.
 		Table(basmodel.ActivityTable).
 		Select(params.Select).
-		Where(whereStr).
+		Where(whereStr, args...).
 		Count(&count).Error
 	return
 }
+
+// Aggregate returns the grouped rows of the activities, the conditions are the same as the List
+func (p *ActivityRepo) Aggregate(params param.Param) (rows []map[string]interface{}, err error) {
+	params.ScopeSearch(p.Search)
+
+	var aggregate func(*gorm.DB) *gorm.DB
+	if aggregate, err = params.ScopeAggregate(p.Cols); err != nil {
+		err = limberr.Take(err, "E1068128").Custom(corerr.ValidationFailedErr).Build()
+		return
+	}
+
+	var whereStr string
+	var args []interface{}
+	if whereStr, args, err = params.ParseWhere(p.Cols); err != nil {
+		err = limberr.Take(err, "E1075150").Custom(corerr.ValidationFailedErr).Build()
+		return
+	}
+
+	err = p.Engine.ActivityDB.
+		Table(basmodel.ActivityTable).
+		Where("bas_activities.deleted_at IS NULL").
+		Where(whereStr, args...).
+		Scopes(aggregate).
+		Limit(params.Limit).
+		Offset(params.Offset).
+		Find(&rows).Error
+
+	return
+}
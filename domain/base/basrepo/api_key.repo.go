@@ -0,0 +1,210 @@
+package basrepo
+
+import (
+	"omono/domain/base/basmodel"
+	"omono/domain/base/basterm"
+	"omono/internal/core"
+	"omono/internal/core/corerr"
+	"omono/internal/core/corterm"
+	"omono/internal/core/validator"
+	"omono/internal/param"
+	"omono/pkg/helper"
+	"reflect"
+	"time"
+
+	"github.com/syronz/dict"
+	"github.com/syronz/limberr"
+	"gorm.io/gorm"
+)
+
+// APIKeyRepo for injecting engine
+type APIKeyRepo struct {
+	Engine *core.Engine
+	Cols   []string
+	Search helper.Searchable
+}
+
+// ProvideAPIKeyRepo is used in wire and initiate the Cols
+func ProvideAPIKeyRepo(engine *core.Engine) APIKeyRepo {
+	return APIKeyRepo{
+		Engine: engine,
+		Cols:   helper.TagExtracter(reflect.TypeOf(basmodel.APIKey{}), basmodel.APIKeyTable),
+		Search: helper.SearchExtracter(reflect.TypeOf(basmodel.APIKey{}), basmodel.APIKeyTable),
+	}
+}
+
+// FindByID finds the api key via its id
+func (p *APIKeyRepo) FindByID(id uint) (apiKey basmodel.APIKey, err error) {
+	err = p.Engine.ReadDB.Table(basmodel.APIKeyTable).
+		Select("bas_api_keys.*, bas_users.username, bas_roles.name as role").
+		Joins("INNER JOIN bas_users ON bas_users.id = bas_api_keys.user_id").
+		Joins("INNER JOIN bas_roles ON bas_roles.id = bas_api_keys.role_id").
+		Where("bas_api_keys.id = ? AND bas_api_keys.deleted_at IS NULL", id).
+		First(&apiKey).Error
+
+	apiKey.ID = id
+	err = p.dbError(err, "E1081552", apiKey, corterm.List)
+
+	return
+}
+
+// FindByHash finds the api key via the hash of the key, owner's info is fetched as well
+func (p *APIKeyRepo) FindByHash(hash string) (apiKey basmodel.APIKey, err error) {
+	err = p.Engine.ReadDB.Table(basmodel.APIKeyTable).
+		Select("bas_api_keys.*, bas_users.username, bas_users.lang, bas_users.status as user_status, "+
+			"bas_roles.name as role").
+		Joins("INNER JOIN bas_users ON bas_users.id = bas_api_keys.user_id").
+		Joins("INNER JOIN bas_roles ON bas_roles.id = bas_api_keys.role_id").
+		Where("bas_api_keys.hash = ? AND bas_api_keys.deleted_at IS NULL", hash).
+		Where("bas_users.deleted_at IS NULL").
+		First(&apiKey).Error
+
+	err = p.dbError(err, "E1052240", apiKey, corterm.List)
+
+	return
+}
+
+// List returns an array of api keys
+func (p *APIKeyRepo) List(params param.Param) (apiKeys []basmodel.APIKey, err error) {
+	var colsStr string
+	if colsStr, err = validator.CheckColumns(p.Cols, params.Select); err != nil {
+		err = limberr.Take(err, "E1023639").Build()
+		return
+	}
+
+	params.ScopeSearch(p.Search)
+	if err = params.ScopeSort(p.Cols); err != nil {
+		err = limberr.Take(err, "E1090034").Custom(corerr.ValidationFailedErr).Build()
+		return
+	}
+
+	var whereStr string
+	var args []interface{}
+	if whereStr, args, err = params.ParseWhere(p.Cols); err != nil {
+		err = limberr.Take(err, "E1077261").Custom(corerr.ValidationFailedErr).Build()
+		return
+	}
+
+	err = p.Engine.ReadDB.Table(basmodel.APIKeyTable).Select(colsStr).
+		Joins("INNER JOIN bas_users ON bas_users.id = bas_api_keys.user_id").
+		Joins("INNER JOIN bas_roles ON bas_roles.id = bas_api_keys.role_id").
+		Where("bas_api_keys.deleted_at IS NULL").
+		Where(whereStr, args...).
+		Clauses(params.OrderBy()).
+		Limit(params.Limit).
+		Offset(params.Offset).
+		Find(&apiKeys).Error
+
+	err = p.dbError(err, "E1089738", basmodel.APIKey{}, corterm.List)
+	params.Arrange(apiKeys)
+
+	return
+}
+
+// Count of api keys, mainly calls with List
+func (p *APIKeyRepo) Count(params param.Param) (count int64, err error) {
+	params.ScopeSearch(p.Search)
+
+	var whereStr string
+	var args []interface{}
+	if whereStr, args, err = params.ParseWhere(p.Cols); err != nil {
+		err = limberr.Take(err, "E1060264").Custom(corerr.ValidationFailedErr).Build()
+		return
+	}
+
+	err = p.Engine.ReadDB.Table(basmodel.APIKeyTable).
+		Joins("INNER JOIN bas_users ON bas_users.id = bas_api_keys.user_id").
+		Joins("INNER JOIN bas_roles ON bas_roles.id = bas_api_keys.role_id").
+		Where("bas_api_keys.deleted_at IS NULL").
+		Where(whereStr, args...).
+		Count(&count).Error
+
+	err = p.dbError(err, "E1037832", basmodel.APIKey{}, corterm.List)
+	return
+}
+
+// Aggregate returns the grouped rows of the api keys, the conditions are the same as the List
+func (p *APIKeyRepo) Aggregate(params param.Param) (rows []map[string]interface{}, err error) {
+	params.ScopeSearch(p.Search)
+
+	var aggregate func(*gorm.DB) *gorm.DB
+	if aggregate, err = params.ScopeAggregate(p.Cols); err != nil {
+		err = limberr.Take(err, "E1043353").Custom(corerr.ValidationFailedErr).Build()
+		return
+	}
+
+	var whereStr string
+	var args []interface{}
+	if whereStr, args, err = params.ParseWhere(p.Cols); err != nil {
+		err = limberr.Take(err, "E1042739").Custom(corerr.ValidationFailedErr).Build()
+		return
+	}
+
+	err = p.Engine.ReadDB.Table(basmodel.APIKeyTable).
+		Joins("INNER JOIN bas_users ON bas_users.id = bas_api_keys.user_id").
+		Joins("INNER JOIN bas_roles ON bas_roles.id = bas_api_keys.role_id").
+		Where("bas_api_keys.deleted_at IS NULL").
+		Where(whereStr, args...).
+		Scopes(aggregate).
+		Limit(params.Limit).
+		Offset(params.Offset).
+		Find(&rows).Error
+
+	err = p.dbError(err, "E1028323", basmodel.APIKey{}, corterm.List)
+	return
+}
+
+// Create an api key
+func (p *APIKeyRepo) Create(apiKey basmodel.APIKey) (u basmodel.APIKey, err error) {
+	if err = p.Engine.DB.Table(basmodel.APIKeyTable).Create(&apiKey).Scan(&u).Error; err != nil {
+		err = p.dbError(err, "E1084487", apiKey, corterm.Created)
+	}
+	return
+}
+
+// Revoke set the revoked_at, the key is kept for auditing
+func (p *APIKeyRepo) Revoke(id uint) (err error) {
+	err = p.Engine.DB.Table(basmodel.APIKeyTable).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", time.Now()).Error
+
+	err = p.dbError(err, "E1060430", basmodel.APIKey{}, corterm.Updated)
+	return
+}
+
+// TouchLastUsed update the last_used_at of the key
+func (p *APIKeyRepo) TouchLastUsed(id uint, now time.Time) (err error) {
+	err = p.Engine.DB.Table(basmodel.APIKeyTable).
+		Where("id = ?", id).
+		UpdateColumn("last_used_at", now).Error
+
+	err = p.dbError(err, "E1063495", basmodel.APIKey{}, corterm.Updated)
+	return
+}
+
+// dbError is an internal method for generate proper database error
+func (p *APIKeyRepo) dbError(err error, code string, apiKey basmodel.APIKey, action string) error {
+	switch corerr.ClearDbErr(err) {
+	case corerr.Nil:
+		err = nil
+
+	case corerr.NotFoundErr:
+		err = corerr.RecordNotFoundHelper(err, code, corterm.ID, apiKey.ID, basterm.APIKeys)
+
+	case corerr.ForeignErr:
+		err = limberr.Take(err, code).
+			Message(corerr.SomeVRelatedToThisVSoItIsNotV, dict.R(basterm.Roles),
+				dict.R(basterm.APIKey), dict.R(action)).
+			Custom(corerr.ForeignErr).Build()
+
+	case corerr.ValidationFailedErr:
+		err = corerr.ValidationFailedHelper(err, code)
+
+	default:
+		err = limberr.Take(err, code).
+			Message(corerr.InternalServerError).
+			Custom(corerr.InternalServerErr).Build()
+	}
+
+	return err
+}
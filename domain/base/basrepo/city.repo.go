@@ -10,15 +10,18 @@ import (
 	"omono/internal/param"
 	"omono/pkg/helper"
 	"reflect"
+	"time"
 
 	"github.com/syronz/dict"
 	"github.com/syronz/limberr"
+	"gorm.io/gorm"
 )
 
 // CityRepo for injecting engine
 type CityRepo struct {
 	Engine *core.Engine
 	Cols   []string
+	Search helper.Searchable
 }
 
 // ProvideCityRepo is used in wire and initiate the Cols
@@ -26,6 +29,7 @@ func ProvideCityRepo(engine *core.Engine) CityRepo {
 	return CityRepo{
 		Engine: engine,
 		Cols:   helper.TagExtracter(reflect.TypeOf(basmodel.City{}), basmodel.CityTable),
+		Search: helper.SearchExtracter(reflect.TypeOf(basmodel.City{}), basmodel.CityTable),
 	}
 }
 
@@ -53,50 +57,102 @@ func (p *CityRepo) FindByCity(cityNumber string) (city basmodel.City, err error)
 
 // List returns an array of cities
 func (p *CityRepo) List(params param.Param) (cities []basmodel.City, err error) {
+	params.ScopeDeleted(basmodel.CityTable)
+
 	var colsStr string
 	if colsStr, err = validator.CheckColumns(p.Cols, params.Select); err != nil {
 		err = limberr.Take(err, "E1091738").Build()
 		return
 	}
 
+	params.ScopeSearch(p.Search)
+	if err = params.ScopeSort(p.Cols); err != nil {
+		err = limberr.Take(err, "E1085908").Custom(corerr.ValidationFailedErr).Build()
+		return
+	}
+
 	var whereStr string
-	if whereStr, err = params.ParseWhere(p.Cols); err != nil {
+	var args []interface{}
+	if whereStr, args, err = params.ParseWhere(p.Cols); err != nil {
 		err = limberr.Take(err, "E1082911").Custom(corerr.ValidationFailedErr).Build()
 		return
 	}
 
-	err = p.Engine.ReadDB.Table(basmodel.CityTable).Select(colsStr).
-		Where(whereStr).
-		Order(params.Order).
+	err = p.Engine.ReadDB.Unscoped().Table(basmodel.CityTable).Select(colsStr).
+		Where(whereStr, args...).
+		Clauses(params.OrderBy()).
 		Limit(params.Limit).
 		Offset(params.Offset).
 		Find(&cities).Error
 
 	err = p.dbError(err, "E1029474", basmodel.City{}, corterm.List)
+	params.Arrange(cities)
 
 	return
 }
 
 // Count of cities, mainly calls with List
 func (p *CityRepo) Count(params param.Param) (count int64, err error) {
+	params.ScopeDeleted(basmodel.CityTable)
+	params.ScopeSearch(p.Search)
+
 	var whereStr string
-	if whereStr, err = params.ParseWhere(p.Cols); err != nil {
+	var args []interface{}
+	if whereStr, args, err = params.ParseWhere(p.Cols); err != nil {
 		err = limberr.Take(err, "E1035100").Custom(corerr.ValidationFailedErr).Build()
 		return
 	}
 
 	err = p.Engine.ReadDB.Table(basmodel.CityTable).
-		Where(whereStr).
+		Where(whereStr, args...).
 		Count(&count).Error
 
 	err = p.dbError(err, "E1067151", basmodel.City{}, corterm.List)
 	return
 }
 
-// Save the city, in case it is not exist create it
+// Aggregate returns the grouped rows of the cities, the conditions are the same as the List
+func (p *CityRepo) Aggregate(params param.Param) (rows []map[string]interface{}, err error) {
+	params.ScopeDeleted(basmodel.CityTable)
+	params.ScopeSearch(p.Search)
+
+	var aggregate func(*gorm.DB) *gorm.DB
+	if aggregate, err = params.ScopeAggregate(p.Cols); err != nil {
+		err = limberr.Take(err, "E1053409").Custom(corerr.ValidationFailedErr).Build()
+		return
+	}
+
+	var whereStr string
+	var args []interface{}
+	if whereStr, args, err = params.ParseWhere(p.Cols); err != nil {
+		err = limberr.Take(err, "E1078144").Custom(corerr.ValidationFailedErr).Build()
+		return
+	}
+
+	err = p.Engine.ReadDB.Table(basmodel.CityTable).
+		Where(whereStr, args...).
+		Scopes(aggregate).
+		Limit(params.Limit).
+		Offset(params.Offset).
+		Find(&rows).Error
+
+	err = p.dbError(err, "E1029482", basmodel.City{}, corterm.List)
+	return
+}
+
+// Save the city, the updated_at is the version of the city which is fetched before, in case
+// another request changed the city after that it is not saved
 func (p *CityRepo) Save(city basmodel.City) (u basmodel.City, err error) {
-	if err = p.Engine.DB.Table(basmodel.CityTable).Save(&city).Error; err != nil {
+	result := p.Engine.DB.Table(basmodel.CityTable).Select("*").
+		Where("updated_at = ?", city.UpdatedAt).Save(&city)
+	if err = result.Error; err != nil {
 		err = p.dbError(err, "E1020589", city, corterm.Updated)
+		return
+	}
+
+	if result.RowsAffected == 0 {
+		err = corerr.PreconditionFailedHelper("E1070543", basterm.City)
+		return
 	}
 
 	p.Engine.DB.Table(basmodel.CityTable).Where("id = ?", city.ID).Find(&u)
@@ -113,12 +169,37 @@ func (p *CityRepo) Create(city basmodel.City) (u basmodel.City, err error) {
 
 // Delete the city
 func (p *CityRepo) Delete(city basmodel.City) (err error) {
-	if err = p.Engine.DB.Unscoped().Table(basmodel.CityTable).Delete(&city).Error; err != nil {
+	if err = p.Engine.DB.Table(basmodel.CityTable).Delete(&city).Error; err != nil {
 		err = p.dbError(err, "E1026719", city, corterm.Deleted)
 	}
 	return
 }
 
+// Restore the soft deleted city
+func (p *CityRepo) Restore(id uint) (err error) {
+	result := p.Engine.DB.Unscoped().Table(basmodel.CityTable).
+		Where("bas_cities.id = ? AND bas_cities.deleted_at IS NOT NULL", id).
+		Update("deleted_at", nil)
+	if err = result.Error; err == nil && result.RowsAffected == 0 {
+		err = gorm.ErrRecordNotFound
+	}
+
+	var city basmodel.City
+	city.ID = id
+	err = p.dbError(err, "E1018033", city, corterm.Updated)
+	return
+}
+
+// PurgeDeleted hard delete the cities which are soft deleted before the time
+func (p *CityRepo) PurgeDeleted(before time.Time) (err error) {
+	err = p.Engine.DB.Unscoped().Table(basmodel.CityTable).
+		Where("deleted_at < ?", before).
+		Delete(&basmodel.City{}).Error
+
+	err = p.dbError(err, "E1040395", basmodel.City{}, corterm.Deleted)
+	return
+}
+
 // dbError is an internal method for generate proper database error
 func (p *CityRepo) dbError(err error, code string, city basmodel.City, action string) error {
 	switch corerr.ClearDbErr(err) {
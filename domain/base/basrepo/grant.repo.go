@@ -0,0 +1,257 @@
+package basrepo
+
+import (
+	"omono/domain/base/basmodel"
+	"omono/domain/base/basterm"
+	"omono/internal/core"
+	"omono/internal/core/corerr"
+	"omono/internal/core/corperm"
+	"omono/internal/core/corterm"
+	"omono/internal/core/validator"
+	"omono/internal/param"
+	"omono/pkg/helper"
+	"reflect"
+	"time"
+
+	"github.com/syronz/dict"
+	"github.com/syronz/limberr"
+	"gorm.io/gorm"
+)
+
+// GrantRepo for injecting engine
+type GrantRepo struct {
+	Engine *core.Engine
+	Cols   []string
+	Search helper.Searchable
+}
+
+// ProvideGrantRepo is used in wire and initiate the Cols
+func ProvideGrantRepo(engine *core.Engine) GrantRepo {
+	return GrantRepo{
+		Engine: engine,
+		Cols:   helper.TagExtracter(reflect.TypeOf(basmodel.Grant{}), basmodel.GrantTable),
+		Search: helper.SearchExtracter(reflect.TypeOf(basmodel.Grant{}), basmodel.GrantTable),
+	}
+}
+
+// grantScope limits the grants to the members of the active company of the params
+const grantScope = "bas_grants.user_id IN (SELECT bas_user_companies.user_id FROM " +
+	"bas_user_companies WHERE bas_user_companies.company_id = %v)"
+
+// FindByID finds the grant via its id
+func (p *GrantRepo) FindByID(id uint) (grant basmodel.Grant, err error) {
+	err = p.Engine.ReadDB.Table(basmodel.GrantTable).
+		Select("bas_grants.*, bas_users.username, grantors.username as grantor").
+		Joins("INNER JOIN bas_users ON bas_users.id = bas_grants.user_id").
+		Joins("INNER JOIN bas_users grantors ON grantors.id = bas_grants.grantor_id").
+		Where("bas_grants.id = ? AND bas_grants.deleted_at IS NULL", id).
+		First(&grant).Error
+
+	grant.ID = id
+	err = p.dbError(err, "E1085168", grant, corterm.List)
+
+	return
+}
+
+// List returns an array of grants
+func (p *GrantRepo) List(params param.Param) (grants []basmodel.Grant, err error) {
+	params.ScopeCompany(grantScope)
+
+	var colsStr string
+	if colsStr, err = validator.CheckColumns(p.Cols, params.Select); err != nil {
+		err = limberr.Take(err, "E1057098").Build()
+		return
+	}
+
+	params.ScopeSearch(p.Search)
+	if err = params.ScopeSort(p.Cols); err != nil {
+		err = limberr.Take(err, "E1045968").Custom(corerr.ValidationFailedErr).Build()
+		return
+	}
+
+	var whereStr string
+	var args []interface{}
+	if whereStr, args, err = params.ParseWhere(p.Cols); err != nil {
+		err = limberr.Take(err, "E1036122").Custom(corerr.ValidationFailedErr).Build()
+		return
+	}
+
+	err = p.Engine.ReadDB.Table(basmodel.GrantTable).Select(colsStr).
+		Joins("INNER JOIN bas_users ON bas_users.id = bas_grants.user_id").
+		Joins("INNER JOIN bas_users grantors ON grantors.id = bas_grants.grantor_id").
+		Where("bas_grants.deleted_at IS NULL").
+		Where(whereStr, args...).
+		Clauses(params.OrderBy()).
+		Limit(params.Limit).
+		Offset(params.Offset).
+		Find(&grants).Error
+
+	err = p.dbError(err, "E1062831", basmodel.Grant{}, corterm.List)
+	params.Arrange(grants)
+
+	return
+}
+
+// Count of grants, mainly calls with List
+func (p *GrantRepo) Count(params param.Param) (count int64, err error) {
+	params.ScopeCompany(grantScope)
+	params.ScopeSearch(p.Search)
+
+	var whereStr string
+	var args []interface{}
+	if whereStr, args, err = params.ParseWhere(p.Cols); err != nil {
+		err = limberr.Take(err, "E1052347").Custom(corerr.ValidationFailedErr).Build()
+		return
+	}
+
+	err = p.Engine.ReadDB.Table(basmodel.GrantTable).
+		Joins("INNER JOIN bas_users ON bas_users.id = bas_grants.user_id").
+		Joins("INNER JOIN bas_users grantors ON grantors.id = bas_grants.grantor_id").
+		Where("bas_grants.deleted_at IS NULL").
+		Where(whereStr, args...).
+		Count(&count).Error
+
+	err = p.dbError(err, "E1032128", basmodel.Grant{}, corterm.List)
+	return
+}
+
+// Aggregate returns the grouped rows of the grants, the conditions are the same as the List
+func (p *GrantRepo) Aggregate(params param.Param) (rows []map[string]interface{}, err error) {
+	params.ScopeCompany(grantScope)
+	params.ScopeSearch(p.Search)
+
+	var aggregate func(*gorm.DB) *gorm.DB
+	if aggregate, err = params.ScopeAggregate(p.Cols); err != nil {
+		err = limberr.Take(err, "E1023442").Custom(corerr.ValidationFailedErr).Build()
+		return
+	}
+
+	var whereStr string
+	var args []interface{}
+	if whereStr, args, err = params.ParseWhere(p.Cols); err != nil {
+		err = limberr.Take(err, "E1054578").Custom(corerr.ValidationFailedErr).Build()
+		return
+	}
+
+	err = p.Engine.ReadDB.Table(basmodel.GrantTable).
+		Joins("INNER JOIN bas_users ON bas_users.id = bas_grants.user_id").
+		Joins("INNER JOIN bas_users grantors ON grantors.id = bas_grants.grantor_id").
+		Where("bas_grants.deleted_at IS NULL").
+		Where(whereStr, args...).
+		Scopes(aggregate).
+		Limit(params.Limit).
+		Offset(params.Offset).
+		Find(&rows).Error
+
+	err = p.dbError(err, "E1088329", basmodel.Grant{}, corterm.List)
+	return
+}
+
+// ListByUser returns the active and upcoming grants of the user
+func (p *GrantRepo) ListByUser(userID uint, now time.Time) (grants []basmodel.Grant, err error) {
+	err = p.Engine.ReadDB.Table(basmodel.GrantTable).
+		Select("bas_grants.*, bas_users.username, grantors.username as grantor").
+		Joins("INNER JOIN bas_users ON bas_users.id = bas_grants.user_id").
+		Joins("INNER JOIN bas_users grantors ON grantors.id = bas_grants.grantor_id").
+		Where("bas_grants.user_id = ? AND bas_grants.ends_at > ?", userID, now).
+		Where("bas_grants.revoked_at IS NULL AND bas_grants.deleted_at IS NULL").
+		Order("bas_grants.starts_at ASC").
+		Find(&grants).Error
+
+	err = p.dbError(err, "E1077342", basmodel.Grant{}, corterm.List)
+	return
+}
+
+// ActiveResources returns the resources of the grants which are active at the moment, until is
+// the nearest time which one of the grants starts or ends and it is nil if there is no grant
+func (p *GrantRepo) ActiveResources(userID uint, now time.Time) (rules []corperm.Rules,
+	until *time.Time, err error) {
+	var grants []basmodel.Grant
+	if grants, err = p.ListByUser(userID, now); err != nil {
+		return
+	}
+
+	for _, v := range grants {
+		change := v.EndsAt
+		if v.IsActive(now) {
+			rules = append(rules, v.Resources)
+		} else {
+			change = v.StartsAt
+		}
+
+		if until == nil || change.Before(*until) {
+			until = &change
+		}
+	}
+
+	return
+}
+
+// Create a grant
+func (p *GrantRepo) Create(grant basmodel.Grant) (u basmodel.Grant, err error) {
+	if err = p.Engine.DB.Table(basmodel.GrantTable).Create(&grant).Scan(&u).Error; err != nil {
+		err = p.dbError(err, "E1029871", grant, corterm.Created)
+	}
+	return
+}
+
+// Revoke set the revoked_at, the grant is kept for auditing
+func (p *GrantRepo) Revoke(id uint, now time.Time) (err error) {
+	err = p.Engine.DB.Table(basmodel.GrantTable).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", now).Error
+
+	err = p.dbError(err, "E1056399", basmodel.Grant{}, corterm.Updated)
+	return
+}
+
+// Expired returns the grants which are ended but not marked as expired yet
+func (p *GrantRepo) Expired(now time.Time) (grants []basmodel.Grant, err error) {
+	err = p.Engine.ReadDB.Table(basmodel.GrantTable).
+		Select("bas_grants.*, bas_users.username, grantors.username as grantor").
+		Joins("INNER JOIN bas_users ON bas_users.id = bas_grants.user_id").
+		Joins("INNER JOIN bas_users grantors ON grantors.id = bas_grants.grantor_id").
+		Where("bas_grants.ends_at <= ? AND bas_grants.expired_at IS NULL", now).
+		Where("bas_grants.revoked_at IS NULL AND bas_grants.deleted_at IS NULL").
+		Find(&grants).Error
+
+	err = p.dbError(err, "E1064025", basmodel.Grant{}, corterm.List)
+	return
+}
+
+// MarkExpired set the expired_at of the grants
+func (p *GrantRepo) MarkExpired(ids []uint, now time.Time) (err error) {
+	err = p.Engine.DB.Table(basmodel.GrantTable).
+		Where("id IN (?)", ids).
+		UpdateColumn("expired_at", now).Error
+
+	err = p.dbError(err, "E1022744", basmodel.Grant{}, corterm.Updated)
+	return
+}
+
+// dbError is an internal method for generate proper database error
+func (p *GrantRepo) dbError(err error, code string, grant basmodel.Grant, action string) error {
+	switch corerr.ClearDbErr(err) {
+	case corerr.Nil:
+		err = nil
+
+	case corerr.NotFoundErr:
+		err = corerr.RecordNotFoundHelper(err, code, corterm.ID, grant.ID, basterm.Grants)
+
+	case corerr.ForeignErr:
+		err = limberr.Take(err, code).
+			Message(corerr.SomeVRelatedToThisVSoItIsNotV, dict.R(basterm.Users),
+				dict.R(basterm.Grant), dict.R(action)).
+			Custom(corerr.ForeignErr).Build()
+
+	case corerr.ValidationFailedErr:
+		err = corerr.ValidationFailedHelper(err, code)
+
+	default:
+		err = limberr.Take(err, code).
+			Message(corerr.InternalServerError).
+			Custom(corerr.InternalServerErr).Build()
+	}
+
+	return err
+}
@@ -0,0 +1,97 @@
+package basrepo
+
+import (
+	"omono/domain/base/basmodel"
+	"omono/internal/core"
+	"omono/internal/core/corerr"
+	"time"
+
+	"github.com/syronz/limberr"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// LoginAttemptRepo for injecting engine, it counts the failed logins
+type LoginAttemptRepo struct {
+	Engine *core.Engine
+}
+
+// ProvideLoginAttemptRepo is used in wire
+func ProvideLoginAttemptRepo(engine *core.Engine) LoginAttemptRepo {
+	return LoginAttemptRepo{Engine: engine}
+}
+
+// Find returns the attempts for the username or IP, an empty record returned in case of not exist
+func (p *LoginAttemptRepo) Find(kind, identifier string) (attempt basmodel.LoginAttempt, err error) {
+	err = p.Engine.DB.Table(basmodel.LoginAttemptTable).
+		Where("kind = ? AND identifier = ?", kind, identifier).
+		Limit(1).
+		Find(&attempt).Error
+
+	err = p.dbError(err, "E1051393")
+	return
+}
+
+// Increase add one to the failures atomically, in case the last failure is older than the window
+// the counting starts from one
+func (p *LoginAttemptRepo) Increase(kind, identifier string, window time.Time) (err error) {
+	now := time.Now()
+	attempt := basmodel.LoginAttempt{
+		Kind:         kind,
+		Identifier:   identifier,
+		Failures:     1,
+		LastFailedAt: now,
+	}
+
+	err = p.Engine.DB.Table(basmodel.LoginAttemptTable).
+		Clauses(clause.OnConflict{
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"failures":       gorm.Expr("IF(last_failed_at < ?, 1, failures + 1)", window),
+				"last_failed_at": now,
+				"updated_at":     now,
+			}),
+		}).
+		Create(&attempt).Error
+
+	err = p.dbError(err, "E1019776")
+	return
+}
+
+// Lock set the locked_until and reset the failures
+func (p *LoginAttemptRepo) Lock(kind, identifier string, until time.Time) (err error) {
+	err = p.Engine.DB.Table(basmodel.LoginAttemptTable).
+		Where("kind = ? AND identifier = ?", kind, identifier).
+		Updates(map[string]interface{}{"locked_until": until, "failures": 0}).Error
+
+	err = p.dbError(err, "E1073184")
+	return
+}
+
+// Reset remove the failures and the lock
+func (p *LoginAttemptRepo) Reset(kind, identifier string) (err error) {
+	err = p.Engine.DB.Table(basmodel.LoginAttemptTable).
+		Where("kind = ? AND identifier = ?", kind, identifier).
+		Delete(&basmodel.LoginAttempt{}).Error
+
+	err = p.dbError(err, "E1052068")
+	return
+}
+
+// dbError is an internal method for generate proper database error
+func (p *LoginAttemptRepo) dbError(err error, code string) error {
+	switch corerr.ClearDbErr(err) {
+	case corerr.Nil:
+		err = nil
+
+	case corerr.ValidationFailedErr:
+		err = corerr.ValidationFailedHelper(err, code)
+
+	default:
+		err = limberr.Take(err, code).
+			Message(corerr.InternalServerError).
+			Custom(corerr.InternalServerErr).Build()
+	}
+
+	return err
+}
@@ -0,0 +1,145 @@
+package basrepo
+
+import (
+	"omono/domain/base/basmodel"
+	"omono/domain/base/basterm"
+	"omono/internal/core"
+	"omono/internal/core/corerr"
+	"time"
+
+	"github.com/syronz/limberr"
+
+	"gorm.io/gorm"
+)
+
+// MFARepo for injecting engine, it keeps TOTP secrets and recovery codes
+type MFARepo struct {
+	Engine *core.Engine
+}
+
+// ProvideMFARepo is used in wire
+func ProvideMFARepo(engine *core.Engine) MFARepo {
+	return MFARepo{Engine: engine}
+}
+
+// FindByUserID finds the MFA record of the user
+func (p *MFARepo) FindByUserID(userID uint) (mfa basmodel.MFA, err error) {
+	err = p.Engine.ReadDB.Table(basmodel.MFATable).
+		Where("user_id = ? AND deleted_at IS NULL", userID).
+		First(&mfa).Error
+
+	err = p.dbError(err, "E1053313")
+
+	return
+}
+
+// Save create or replace the pending secret of the user
+func (p *MFARepo) Save(mfa basmodel.MFA) (err error) {
+	err = p.Engine.DB.Unscoped().Table(basmodel.MFATable).
+		Where("user_id = ?", mfa.UserID).
+		Delete(&basmodel.MFA{}).Error
+	if err = p.dbError(err, "E1059128"); err != nil {
+		return
+	}
+
+	err = p.Engine.DB.Table(basmodel.MFATable).Create(&mfa).Error
+	err = p.dbError(err, "E1044409")
+
+	return
+}
+
+// TxEnable activate the two-factor authentication after confirmation, the step of the confirmed
+// code is saved as used
+func (p *MFARepo) TxEnable(db *gorm.DB, id uint, step int64) (err error) {
+	err = db.Table(basmodel.MFATable).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{"enabled": true, "confirmed_at": time.Now(),
+			"last_step": step}).Error
+
+	err = p.dbError(err, "E1091157")
+	return
+}
+
+// TxReplaceRecoveryCodes delete the old recovery codes and save the new ones
+func (p *MFARepo) TxReplaceRecoveryCodes(db *gorm.DB, userID uint, hashes []string) (err error) {
+	err = db.Unscoped().Table(basmodel.RecoveryCodeTable).
+		Where("user_id = ?", userID).
+		Delete(&basmodel.RecoveryCode{}).Error
+	if err = p.dbError(err, "E1044691"); err != nil {
+		return
+	}
+
+	codes := make([]basmodel.RecoveryCode, len(hashes))
+	for i, v := range hashes {
+		codes[i] = basmodel.RecoveryCode{UserID: userID, Code: v}
+	}
+
+	err = db.Table(basmodel.RecoveryCodeTable).Create(&codes).Error
+	err = p.dbError(err, "E1051676")
+
+	return
+}
+
+// UseStep save the time-step of the accepted code, used is false if the same or a newer step
+// was accepted before, so each code is accepted once
+func (p *MFARepo) UseStep(id uint, step int64) (used bool, err error) {
+	result := p.Engine.DB.Table(basmodel.MFATable).
+		Where("id = ? AND last_step < ?", id, step).
+		Update("last_step", step)
+
+	err = p.dbError(result.Error, "E1085878")
+	used = result.RowsAffected > 0
+	return
+}
+
+// UseRecoveryCode mark the code as used, used is false if the code not exist or used before
+func (p *MFARepo) UseRecoveryCode(userID uint, hash string) (used bool, err error) {
+	result := p.Engine.DB.Table(basmodel.RecoveryCodeTable).
+		Where("user_id = ? AND code = ? AND used_at IS NULL AND deleted_at IS NULL", userID, hash).
+		Update("used_at", time.Now())
+
+	err = p.dbError(result.Error, "E1034383")
+	used = result.RowsAffected > 0
+	return
+}
+
+// Delete remove the secret and recovery codes of the user
+func (p *MFARepo) Delete(userID uint) (err error) {
+	err = p.Engine.DB.Transaction(func(db *gorm.DB) error {
+		if err := db.Unscoped().Table(basmodel.RecoveryCodeTable).
+			Where("user_id = ?", userID).
+			Delete(&basmodel.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+
+		return db.Unscoped().Table(basmodel.MFATable).
+			Where("user_id = ?", userID).
+			Delete(&basmodel.MFA{}).Error
+	})
+
+	err = p.dbError(err, "E1014271")
+	return
+}
+
+// dbError is an internal method for generate proper database error
+func (p *MFARepo) dbError(err error, code string) error {
+	switch corerr.ClearDbErr(err) {
+	case corerr.Nil:
+		err = nil
+
+	case corerr.NotFoundErr:
+		err = limberr.Take(err, code).
+			Message(basterm.TwoFactorAuthenticationIsNotEnabled).
+			Custom(corerr.NotFoundErr).Build()
+
+	case corerr.ValidationFailedErr:
+		err = corerr.ValidationFailedHelper(err, code)
+
+	default:
+		err = limberr.Take(err, code).
+			Message(corerr.InternalServerError).
+			Custom(corerr.InternalServerErr).Build()
+	}
+
+	return err
+}
@@ -0,0 +1,113 @@
+package basrepo
+
+import (
+	"omono/domain/base/basmodel"
+	"omono/domain/base/basterm"
+	"omono/internal/core"
+	"omono/internal/core/corerr"
+	"time"
+
+	"github.com/syronz/limberr"
+
+	"gorm.io/gorm"
+)
+
+// PasswordRepo for injecting engine, it keeps the password reset tokens
+type PasswordRepo struct {
+	Engine *core.Engine
+}
+
+// ProvidePasswordRepo is used in wire
+func ProvidePasswordRepo(engine *core.Engine) PasswordRepo {
+	return PasswordRepo{Engine: engine}
+}
+
+// CreateReset save the hashed reset token, previous unused tokens of the user are expired
+func (p *PasswordRepo) CreateReset(reset basmodel.PasswordReset) (err error) {
+	err = p.Engine.DB.Transaction(func(db *gorm.DB) error {
+		if err := db.Table(basmodel.PasswordResetTable).
+			Where("user_id = ? AND used_at IS NULL", reset.UserID).
+			Update("used_at", time.Now()).Error; err != nil {
+			return err
+		}
+
+		return db.Table(basmodel.PasswordResetTable).Create(&reset).Error
+	})
+
+	err = p.dbError(err, "E1024499")
+	return
+}
+
+// TxFindReset finds the reset token via its hash
+func (p *PasswordRepo) TxFindReset(db *gorm.DB, hash string) (reset basmodel.PasswordReset, err error) {
+	err = db.Table(basmodel.PasswordResetTable).
+		Where("token = ? AND deleted_at IS NULL", hash).
+		First(&reset).Error
+
+	err = p.dbError(err, "E1052981")
+	return
+}
+
+// TxUseReset mark the token as used, used is false in case another request used it before
+func (p *PasswordRepo) TxUseReset(db *gorm.DB, id uint) (used bool, err error) {
+	result := db.Table(basmodel.PasswordResetTable).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", time.Now())
+
+	err = p.dbError(result.Error, "E1086347")
+	used = result.RowsAffected > 0
+	return
+}
+
+// TxCreateHistory save the hash of the password in the history
+func (p *PasswordRepo) TxCreateHistory(db *gorm.DB, history basmodel.PasswordHistory) (err error) {
+	err = db.Table(basmodel.PasswordHistoryTable).Create(&history).Error
+	err = p.dbError(err, "E1048643")
+	return
+}
+
+// TxLastHistories returns the last n passwords of the user
+func (p *PasswordRepo) TxLastHistories(db *gorm.DB, userID uint,
+	n int) (histories []basmodel.PasswordHistory, err error) {
+	err = db.Table(basmodel.PasswordHistoryTable).
+		Where("user_id = ?", userID).
+		Order("id DESC").
+		Limit(n).
+		Find(&histories).Error
+
+	err = p.dbError(err, "E1014235")
+	return
+}
+
+// PurgeExpired hard delete the reset tokens which passed their expiration time
+func (p *PasswordRepo) PurgeExpired(now time.Time) (err error) {
+	err = p.Engine.DB.Unscoped().Table(basmodel.PasswordResetTable).
+		Where("expires_at < ?", now).
+		Delete(&basmodel.PasswordReset{}).Error
+
+	err = p.dbError(err, "E1060027")
+	return
+}
+
+// dbError is an internal method for generate proper database error
+func (p *PasswordRepo) dbError(err error, code string) error {
+	switch corerr.ClearDbErr(err) {
+	case corerr.Nil:
+		err = nil
+
+	case corerr.NotFoundErr:
+		err = limberr.Take(err, code).
+			Message(basterm.ResetTokenIsNotValid).
+			Custom(corerr.UnauthorizedErr).Build()
+
+	case corerr.ValidationFailedErr:
+		err = corerr.ValidationFailedHelper(err, code)
+
+	default:
+		err = limberr.Take(err, code).
+			Message(corerr.InternalServerError).
+			Custom(corerr.InternalServerErr).Build()
+	}
+
+	return err
+}
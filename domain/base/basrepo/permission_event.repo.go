@@ -0,0 +1,71 @@
+package basrepo
+
+import (
+	"omono/domain/base/basmodel"
+	"omono/internal/core"
+	"omono/internal/core/corerr"
+	"time"
+
+	"github.com/syronz/limberr"
+)
+
+// PermissionEventRepo for injecting engine, it keeps the invalidations of the permission cache
+type PermissionEventRepo struct {
+	Engine *core.Engine
+}
+
+// ProvidePermissionEventRepo is used in wire
+func ProvidePermissionEventRepo(engine *core.Engine) PermissionEventRepo {
+	return PermissionEventRepo{Engine: engine}
+}
+
+// Create add an invalidation event
+func (p *PermissionEventRepo) Create(event basmodel.PermissionEvent) (err error) {
+	err = p.Engine.DB.Table(basmodel.PermissionEventTable).Create(&event).Error
+	err = p.dbError(err, "E1013101")
+	return
+}
+
+// LastID returns the id of the latest event, it is zero in case the table is empty
+func (p *PermissionEventRepo) LastID() (id uint, err error) {
+	err = p.Engine.DB.Table(basmodel.PermissionEventTable).
+		Select("COALESCE(MAX(id), 0)").
+		Row().Scan(&id)
+	err = p.dbError(err, "E1060282")
+	return
+}
+
+// ListAfter returns the events which are created after the id, the write database is used
+// because the replica may be behind
+func (p *PermissionEventRepo) ListAfter(id uint) (events []basmodel.PermissionEvent, err error) {
+	err = p.Engine.DB.Table(basmodel.PermissionEventTable).
+		Where("id > ?", id).
+		Order("id ASC").
+		Find(&events).Error
+	err = p.dbError(err, "E1022533")
+	return
+}
+
+// PurgeBefore delete the events which are delivered to all instances
+func (p *PermissionEventRepo) PurgeBefore(t time.Time) (err error) {
+	err = p.Engine.DB.Table(basmodel.PermissionEventTable).
+		Where("created_at < ?", t).
+		Delete(&basmodel.PermissionEvent{}).Error
+	err = p.dbError(err, "E1080194")
+	return
+}
+
+// dbError is an internal method for generate proper database error
+func (p *PermissionEventRepo) dbError(err error, code string) error {
+	switch corerr.ClearDbErr(err) {
+	case corerr.Nil:
+		err = nil
+
+	default:
+		err = limberr.Take(err, code).
+			Message(corerr.InternalServerError).
+			Custom(corerr.InternalServerErr).Build()
+	}
+
+	return err
+}
@@ -0,0 +1,94 @@
+package basrepo
+
+import (
+	"omono/domain/base/basmodel"
+	"omono/domain/base/basterm"
+	"omono/internal/core"
+	"omono/internal/core/corerr"
+	"time"
+
+	"github.com/syronz/limberr"
+
+	"gorm.io/gorm"
+)
+
+// RegistrationRepo for injecting engine, it keeps the email verification tokens
+type RegistrationRepo struct {
+	Engine *core.Engine
+}
+
+// ProvideRegistrationRepo is used in wire
+func ProvideRegistrationRepo(engine *core.Engine) RegistrationRepo {
+	return RegistrationRepo{Engine: engine}
+}
+
+// CreateVerification save the hashed token, previous unused tokens of the user are expired
+func (p *RegistrationRepo) CreateVerification(verification basmodel.EmailVerification) (err error) {
+	err = p.Engine.DB.Transaction(func(db *gorm.DB) error {
+		if err := db.Table(basmodel.EmailVerificationTable).
+			Where("user_id = ? AND used_at IS NULL", verification.UserID).
+			Update("used_at", time.Now()).Error; err != nil {
+			return err
+		}
+
+		return db.Table(basmodel.EmailVerificationTable).Create(&verification).Error
+	})
+
+	err = p.dbError(err, "E1020508")
+	return
+}
+
+// TxFindVerification finds the verification via the hash of the token
+func (p *RegistrationRepo) TxFindVerification(db *gorm.DB,
+	hash string) (verification basmodel.EmailVerification, err error) {
+	err = db.Table(basmodel.EmailVerificationTable).
+		Where("token = ? AND deleted_at IS NULL", hash).
+		First(&verification).Error
+
+	err = p.dbError(err, "E1052324")
+	return
+}
+
+// TxUseVerification mark the token as used, used is false in case another request used it before
+func (p *RegistrationRepo) TxUseVerification(db *gorm.DB, id uint) (used bool, err error) {
+	result := db.Table(basmodel.EmailVerificationTable).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", time.Now())
+
+	err = p.dbError(result.Error, "E1050553")
+	used = result.RowsAffected > 0
+	return
+}
+
+// PurgeExpired hard delete the expired verification tokens
+func (p *RegistrationRepo) PurgeExpired(now time.Time) (err error) {
+	err = p.Engine.DB.Unscoped().Table(basmodel.EmailVerificationTable).
+		Where("expires_at < ?", now).
+		Delete(&basmodel.EmailVerification{}).Error
+
+	err = p.dbError(err, "E1037131")
+	return
+}
+
+// dbError is an internal method for generate proper database error
+func (p *RegistrationRepo) dbError(err error, code string) error {
+	switch corerr.ClearDbErr(err) {
+	case corerr.Nil:
+		err = nil
+
+	case corerr.NotFoundErr:
+		err = limberr.Take(err, code).
+			Message(basterm.VerificationTokenIsNotValid).
+			Custom(corerr.UnauthorizedErr).Build()
+
+	case corerr.ValidationFailedErr:
+		err = corerr.ValidationFailedHelper(err, code)
+
+	default:
+		err = limberr.Take(err, code).
+			Message(corerr.InternalServerError).
+			Custom(corerr.InternalServerErr).Build()
+	}
+
+	return err
+}
@@ -10,6 +10,7 @@ import (
 	"omono/internal/param"
 	"omono/pkg/helper"
 	"reflect"
+	"time"
 
 	"github.com/syronz/dict"
 	"github.com/syronz/limberr"
@@ -21,6 +22,7 @@ import (
 type RoleRepo struct {
 	Engine *core.Engine
 	Cols   []string
+	Search helper.Searchable
 }
 
 // ProvideRoleRepo is used in wire and initiate the Cols
@@ -28,6 +30,7 @@ func ProvideRoleRepo(engine *core.Engine) RoleRepo {
 	return RoleRepo{
 		Engine: engine,
 		Cols:   helper.TagExtracter(reflect.TypeOf(basmodel.Role{}), basmodel.RoleTable),
+		Search: helper.SearchExtracter(reflect.TypeOf(basmodel.Role{}), basmodel.RoleTable),
 	}
 }
 
@@ -45,46 +48,89 @@ func (p *RoleRepo) FindByID(id uint) (role basmodel.Role, err error) {
 
 // List returns an array of roles
 func (p *RoleRepo) List(params param.Param) (roles []basmodel.Role, err error) {
+	params.ScopeDeleted(basmodel.RoleTable)
+
 	var colsStr string
 	if colsStr, err = validator.CheckColumns(p.Cols, params.Select); err != nil {
 		err = limberr.Take(err, "E1084438").Build()
 		return
 	}
 
+	params.ScopeSearch(p.Search)
+	if err = params.ScopeSort(p.Cols); err != nil {
+		err = limberr.Take(err, "E1086320").Custom(corerr.ValidationFailedErr).Build()
+		return
+	}
+
 	var whereStr string
-	if whereStr, err = params.ParseWhere(p.Cols); err != nil {
+	var args []interface{}
+	if whereStr, args, err = params.ParseWhere(p.Cols); err != nil {
 		err = limberr.Take(err, "E1032278").Custom(corerr.ValidationFailedErr).Build()
 		return
 	}
 
-	err = p.Engine.ReadDB.Table(basmodel.RoleTable).Select(colsStr).
-		Where(whereStr).
-		Order(params.Order).
+	err = p.Engine.ReadDB.Unscoped().Table(basmodel.RoleTable).Select(colsStr).
+		Where(whereStr, args...).
+		Clauses(params.OrderBy()).
 		Limit(params.Limit).
 		Offset(params.Offset).
 		Find(&roles).Error
 
 	err = p.dbError(err, "E1032861", basmodel.Role{}, corterm.List)
+	params.Arrange(roles)
 
 	return
 }
 
 // Count of roles, mainly calls with List
 func (p *RoleRepo) Count(params param.Param) (count int64, err error) {
+	params.ScopeDeleted(basmodel.RoleTable)
+	params.ScopeSearch(p.Search)
+
 	var whereStr string
-	if whereStr, err = params.ParseWhere(p.Cols); err != nil {
+	var args []interface{}
+	if whereStr, args, err = params.ParseWhere(p.Cols); err != nil {
 		err = limberr.Take(err, "E1032288").Custom(corerr.ValidationFailedErr).Build()
 		return
 	}
 
 	err = p.Engine.ReadDB.Table(basmodel.RoleTable).
-		Where(whereStr).
+		Where(whereStr, args...).
 		Count(&count).Error
 
 	err = p.dbError(err, "E1039820", basmodel.Role{}, corterm.List)
 	return
 }
 
+// Aggregate returns the grouped rows of the roles, the conditions are the same as the List
+func (p *RoleRepo) Aggregate(params param.Param) (rows []map[string]interface{}, err error) {
+	params.ScopeDeleted(basmodel.RoleTable)
+	params.ScopeSearch(p.Search)
+
+	var aggregate func(*gorm.DB) *gorm.DB
+	if aggregate, err = params.ScopeAggregate(p.Cols); err != nil {
+		err = limberr.Take(err, "E1059156").Custom(corerr.ValidationFailedErr).Build()
+		return
+	}
+
+	var whereStr string
+	var args []interface{}
+	if whereStr, args, err = params.ParseWhere(p.Cols); err != nil {
+		err = limberr.Take(err, "E1028646").Custom(corerr.ValidationFailedErr).Build()
+		return
+	}
+
+	err = p.Engine.ReadDB.Table(basmodel.RoleTable).
+		Where(whereStr, args...).
+		Scopes(aggregate).
+		Limit(params.Limit).
+		Offset(params.Offset).
+		Find(&rows).Error
+
+	err = p.dbError(err, "E1034784", basmodel.Role{}, corterm.List)
+	return
+}
+
 // Save the role, in case it is not exist create it
 func (p *RoleRepo) Save(role basmodel.Role) (u basmodel.Role, err error) {
 	if err = p.Engine.DB.Table(basmodel.RoleTable).Save(&role).Error; err != nil {
@@ -95,6 +141,25 @@ func (p *RoleRepo) Save(role basmodel.Role) (u basmodel.Role, err error) {
 	return
 }
 
+// TxSave the role, the updated_at is the version of the role which is fetched before, in case
+// another request changed the role after that it is not saved
+func (p *RoleRepo) TxSave(db *gorm.DB, role basmodel.Role) (u basmodel.Role, err error) {
+	result := db.Table(basmodel.RoleTable).Select("*").
+		Where("updated_at = ?", role.UpdatedAt).Save(&role)
+	if err = result.Error; err != nil {
+		err = p.dbError(err, "E1097528", role, corterm.Updated)
+		return
+	}
+
+	if result.RowsAffected == 0 {
+		err = corerr.PreconditionFailedHelper("E1043840", basterm.Role)
+		return
+	}
+
+	db.Table(basmodel.RoleTable).Where("id = ?", role.ID).Find(&u)
+	return
+}
+
 // TxCreate a role
 func (p *RoleRepo) TxCreate(db *gorm.DB, role basmodel.Role) (u basmodel.Role, err error) {
 	if err = db.Table(basmodel.RoleTable).Create(&role).Scan(&u).Error; err != nil {
@@ -105,12 +170,75 @@ func (p *RoleRepo) TxCreate(db *gorm.DB, role basmodel.Role) (u basmodel.Role, e
 
 // Delete the role
 func (p *RoleRepo) Delete(role basmodel.Role) (err error) {
-	if err = p.Engine.DB.Unscoped().Table(basmodel.RoleTable).Delete(&role).Error; err != nil {
+	if err = p.Engine.DB.Table(basmodel.RoleTable).Delete(&role).Error; err != nil {
 		err = p.dbError(err, "E1067392", role, corterm.Deleted)
 	}
 	return
 }
 
+// Restore the soft deleted role
+func (p *RoleRepo) Restore(id uint) (err error) {
+	result := p.Engine.DB.Unscoped().Table(basmodel.RoleTable).
+		Where("bas_roles.id = ? AND bas_roles.deleted_at IS NOT NULL", id).
+		Update("deleted_at", nil)
+	if err = result.Error; err == nil && result.RowsAffected == 0 {
+		err = gorm.ErrRecordNotFound
+	}
+
+	var role basmodel.Role
+	role.ID = id
+	err = p.dbError(err, "E1050165", role, corterm.Updated)
+	return
+}
+
+// PurgeDeleted hard delete the roles which are soft deleted before the time
+func (p *RoleRepo) PurgeDeleted(before time.Time) (err error) {
+	err = p.Engine.DB.Unscoped().Table(basmodel.RoleTable).
+		Where("deleted_at < ?", before).
+		Delete(&basmodel.Role{}).Error
+
+	err = p.dbError(err, "E1083101", basmodel.Role{}, corterm.Deleted)
+	return
+}
+
+// ParentIDs returns the roles which are extended by the role
+func (p *RoleRepo) ParentIDs(roleID uint) (parentIDs []uint, err error) {
+	err = p.Engine.ReadDB.Table(basmodel.RoleParentTable).
+		Where("role_id = ?", roleID).
+		Order("parent_id ASC").
+		Pluck("parent_id", &parentIDs).Error
+
+	err = p.dbError(err, "E1087869", basmodel.Role{}, corterm.List)
+	return
+}
+
+// Ancestors returns the roles beside their parents and the parents of the parents
+func (p *RoleRepo) Ancestors(roleIDs []uint) (ancestors []uint, err error) {
+	ancestors, err = expandRoles(p.Engine.ReadDB, roleIDs)
+	err = p.dbError(err, "E1069248", basmodel.Role{}, corterm.List)
+	return
+}
+
+// TxReplaceParents set the parents of the role
+func (p *RoleRepo) TxReplaceParents(db *gorm.DB, roleID uint, parentIDs []uint) (err error) {
+	if err = db.Table(basmodel.RoleParentTable).
+		Where("role_id = ?", roleID).
+		Delete(&basmodel.RoleParent{}).Error; err != nil {
+		err = p.dbError(err, "E1048421", basmodel.Role{ParentIDs: parentIDs}, corterm.Updated)
+		return
+	}
+
+	for _, v := range parentIDs {
+		if err = db.Table(basmodel.RoleParentTable).
+			Create(&basmodel.RoleParent{RoleID: roleID, ParentID: v}).Error; err != nil {
+			err = p.dbError(err, "E1083231", basmodel.Role{ParentIDs: parentIDs}, corterm.Updated)
+			return
+		}
+	}
+
+	return
+}
+
 // dbError is an internal method for generate proper database error
 func (p *RoleRepo) dbError(err error, code string, role basmodel.Role, action string) error {
 	switch corerr.ClearDbErr(err) {
@@ -0,0 +1,188 @@
+package basrepo
+
+import (
+	"omono/domain/base/basmodel"
+	"omono/domain/base/basterm"
+	"omono/internal/core"
+	"omono/internal/core/corerr"
+	"omono/internal/core/corterm"
+	"omono/internal/core/validator"
+	"omono/internal/param"
+	"omono/pkg/helper"
+	"reflect"
+	"time"
+
+	"github.com/syronz/limberr"
+	"gorm.io/gorm"
+)
+
+// SessionRepo for injecting engine
+type SessionRepo struct {
+	Engine *core.Engine
+	Cols   []string
+	Search helper.Searchable
+}
+
+// ProvideSessionRepo is used in wire and initiate the Cols
+func ProvideSessionRepo(engine *core.Engine) SessionRepo {
+	return SessionRepo{
+		Engine: engine,
+		Cols:   helper.TagExtracter(reflect.TypeOf(basmodel.Session{}), basmodel.SessionTable),
+		Search: helper.SearchExtracter(reflect.TypeOf(basmodel.Session{}), basmodel.SessionTable),
+	}
+}
+
+// FindByID finds the session via its id
+func (p *SessionRepo) FindByID(id uint) (session basmodel.Session, err error) {
+	err = p.Engine.ReadDB.Table(basmodel.SessionTable).
+		Select("bas_sessions.*, bas_users.username").
+		Joins("INNER JOIN bas_users ON bas_users.id = bas_sessions.user_id").
+		Where("bas_sessions.id = ? AND bas_sessions.deleted_at IS NULL", id).
+		First(&session).Error
+
+	session.ID = id
+	err = p.dbError(err, "E1028717", session)
+
+	return
+}
+
+// List returns an array of sessions
+func (p *SessionRepo) List(params param.Param) (sessions []basmodel.Session, err error) {
+	var colsStr string
+	if colsStr, err = validator.CheckColumns(p.Cols, params.Select); err != nil {
+		err = limberr.Take(err, "E1047982").Build()
+		return
+	}
+
+	params.ScopeSearch(p.Search)
+	if err = params.ScopeSort(p.Cols); err != nil {
+		err = limberr.Take(err, "E1069548").Custom(corerr.ValidationFailedErr).Build()
+		return
+	}
+
+	var whereStr string
+	var args []interface{}
+	if whereStr, args, err = params.ParseWhere(p.Cols); err != nil {
+		err = limberr.Take(err, "E1068849").Custom(corerr.ValidationFailedErr).Build()
+		return
+	}
+
+	err = p.Engine.ReadDB.Table(basmodel.SessionTable).Select(colsStr).
+		Joins("INNER JOIN bas_users ON bas_users.id = bas_sessions.user_id").
+		Where("bas_sessions.deleted_at IS NULL").
+		Where(whereStr, args...).
+		Clauses(params.OrderBy()).
+		Limit(params.Limit).
+		Offset(params.Offset).
+		Find(&sessions).Error
+
+	err = p.dbError(err, "E1067728", basmodel.Session{})
+	params.Arrange(sessions)
+
+	return
+}
+
+// Count of sessions, mainly calls with List
+func (p *SessionRepo) Count(params param.Param) (count int64, err error) {
+	params.ScopeSearch(p.Search)
+
+	var whereStr string
+	var args []interface{}
+	if whereStr, args, err = params.ParseWhere(p.Cols); err != nil {
+		err = limberr.Take(err, "E1051694").Custom(corerr.ValidationFailedErr).Build()
+		return
+	}
+
+	err = p.Engine.ReadDB.Table(basmodel.SessionTable).
+		Joins("INNER JOIN bas_users ON bas_users.id = bas_sessions.user_id").
+		Where("bas_sessions.deleted_at IS NULL").
+		Where(whereStr, args...).
+		Count(&count).Error
+
+	err = p.dbError(err, "E1010342", basmodel.Session{})
+	return
+}
+
+// TxCreate a session inside the login's transaction
+func (p *SessionRepo) TxCreate(db *gorm.DB, session basmodel.Session) (u basmodel.Session,
+	err error) {
+	if err = db.Table(basmodel.SessionTable).Create(&session).Scan(&u).Error; err != nil {
+		err = p.dbError(err, "E1043453", session)
+	}
+	return
+}
+
+// TxExtend push the expiration of the session forward, it is called after refreshing the token
+func (p *SessionRepo) TxExtend(db *gorm.DB, id uint, expiresAt, now time.Time) (err error) {
+	err = db.Table(basmodel.SessionTable).
+		Where("id = ?", id).
+		UpdateColumns(map[string]interface{}{
+			"expires_at":   expiresAt,
+			"last_seen_at": now,
+		}).Error
+
+	err = p.dbError(err, "E1038913", basmodel.Session{})
+	return
+}
+
+// Touch update the last_seen_at of the session
+func (p *SessionRepo) Touch(id uint, now time.Time) (err error) {
+	err = p.Engine.DB.Table(basmodel.SessionTable).
+		Where("id = ?", id).
+		UpdateColumn("last_seen_at", now).Error
+
+	err = p.dbError(err, "E1081152", basmodel.Session{})
+	return
+}
+
+// Terminate set the terminated_at of the session
+func (p *SessionRepo) Terminate(id uint) (err error) {
+	err = p.Engine.DB.Table(basmodel.SessionTable).
+		Where("id = ? AND terminated_at IS NULL", id).
+		UpdateColumn("terminated_at", time.Now()).Error
+
+	err = p.dbError(err, "E1054208", basmodel.Session{})
+	return
+}
+
+// TerminateUser terminate all active sessions of the user
+func (p *SessionRepo) TerminateUser(userID uint) (err error) {
+	err = p.Engine.DB.Table(basmodel.SessionTable).
+		Where("user_id = ? AND terminated_at IS NULL", userID).
+		UpdateColumn("terminated_at", time.Now()).Error
+
+	err = p.dbError(err, "E1014536", basmodel.Session{})
+	return
+}
+
+// PurgeExpired hard delete the expired sessions and the terminated sessions which their access
+// tokens are expired as well
+func (p *SessionRepo) PurgeExpired(now, terminatedBefore time.Time) (err error) {
+	err = p.Engine.DB.Unscoped().Table(basmodel.SessionTable).
+		Where("expires_at < ? OR terminated_at < ?", now, terminatedBefore).
+		Delete(&basmodel.Session{}).Error
+
+	err = p.dbError(err, "E1096715", basmodel.Session{})
+	return
+}
+
+// dbError is an internal method for generate proper database error
+func (p *SessionRepo) dbError(err error, code string, session basmodel.Session) error {
+	switch corerr.ClearDbErr(err) {
+	case corerr.Nil:
+		err = nil
+
+	case corerr.NotFoundErr:
+		err = corerr.RecordNotFoundHelper(err, code, corterm.ID, session.ID, basterm.Sessions)
+
+	case corerr.ValidationFailedErr:
+		err = corerr.ValidationFailedHelper(err, code)
+
+	default:
+		err = limberr.Take(err, code).
+			Message(corerr.InternalServerError).
+			Custom(corerr.InternalServerErr).Build()
+	}
+
+	return err
+}
@@ -21,6 +21,7 @@ import (
 type SettingRepo struct {
 	Engine *core.Engine
 	Cols   []string
+	Search helper.Searchable
 }
 
 // ProvideSettingRepo is used in wire and initiate the Cols
@@ -28,6 +29,7 @@ func ProvideSettingRepo(engine *core.Engine) SettingRepo {
 	return SettingRepo{
 		Engine: engine,
 		Cols:   helper.TagExtracter(reflect.TypeOf(basmodel.Setting{}), basmodel.SettingTable),
+		Search: helper.SearchExtracter(reflect.TypeOf(basmodel.Setting{}), basmodel.SettingTable),
 	}
 }
 
@@ -43,7 +45,7 @@ func (p *SettingRepo) FindByID(id uint) (setting basmodel.Setting, err error) {
 	return
 }
 
-//FindByProperty finds the setting via its property
+// FindByProperty finds the setting via its property
 func (p *SettingRepo) FindByProperty(property string) (setting basmodel.Setting, err error) {
 	err = p.Engine.ReadDB.Table(basmodel.SettingTable).
 		Select("bas_settings.*").
@@ -65,40 +67,80 @@ func (p *SettingRepo) List(params param.Param) (settings []basmodel.Setting, err
 		return
 	}
 
+	params.ScopeSearch(p.Search)
+	if err = params.ScopeSort(p.Cols); err != nil {
+		err = limberr.Take(err, "E1076546").Custom(corerr.ValidationFailedErr).Build()
+		return
+	}
+
 	var whereStr string
-	if whereStr, err = params.ParseWhere(p.Cols); err != nil {
+	var args []interface{}
+	if whereStr, args, err = params.ParseWhere(p.Cols); err != nil {
 		err = limberr.Take(err, "E1039990").Custom(corerr.ValidationFailedErr).Build()
 		return
 	}
 
 	err = p.Engine.ReadDB.Table(basmodel.SettingTable).Select(colsStr).
-		Where(whereStr).
-		Order(params.Order).
+		Where(whereStr, args...).
+		Clauses(params.OrderBy()).
 		Limit(params.Limit).
 		Offset(params.Offset).
 		Scan(&settings).Error
 
 	err = p.dbError(err, "E1094986", basmodel.Setting{}, corterm.List)
+	params.Arrange(settings)
 
 	return
 }
 
 // Count of settings
 func (p *SettingRepo) Count(params param.Param) (count int64, err error) {
+	params.ScopeSearch(p.Search)
+
 	var whereStr string
-	if whereStr, err = params.ParseWhere(p.Cols); err != nil {
+	var args []interface{}
+	if whereStr, args, err = params.ParseWhere(p.Cols); err != nil {
 		err = limberr.Take(err, "E1051896").Custom(corerr.ValidationFailedErr).Build()
 		return
 	}
 
 	err = p.Engine.ReadDB.Table(basmodel.SettingTable).
-		Where(whereStr).
+		Where(whereStr, args...).
 		Count(&count).Error
 
 	err = p.dbError(err, "E1021898", basmodel.Setting{}, corterm.List)
 	return
 }
 
+// Aggregate returns the grouped rows of the settings, the conditions are the same as the List
+func (p *SettingRepo) Aggregate(params param.Param) (rows []map[string]interface{}, err error) {
+	params.ScopeSearch(p.Search)
+
+	var aggregate func(*gorm.DB) *gorm.DB
+	if aggregate, err = params.ScopeAggregate(p.Cols); err != nil {
+		err = limberr.Take(err, "E1095658").Custom(corerr.ValidationFailedErr).Build()
+		return
+	}
+
+	var whereStr string
+	var args []interface{}
+	if whereStr, args, err = params.ParseWhere(p.Cols); err != nil {
+		err = limberr.Take(err, "E1085319").Custom(corerr.ValidationFailedErr).Build()
+		return
+	}
+
+	err = p.Engine.ReadDB.Table(basmodel.SettingTable).
+		Where("bas_settings.deleted_at IS NULL").
+		Where(whereStr, args...).
+		Scopes(aggregate).
+		Limit(params.Limit).
+		Offset(params.Offset).
+		Find(&rows).Error
+
+	err = p.dbError(err, "E1063969", basmodel.Setting{}, corterm.List)
+	return
+}
+
 // TxCreate is used for creating setting in transaction mode
 func (p *SettingRepo) TxCreate(db *gorm.DB, setting basmodel.Setting) (u basmodel.Setting, err error) {
 	if err = db.Table(basmodel.SettingTable).Create(&setting).Scan(&u).Error; err != nil {
@@ -108,26 +150,44 @@ func (p *SettingRepo) TxCreate(db *gorm.DB, setting basmodel.Setting) (u basmode
 	return
 }
 
-// Save SettingRepo
+// Save the setting, the updated_at is the version of the setting which is fetched before, in case
+// another request changed the setting after that it is not saved
 func (p *SettingRepo) Save(setting basmodel.Setting) (u basmodel.Setting, err error) {
-	if err = p.Engine.DB.Table(basmodel.SettingTable).Save(&setting).Error; err != nil {
+	result := p.Engine.DB.Table(basmodel.SettingTable).Select("*").
+		Where("updated_at = ?", setting.UpdatedAt).Save(&setting)
+	if err = result.Error; err != nil {
 		err = p.dbError(err, "E1020662", setting, corterm.Updated)
+		return
+	}
+
+	if result.RowsAffected == 0 {
+		err = corerr.PreconditionFailedHelper("E1056434", basterm.Setting)
+		return
 	}
 
 	p.Engine.DB.Table(basmodel.SettingTable).Where("id = ?", setting.ID).Find(&u)
 	return
 }
 
-// Update SettingRepo
+// Update the value of the setting, the updated_at is the version of the setting which is
+// fetched before, in case another request changed the setting after that it is not updated
 func (p *SettingRepo) Update(setting basmodel.Setting) (u basmodel.Setting, err error) {
 	id := setting.ID
+	updatedAt := setting.UpdatedAt
 	setting.ID = 0
 	setting.Property = ""
 	setting.Type = ""
 	setting.Description = ""
-	err = p.Engine.DB.Table(basmodel.SettingTable).Where("id = ?", id).Updates(&setting).Error
+	result := p.Engine.DB.Table(basmodel.SettingTable).
+		Where("id = ? AND updated_at = ?", id, updatedAt).Updates(&setting)
+	if err = p.dbError(result.Error, "E1081024", basmodel.Setting{}, corterm.Updated); err != nil {
+		return
+	}
 
-	err = p.dbError(err, "E1081024", basmodel.Setting{}, corterm.Updated)
+	if result.RowsAffected == 0 {
+		err = corerr.PreconditionFailedHelper("E1089207", basterm.Setting)
+		return
+	}
 
 	p.Engine.DB.Table(basmodel.SettingTable).Where("id = ?", id).Find(&u)
 	return
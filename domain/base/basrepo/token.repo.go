@@ -0,0 +1,148 @@
+package basrepo
+
+import (
+	"omono/domain/base/basmodel"
+	"omono/internal/core"
+	"omono/internal/core/corerr"
+	"time"
+
+	"github.com/syronz/limberr"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TokenRepo for injecting engine, it keeps refresh tokens and revoked access tokens
+type TokenRepo struct {
+	Engine *core.Engine
+}
+
+// ProvideTokenRepo is used in wire
+func ProvideTokenRepo(engine *core.Engine) TokenRepo {
+	return TokenRepo{Engine: engine}
+}
+
+// TxCreateRefresh save the hashed refresh token
+func (p *TokenRepo) TxCreateRefresh(db *gorm.DB,
+	refresh basmodel.RefreshToken) (u basmodel.RefreshToken, err error) {
+	if err = db.Table(basmodel.RefreshTokenTable).Create(&refresh).Scan(&u).Error; err != nil {
+		err = p.dbError(err, "E1076264")
+	}
+	return
+}
+
+// TxFindRefresh finds the refresh token via its hash
+func (p *TokenRepo) TxFindRefresh(db *gorm.DB, hash string) (refresh basmodel.RefreshToken, err error) {
+	err = db.Table(basmodel.RefreshTokenTable).
+		Where("token = ? AND deleted_at IS NULL", hash).
+		First(&refresh).Error
+
+	err = p.dbError(err, "E1087786")
+
+	return
+}
+
+// TxRevokeRefresh mark the refresh token as revoked, revoked is false in case another request
+// revoked it before
+func (p *TokenRepo) TxRevokeRefresh(db *gorm.DB, id uint) (revoked bool, err error) {
+	result := db.Table(basmodel.RefreshTokenTable).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", time.Now())
+
+	err = p.dbError(result.Error, "E1070221")
+	revoked = result.RowsAffected > 0
+	return
+}
+
+// RevokeRefreshByJTI revoke the refresh token which has been issued beside the access token
+func (p *TokenRepo) RevokeRefreshByJTI(jti string) (err error) {
+	err = p.Engine.DB.Table(basmodel.RefreshTokenTable).
+		Where("access_jti = ? AND revoked_at IS NULL", jti).
+		Update("revoked_at", time.Now()).Error
+
+	err = p.dbError(err, "E1040276")
+	return
+}
+
+// RevokeRefreshBySession revoke the refresh tokens of the session
+func (p *TokenRepo) RevokeRefreshBySession(sessionID uint) (err error) {
+	err = p.Engine.DB.Table(basmodel.RefreshTokenTable).
+		Where("session_id = ? AND revoked_at IS NULL", sessionID).
+		Update("revoked_at", time.Now()).Error
+
+	err = p.dbError(err, "E1078257")
+	return
+}
+
+// RevokeUserRefresh revoke all active refresh tokens of a user
+func (p *TokenRepo) RevokeUserRefresh(userID uint) (err error) {
+	err = p.Engine.DB.Table(basmodel.RefreshTokenTable).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).Error
+
+	err = p.dbError(err, "E1089189")
+	return
+}
+
+// CreateRevoked add the jti of an access token to the revocation list
+func (p *TokenRepo) CreateRevoked(revoked basmodel.RevokedToken) (err error) {
+	err = p.Engine.DB.Table(basmodel.RevokedTokenTable).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&revoked).Error
+
+	err = p.dbError(err, "E1093289")
+	return
+}
+
+// IsRevoked check if the jti exist in the revocation list
+func (p *TokenRepo) IsRevoked(jti string) (revoked bool, err error) {
+	var count int64
+	err = p.Engine.ReadDB.Table(basmodel.RevokedTokenTable).
+		Where("jti = ?", jti).
+		Count(&count).Error
+
+	err = p.dbError(err, "E1093878")
+	revoked = count > 0
+
+	return
+}
+
+// PurgeExpired hard delete the tokens which passed their expiration time
+func (p *TokenRepo) PurgeExpired(now time.Time) (err error) {
+	err = p.Engine.DB.Unscoped().Table(basmodel.RefreshTokenTable).
+		Where("expires_at < ?", now).
+		Delete(&basmodel.RefreshToken{}).Error
+	if err = p.dbError(err, "E1026225"); err != nil {
+		return
+	}
+
+	err = p.Engine.DB.Unscoped().Table(basmodel.RevokedTokenTable).
+		Where("expires_at < ?", now).
+		Delete(&basmodel.RevokedToken{}).Error
+
+	err = p.dbError(err, "E1090019")
+	return
+}
+
+// dbError is an internal method for generate proper database error
+func (p *TokenRepo) dbError(err error, code string) error {
+	switch corerr.ClearDbErr(err) {
+	case corerr.Nil:
+		err = nil
+
+	case corerr.NotFoundErr:
+		err = limberr.Take(err, code).
+			Message(corerr.TokenIsNotValid).
+			Custom(corerr.UnauthorizedErr).Build()
+
+	case corerr.ValidationFailedErr:
+		err = corerr.ValidationFailedHelper(err, code)
+
+	default:
+		err = limberr.Take(err, code).
+			Message(corerr.InternalServerError).
+			Custom(corerr.InternalServerErr).Build()
+	}
+
+	return err
+}
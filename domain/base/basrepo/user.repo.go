@@ -3,13 +3,16 @@ package basrepo
 import (
 	"omono/domain/base/basmodel"
 	"omono/domain/base/basterm"
+	"omono/domain/base/enum/userstatus"
 	"omono/internal/core"
 	"omono/internal/core/corerr"
 	"omono/internal/core/corterm"
 	"omono/internal/core/validator"
 	"omono/internal/param"
+	"omono/internal/types"
 	"omono/pkg/helper"
 	"reflect"
+	"time"
 
 	"github.com/syronz/dict"
 	"github.com/syronz/limberr"
@@ -21,6 +24,7 @@ import (
 type UserRepo struct {
 	Engine *core.Engine
 	Cols   []string
+	Search helper.Searchable
 }
 
 // ProvideUserRepo is used in wire and initiate the Cols
@@ -28,9 +32,14 @@ func ProvideUserRepo(engine *core.Engine) UserRepo {
 	return UserRepo{
 		Engine: engine,
 		Cols:   helper.TagExtracter(reflect.TypeOf(basmodel.User{}), basmodel.UserTable),
+		Search: helper.SearchExtracter(reflect.TypeOf(basmodel.User{}), basmodel.UserTable),
 	}
 }
 
+// userScope limits the users to the members of the active company of the params
+const userScope = "bas_users.id IN (SELECT bas_user_companies.user_id FROM bas_user_companies " +
+	"WHERE bas_user_companies.company_id = %v)"
+
 // FindByID finds the user via its id
 func (p *UserRepo) FindByID(id uint) (user basmodel.User, err error) {
 	var colsStr string
@@ -39,8 +48,9 @@ func (p *UserRepo) FindByID(id uint) (user basmodel.User, err error) {
 		return
 	}
 
+	// the password is not part of the cols, it is read for keeping it in the updates
 	err = p.Engine.ReadDB.Table(basmodel.UserTable).
-		Select(colsStr).
+		Select(colsStr+", bas_users.password").
 		Joins("INNER JOIN bas_roles ON bas_roles.id = bas_users.role_id").
 		Where("bas_users.id = ? AND bas_users.deleted_at IS NULL", id).
 		First(&user).Error
@@ -55,7 +65,7 @@ func (p *UserRepo) FindByID(id uint) (user basmodel.User, err error) {
 func (p *UserRepo) FindByUsername(username string) (user basmodel.User, err error) {
 	err = p.Engine.ReadDB.Table(basmodel.UserTable).
 		Select("bas_users.*, bas_roles.resources, bas_roles.name as role").
-		Where("bas_users.username = ?", username).
+		Where("bas_users.username = ? AND bas_users.deleted_at IS NULL", username).
 		Joins("INNER JOIN bas_roles on bas_roles.id = bas_users.role_id").
 		Scan(&user).Error
 
@@ -65,29 +75,51 @@ func (p *UserRepo) FindByUsername(username string) (user basmodel.User, err erro
 	return
 }
 
+// FindByEmail finds the user via its email
+func (p *UserRepo) FindByEmail(email string) (user basmodel.User, err error) {
+	err = p.Engine.ReadDB.Table(basmodel.UserTable).
+		Where("bas_users.email = ? AND bas_users.deleted_at IS NULL", email).
+		First(&user).Error
+
+	err = p.dbError(err, "E1014347", user, corterm.List)
+
+	return
+}
+
 // List returns an array of users
 func (p *UserRepo) List(params param.Param) (users []basmodel.User, err error) {
+	params.ScopeCompany(userScope)
+	params.ScopeDeleted(basmodel.UserTable)
+
 	var colsStr string
 	if colsStr, err = validator.CheckColumns(p.Cols, params.Select); err != nil {
 		err = limberr.Take(err, "E1084438").Build()
 		return
 	}
 
+	params.ScopeSearch(p.Search)
+	if err = params.ScopeSort(p.Cols); err != nil {
+		err = limberr.Take(err, "E1053993").Custom(corerr.ValidationFailedErr).Build()
+		return
+	}
+
 	var whereStr string
-	if whereStr, err = params.ParseWhere(p.Cols); err != nil {
+	var args []interface{}
+	if whereStr, args, err = params.ParseWhere(p.Cols); err != nil {
 		err = limberr.Take(err, "E1043328").Custom(corerr.ValidationFailedErr).Build()
 		return
 	}
 
-	err = p.Engine.ReadDB.Table(basmodel.UserTable).Select(colsStr).
+	err = p.Engine.ReadDB.Unscoped().Table(basmodel.UserTable).Select(colsStr).
 		Joins("INNER JOIN bas_roles ON bas_roles.id = bas_users.role_id").
-		Where(whereStr).
-		Order(params.Order).
+		Where(whereStr, args...).
+		Clauses(params.OrderBy()).
 		Limit(params.Limit).
 		Offset(params.Offset).
 		Find(&users).Error
 
 	err = p.dbError(err, "E1077340", basmodel.User{}, corterm.List)
+	params.Arrange(users)
 
 	for i := range users {
 		users[i].Password = ""
@@ -98,31 +130,129 @@ func (p *UserRepo) List(params param.Param) (users []basmodel.User, err error) {
 
 // Count of users, mainly calls with List
 func (p *UserRepo) Count(params param.Param) (count int64, err error) {
+	params.ScopeCompany(userScope)
+	params.ScopeDeleted(basmodel.UserTable)
+	params.ScopeSearch(p.Search)
+
 	var whereStr string
-	if whereStr, err = params.ParseWhere(p.Cols); err != nil {
+	var args []interface{}
+	if whereStr, args, err = params.ParseWhere(p.Cols); err != nil {
 		err = limberr.Take(err, "E1042198").Custom(corerr.ValidationFailedErr).Build()
 		return
 	}
 
 	err = p.Engine.ReadDB.Table(basmodel.UserTable).
 		Joins("INNER JOIN bas_roles ON bas_roles.id = bas_users.role_id").
-		Where(whereStr).
+		Where(whereStr, args...).
 		Count(&count).Error
 
 	err = p.dbError(err, "E1042199", basmodel.User{}, corterm.List)
 	return
 }
 
-// TxSave the user, in case it is not exist create it
+// Aggregate returns the grouped rows of the users, the conditions are the same as the List
+func (p *UserRepo) Aggregate(params param.Param) (rows []map[string]interface{}, err error) {
+	params.ScopeCompany(userScope)
+	params.ScopeDeleted(basmodel.UserTable)
+	params.ScopeSearch(p.Search)
+
+	var aggregate func(*gorm.DB) *gorm.DB
+	if aggregate, err = params.ScopeAggregate(p.Cols); err != nil {
+		err = limberr.Take(err, "E1012066").Custom(corerr.ValidationFailedErr).Build()
+		return
+	}
+
+	var whereStr string
+	var args []interface{}
+	if whereStr, args, err = params.ParseWhere(p.Cols); err != nil {
+		err = limberr.Take(err, "E1047437").Custom(corerr.ValidationFailedErr).Build()
+		return
+	}
+
+	err = p.Engine.ReadDB.Table(basmodel.UserTable).
+		Joins("INNER JOIN bas_roles ON bas_roles.id = bas_users.role_id").
+		Where(whereStr, args...).
+		Scopes(aggregate).
+		Limit(params.Limit).
+		Offset(params.Offset).
+		Find(&rows).Error
+
+	err = p.dbError(err, "E1024310", basmodel.User{}, corterm.List)
+	return
+}
+
+// TxSave the user, the updated_at is the version of the user which is fetched before, in case
+// another request changed the user after that it is not saved
 func (p *UserRepo) TxSave(db *gorm.DB, user basmodel.User) (u basmodel.User, err error) {
-	if err = db.Table(basmodel.UserTable).Save(&user).Error; err != nil {
+	result := db.Table(basmodel.UserTable).Select("*").
+		Where("updated_at = ?", user.UpdatedAt).Save(&user)
+	if err = result.Error; err != nil {
 		err = p.dbError(err, "E1056429", user, corterm.Updated)
+		return
+	}
+
+	if result.RowsAffected == 0 {
+		err = corerr.PreconditionFailedHelper("E1037860", basterm.User)
+		return
 	}
 
 	db.Table(basmodel.UserTable).Where("id = ?", user.ID).Find(&u)
 	return
 }
 
+// TxTouch renew the updated_at of the user in case it is not changed after fetching it, it is
+// used for the relations of the user which are changed via the version of the user
+func (p *UserRepo) TxTouch(db *gorm.DB, id uint, updatedAt time.Time) (err error) {
+	result := db.Table(basmodel.UserTable).
+		Where("id = ? AND updated_at = ?", id, updatedAt).
+		Update("updated_at", time.Now())
+	if err = result.Error; err != nil {
+		err = p.dbError(err, "E1016148", basmodel.User{}, corterm.Updated)
+		return
+	}
+
+	if result.RowsAffected == 0 {
+		err = corerr.PreconditionFailedHelper("E1084479", basterm.User)
+	}
+
+	return
+}
+
+// TxUpdatePassword just change the password and its change time, the password should be hashed
+func (p *UserRepo) TxUpdatePassword(db *gorm.DB, id uint, hash string) (err error) {
+	if err = db.Table(basmodel.UserTable).Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"password":            hash,
+			"password_changed_at": time.Now(),
+		}).Error; err != nil {
+		err = p.dbError(err, "E1026286", basmodel.User{}, corterm.Updated)
+	}
+	return
+}
+
+// TxUpdateStatus change the status of the user in case the current status is equal to the from,
+// updated is false in case the status is changed before
+func (p *UserRepo) TxUpdateStatus(db *gorm.DB, id uint, from,
+	to types.Enum) (updated bool, err error) {
+	result := db.Table(basmodel.UserTable).
+		Where("id = ? AND status = ?", id, from).
+		Update("status", to)
+
+	err = p.dbError(result.Error, "E1089246", basmodel.User{}, corterm.Updated)
+	updated = result.RowsAffected > 0
+	return
+}
+
+// FindActive returns the active users, their resources are checked by the access service
+func (p *UserRepo) FindActive() (users []basmodel.User, err error) {
+	err = p.Engine.ReadDB.Table(basmodel.UserTable).
+		Where("bas_users.status = ? AND bas_users.deleted_at IS NULL", userstatus.Active).
+		Find(&users).Error
+
+	err = p.dbError(err, "E1075296", basmodel.User{}, corterm.List)
+	return
+}
+
 // Create a user
 func (p *UserRepo) Create(user basmodel.User) (u basmodel.User, err error) {
 	if err = p.Engine.DB.Table(basmodel.UserTable).Create(&user).Scan(&u).Error; err != nil {
@@ -141,12 +271,43 @@ func (p *UserRepo) TxCreate(db *gorm.DB, user basmodel.User) (u basmodel.User, e
 
 // Delete the user
 func (p *UserRepo) Delete(user basmodel.User) (err error) {
-	if err = p.Engine.DB.Unscoped().Table(basmodel.UserTable).Delete(&user).Error; err != nil {
+	if err = p.Engine.DB.Table(basmodel.UserTable).Delete(&user).Error; err != nil {
 		err = p.dbError(err, "E1044329", user, corterm.Deleted)
 	}
 	return
 }
 
+// Restore the soft deleted user, it is limited to the active company of the params
+func (p *UserRepo) Restore(params param.Param, id uint) (err error) {
+	params.ScopeCompany(userScope)
+
+	db := p.Engine.DB.Unscoped().Table(basmodel.UserTable).
+		Where("bas_users.id = ? AND bas_users.deleted_at IS NOT NULL", id)
+	if params.PreCondition != "" {
+		db = db.Where(params.PreCondition)
+	}
+
+	result := db.Update("deleted_at", nil)
+	if err = result.Error; err == nil && result.RowsAffected == 0 {
+		err = gorm.ErrRecordNotFound
+	}
+
+	var user basmodel.User
+	user.ID = id
+	err = p.dbError(err, "E1057472", user, corterm.Updated)
+	return
+}
+
+// PurgeDeleted hard delete the users which are soft deleted before the time
+func (p *UserRepo) PurgeDeleted(before time.Time) (err error) {
+	err = p.Engine.DB.Unscoped().Table(basmodel.UserTable).
+		Where("deleted_at < ?", before).
+		Delete(&basmodel.User{}).Error
+
+	err = p.dbError(err, "E1044176", basmodel.User{}, corterm.Deleted)
+	return
+}
+
 // dbError is an internal method for create proper database error
 func (p *UserRepo) dbError(err error, code string, user basmodel.User, action string) error {
 	switch corerr.ClearDbErr(err) {
@@ -0,0 +1,88 @@
+package basrepo
+
+import (
+	"omono/domain/base/basmodel"
+	"omono/internal/core"
+	"omono/internal/core/corerr"
+
+	"github.com/syronz/limberr"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// UserCompanyRepo for injecting engine, it keeps the memberships of the users in the companies
+type UserCompanyRepo struct {
+	Engine *core.Engine
+}
+
+// ProvideUserCompanyRepo is used in wire
+func ProvideUserCompanyRepo(engine *core.Engine) UserCompanyRepo {
+	return UserCompanyRepo{Engine: engine}
+}
+
+// ListByUser returns the companies of the user which are not deleted
+func (p *UserCompanyRepo) ListByUser(userID uint) (companies []basmodel.UserCompany, err error) {
+	err = p.Engine.ReadDB.Table(basmodel.UserCompanyTable).
+		Select("bas_user_companies.*, seg_companies.name as company").
+		Joins("INNER JOIN seg_companies ON seg_companies.id = bas_user_companies.company_id").
+		Where("bas_user_companies.user_id = ? AND seg_companies.deleted_at IS NULL", userID).
+		Order("bas_user_companies.company_id ASC").
+		Find(&companies).Error
+
+	err = p.dbError(err, "E1092321")
+	return
+}
+
+// IsMember check the membership of the user in the company
+func (p *UserCompanyRepo) IsMember(userID, companyID uint) (member bool, err error) {
+	var count int64
+	err = p.Engine.ReadDB.Table(basmodel.UserCompanyTable).
+		Joins("INNER JOIN seg_companies ON seg_companies.id = bas_user_companies.company_id").
+		Where("bas_user_companies.user_id = ? AND bas_user_companies.company_id = ? AND "+
+			"seg_companies.deleted_at IS NULL", userID, companyID).
+		Count(&count).Error
+
+	err = p.dbError(err, "E1030408")
+	member = count > 0
+	return
+}
+
+// TxAdd join the user to the company, it is ignored if the user is already a member
+func (p *UserCompanyRepo) TxAdd(db *gorm.DB, userID, companyID uint) (err error) {
+	err = db.Table(basmodel.UserCompanyTable).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&basmodel.UserCompany{UserID: userID, CompanyID: companyID}).Error
+
+	err = p.dbError(err, "E1098560")
+	return
+}
+
+// TxRemove delete the memberships of the user in the companies
+func (p *UserCompanyRepo) TxRemove(db *gorm.DB, userID uint, companyIDs []uint) (err error) {
+	err = db.Table(basmodel.UserCompanyTable).
+		Where("user_id = ? AND company_id IN (?)", userID, companyIDs).
+		Delete(&basmodel.UserCompany{}).Error
+
+	err = p.dbError(err, "E1038130")
+	return
+}
+
+// dbError is an internal method for generate proper database error
+func (p *UserCompanyRepo) dbError(err error, code string) error {
+	switch corerr.ClearDbErr(err) {
+	case corerr.Nil:
+		err = nil
+
+	case corerr.ForeignErr:
+		err = limberr.Take(err, code).
+			Message(corerr.InternalServerError).
+			Custom(corerr.ForeignErr).Build()
+
+	default:
+		err = limberr.Take(err, code).
+			Message(corerr.InternalServerError).
+			Custom(corerr.InternalServerErr).Build()
+	}
+
+	return err
+}
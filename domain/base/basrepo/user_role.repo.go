@@ -0,0 +1,86 @@
+package basrepo
+
+import (
+	"omono/domain/base/basmodel"
+	"omono/internal/core"
+	"omono/internal/core/corerr"
+
+	"github.com/syronz/limberr"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// UserRoleRepo for injecting engine, it keeps the roles of the users
+type UserRoleRepo struct {
+	Engine *core.Engine
+}
+
+// ProvideUserRoleRepo is used in wire
+func ProvideUserRoleRepo(engine *core.Engine) UserRoleRepo {
+	return UserRoleRepo{Engine: engine}
+}
+
+// RoleIDs returns the roles of the user
+func (p *UserRoleRepo) RoleIDs(userID uint) (roleIDs []uint, err error) {
+	err = p.Engine.ReadDB.Table(basmodel.UserRoleTable).
+		Where("user_id = ?", userID).
+		Order("role_id ASC").
+		Pluck("role_id", &roleIDs).Error
+
+	err = p.dbError(err, "E1070030")
+	return
+}
+
+// Usernames returns the usernames of the users who have the role, the deleted users are ignored
+func (p *UserRoleRepo) Usernames(roleID uint, limit int) (usernames []string, err error) {
+	err = p.Engine.ReadDB.Table(basmodel.UserRoleTable).
+		Joins("INNER JOIN bas_users ON bas_users.id = bas_user_roles.user_id").
+		Where("bas_user_roles.role_id = ? AND bas_users.deleted_at IS NULL", roleID).
+		Order("bas_users.id ASC").
+		Limit(limit).
+		Pluck("bas_users.username", &usernames).Error
+
+	err = p.dbError(err, "E1050036")
+	return
+}
+
+// TxReplace set the roles of the user, the roles which are not in the list are removed
+func (p *UserRoleRepo) TxReplace(db *gorm.DB, userID uint, roleIDs []uint) (err error) {
+	if err = db.Table(basmodel.UserRoleTable).
+		Where("user_id = ? AND role_id NOT IN (?)", userID, roleIDs).
+		Delete(&basmodel.UserRole{}).Error; err != nil {
+		err = p.dbError(err, "E1087506")
+		return
+	}
+
+	for _, v := range roleIDs {
+		if err = db.Table(basmodel.UserRoleTable).
+			Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&basmodel.UserRole{UserID: userID, RoleID: v}).Error; err != nil {
+			err = p.dbError(err, "E1083705")
+			return
+		}
+	}
+
+	return
+}
+
+// dbError is an internal method for generate proper database error
+func (p *UserRoleRepo) dbError(err error, code string) error {
+	switch corerr.ClearDbErr(err) {
+	case corerr.Nil:
+		err = nil
+
+	case corerr.ForeignErr:
+		err = limberr.Take(err, code).
+			Message(corerr.InternalServerError).
+			Custom(corerr.ForeignErr).Build()
+
+	default:
+		err = limberr.Take(err, code).
+			Message(corerr.InternalServerError).
+			Custom(corerr.InternalServerErr).Build()
+	}
+
+	return err
+}
@@ -0,0 +1,197 @@
+package basrepo
+
+import (
+	"omono/domain/base/basmodel"
+	"omono/domain/base/basterm"
+	"omono/internal/core"
+	"omono/internal/core/corerr"
+	"omono/internal/core/corterm"
+	"omono/internal/core/validator"
+	"omono/internal/param"
+	"omono/pkg/helper"
+	"reflect"
+
+	"github.com/syronz/dict"
+	"github.com/syronz/limberr"
+	"gorm.io/gorm"
+)
+
+// ViewRepo for injecting engine
+type ViewRepo struct {
+	Engine *core.Engine
+	Cols   []string
+	Search helper.Searchable
+}
+
+// ProvideViewRepo is used in wire and initiate the Cols
+func ProvideViewRepo(engine *core.Engine) ViewRepo {
+	return ViewRepo{
+		Engine: engine,
+		Cols:   helper.TagExtracter(reflect.TypeOf(basmodel.View{}), basmodel.ViewTable),
+		Search: helper.SearchExtracter(reflect.TypeOf(basmodel.View{}), basmodel.ViewTable),
+	}
+}
+
+// FindByID finds the view via its id
+func (p *ViewRepo) FindByID(id uint) (view basmodel.View, err error) {
+	err = p.Engine.ReadDB.Table(basmodel.ViewTable).
+		Select("bas_views.*, bas_users.username").
+		Joins("INNER JOIN bas_users ON bas_users.id = bas_views.user_id").
+		Where("bas_views.id = ? AND bas_views.deleted_at IS NULL", id).
+		First(&view).Error
+
+	view.ID = id
+	err = p.dbError(err, "E1079377", view, corterm.List)
+
+	return
+}
+
+// List returns an array of views
+func (p *ViewRepo) List(params param.Param) (views []basmodel.View, err error) {
+	var colsStr string
+	if colsStr, err = validator.CheckColumns(p.Cols, params.Select); err != nil {
+		err = limberr.Take(err, "E1027328").Build()
+		return
+	}
+
+	params.ScopeSearch(p.Search)
+	if err = params.ScopeSort(p.Cols); err != nil {
+		err = limberr.Take(err, "E1046689").Custom(corerr.ValidationFailedErr).Build()
+		return
+	}
+
+	var whereStr string
+	var args []interface{}
+	if whereStr, args, err = params.ParseWhere(p.Cols); err != nil {
+		err = limberr.Take(err, "E1012814").Custom(corerr.ValidationFailedErr).Build()
+		return
+	}
+
+	err = p.Engine.ReadDB.Table(basmodel.ViewTable).Select(colsStr).
+		Joins("INNER JOIN bas_users ON bas_users.id = bas_views.user_id").
+		Where("bas_views.deleted_at IS NULL").
+		Where(whereStr, args...).
+		Clauses(params.OrderBy()).
+		Limit(params.Limit).
+		Offset(params.Offset).
+		Find(&views).Error
+
+	err = p.dbError(err, "E1017961", basmodel.View{}, corterm.List)
+	params.Arrange(views)
+
+	return
+}
+
+// Count of views, mainly calls with List
+func (p *ViewRepo) Count(params param.Param) (count int64, err error) {
+	params.ScopeSearch(p.Search)
+
+	var whereStr string
+	var args []interface{}
+	if whereStr, args, err = params.ParseWhere(p.Cols); err != nil {
+		err = limberr.Take(err, "E1069170").Custom(corerr.ValidationFailedErr).Build()
+		return
+	}
+
+	err = p.Engine.ReadDB.Table(basmodel.ViewTable).
+		Joins("INNER JOIN bas_users ON bas_users.id = bas_views.user_id").
+		Where("bas_views.deleted_at IS NULL").
+		Where(whereStr, args...).
+		Count(&count).Error
+
+	err = p.dbError(err, "E1060025", basmodel.View{}, corterm.List)
+	return
+}
+
+// Aggregate returns the grouped rows of the views, the conditions are the same as the List
+func (p *ViewRepo) Aggregate(params param.Param) (rows []map[string]interface{}, err error) {
+	params.ScopeSearch(p.Search)
+
+	var aggregate func(*gorm.DB) *gorm.DB
+	if aggregate, err = params.ScopeAggregate(p.Cols); err != nil {
+		err = limberr.Take(err, "E1070389").Custom(corerr.ValidationFailedErr).Build()
+		return
+	}
+
+	var whereStr string
+	var args []interface{}
+	if whereStr, args, err = params.ParseWhere(p.Cols); err != nil {
+		err = limberr.Take(err, "E1044594").Custom(corerr.ValidationFailedErr).Build()
+		return
+	}
+
+	err = p.Engine.ReadDB.Table(basmodel.ViewTable).
+		Joins("INNER JOIN bas_users ON bas_users.id = bas_views.user_id").
+		Where("bas_views.deleted_at IS NULL").
+		Where(whereStr, args...).
+		Scopes(aggregate).
+		Limit(params.Limit).
+		Offset(params.Offset).
+		Find(&rows).Error
+
+	err = p.dbError(err, "E1062875", basmodel.View{}, corterm.List)
+	return
+}
+
+// Save the view, the updated_at is the version of the view which is fetched before, in case
+// another request changed the view after that it is not saved
+func (p *ViewRepo) Save(view basmodel.View) (u basmodel.View, err error) {
+	result := p.Engine.DB.Table(basmodel.ViewTable).Select("*").
+		Where("updated_at = ?", view.UpdatedAt).Save(&view)
+	if err = result.Error; err != nil {
+		err = p.dbError(err, "E1013096", view, corterm.Updated)
+		return
+	}
+
+	if result.RowsAffected == 0 {
+		err = corerr.PreconditionFailedHelper("E1083526", basterm.View)
+		return
+	}
+
+	return p.FindByID(view.ID)
+}
+
+// Create a view
+func (p *ViewRepo) Create(view basmodel.View) (u basmodel.View, err error) {
+	if err = p.Engine.DB.Table(basmodel.ViewTable).Create(&view).Error; err != nil {
+		err = p.dbError(err, "E1087138", view, corterm.Created)
+		return
+	}
+
+	return p.FindByID(view.ID)
+}
+
+// Delete the view
+func (p *ViewRepo) Delete(view basmodel.View) (err error) {
+	if err = p.Engine.DB.Table(basmodel.ViewTable).Delete(&view).Error; err != nil {
+		err = p.dbError(err, "E1096286", view, corterm.Deleted)
+	}
+	return
+}
+
+// dbError is an internal method for generate proper database error
+func (p *ViewRepo) dbError(err error, code string, view basmodel.View, action string) error {
+	switch corerr.ClearDbErr(err) {
+	case corerr.Nil:
+		err = nil
+
+	case corerr.NotFoundErr:
+		err = corerr.RecordNotFoundHelper(err, code, corterm.ID, view.ID, basterm.Views)
+
+	case corerr.ForeignErr:
+		err = limberr.Take(err, code).
+			Message(corerr.SomeVRelatedToThisVSoItIsNotV, dict.R(basterm.Roles),
+				dict.R(basterm.View), dict.R(action)).
+			Custom(corerr.ForeignErr).Build()
+
+	case corerr.ValidationFailedErr:
+		err = corerr.ValidationFailedHelper(err, code)
+
+	default:
+		err = limberr.Take(err, code).
+			Message(corerr.InternalServerError).
+			Custom(corerr.InternalServerErr).Build()
+	}
+
+	return err
+}
@@ -1,13 +1,18 @@
 package base
 
-import "omono/internal/types"
+import (
+	"omono/domain/base/basterm"
+	"omono/internal/core/corperm"
+	"omono/internal/types"
+)
 
 // list of resources for base domain
 const (
 	Domain string = "base"
 
-	SuperAccess types.Resource = "supper:access"
-	ReadDeleted types.Resource = "deleted:read"
+	SuperAccess    types.Resource = "supper:access"
+	ReadDeleted    types.Resource = "deleted:read"
+	RestoreDeleted types.Resource = "deleted:restore"
 
 	UserWrite types.Resource = "user:write"
 	UserRead  types.Resource = "user:read"
@@ -28,5 +33,31 @@ const (
 	CityRead  types.Resource = "city:read"
 	CityExcel types.Resource = "city:excel"
 
+	APIKeyRead  types.Resource = "api-key:read"
+	APIKeyWrite types.Resource = "api-key:write"
+
+	SessionRead  types.Resource = "session:read"
+	SessionWrite types.Resource = "session:write"
+
+	GrantRead  types.Resource = "grant:read"
+	GrantWrite types.Resource = "grant:write"
+
+	UserImpersonate types.Resource = "user:impersonate"
+	UserApprove     types.Resource = "user:approve"
+
 	Ping types.Resource = "ping"
 )
+
+func init() {
+	corperm.Register("base", basterm.System, SuperAccess, ReadDeleted, RestoreDeleted,
+		Ping)
+	corperm.Register("base", basterm.Users, UserRead, UserWrite, UserExcel, UserImpersonate,
+		UserApprove)
+	corperm.Register("base", basterm.Roles, RoleRead, RoleWrite, RoleExcel)
+	corperm.Register("base", basterm.Settings, SettingRead, SettingWrite, SettingExcel)
+	corperm.Register("base", basterm.Activities, ActivitySelf)
+	corperm.Register("base", basterm.Cities, CityRead, CityWrite, CityExcel)
+	corperm.Register("base", basterm.APIKeys, APIKeyRead, APIKeyWrite)
+	corperm.Register("base", basterm.Sessions, SessionRead, SessionWrite)
+	corperm.Register("base", basterm.Grants, GrantRead, GrantWrite)
+}
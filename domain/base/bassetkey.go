@@ -9,12 +9,39 @@ import (
 const (
 	DefaultLang           types.Setting = "default_language"
 	DefaultRegisteredRole types.Setting = "default_registered_role"
+	MFARequiredRoles      types.Setting = "mfa_required_roles"
+	LoginMaxAttempts      types.Setting = "login_max_attempts"
+	LoginIPMaxAttempts    types.Setting = "login_ip_max_attempts"
+	LoginLockoutDuration  types.Setting = "login_lockout_duration"
+	LoginDelayStep        types.Setting = "login_delay_step"
+
+	PasswordMinLength       types.Setting = "password_min_length"
+	PasswordRequiredClasses types.Setting = "password_required_classes"
+	PasswordHistory         types.Setting = "password_history"
+	PasswordMaxAge          types.Setting = "password_max_age"
+
+	RegistrationVerifyEmail     types.Setting = "registration_verify_email"
+	RegistrationRequireApproval types.Setting = "registration_require_approval"
+
+	DeletedRetention types.Setting = "deleted_retention"
 )
 
 // List is used for validation
 var List = []types.Setting{
 	DefaultLang,
 	DefaultRegisteredRole,
+	MFARequiredRoles,
+	LoginMaxAttempts,
+	LoginIPMaxAttempts,
+	LoginLockoutDuration,
+	LoginDelayStep,
+	PasswordMinLength,
+	PasswordRequiredClasses,
+	PasswordHistory,
+	PasswordMaxAge,
+	RegistrationVerifyEmail,
+	RegistrationRequireApproval,
+	DeletedRetention,
 }
 
 // Join make a string for showing in the api
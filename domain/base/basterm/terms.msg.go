@@ -27,4 +27,108 @@ const (
 	UserLogedInSuccessfully    = "user loged in successfully"
 	UsernameAndPassword        = "username and password"
 	UserRegisteredSuccessfully = "user registered successfully"
+	RefreshToken               = "refresh token"
+	TokenRefreshedSuccessfully = "token refreshed successfully"
+	YourAccountIsNotActive     = "your account is not active"
+
+	TwoFactorAuthentication             = "two-factor authentication"
+	TwoFactorCode                       = "two-factor code"
+	MFAToken                            = "mfa token"
+	TwoFactorCodeIsWrong                = "two-factor code is wrong"
+	TwoFactorAuthenticationIsNotEnabled = "two-factor authentication is not enabled"
+	TwoFactorAuthenticationIsEnabled    = "two-factor authentication is already enabled"
+	TwoFactorIsRequiredForYourRole      = "two-factor authentication is required for your role"
+	EnterTwoFactorCode                  = "enter the two-factor code for completing the login"
+	ScanTheQRCodeAndConfirm             = "scan the QR code and confirm it with a two-factor code"
+	KeepRecoveryCodesSafe               = "two-factor authentication enabled, keep the recovery codes safe"
+	TwoFactorDisabled                   = "two-factor authentication disabled"
+	OnlySuperAdminCanChangeV            = "only super admin can change %v"
+
+	TooManyFailedAttemptsTryAfterV = "too many failed attempts, try again after %v"
+	UserUnlockedSuccessfully       = "user unlocked successfully"
+
+	CurrentPassword                                = "current password"
+	NewPassword                                    = "new password"
+	ResetToken                                     = "reset token"
+	PasswordReset                                  = "password reset"
+	CurrentPasswordIsWrong                         = "current password is wrong"
+	ResetTokenIsNotValid                           = "reset token is not valid or expired"
+	PasswordChangedSuccessfully                    = "password changed successfully"
+	PasswordResetSuccessfully                      = "password reset successfully"
+	ResetTokenSentIfEmailExists                    = "in case the email exists, the reset token has been sent to it"
+	UseTheTokenForResettingPasswordItExpiresAfterV = "use this token for resetting your password, it expires after %v"
+
+	LowercaseLetter                           = "lowercase letter"
+	UppercaseLetter                           = "uppercase letter"
+	Digit                                     = "digit"
+	Symbol                                    = "symbol"
+	VShouldContainAtLeastOneV                 = "%v should contain at least one %v"
+	VIsTooCommonChooseAnotherOne              = "%v is too common, choose another one"
+	VIsUsedRecentlyChooseAnotherOne           = "%v is used recently, choose another one"
+	PasswordIsExpiredResetItViaForgotPassword = "password is expired, reset it via forgot password"
+
+	APIKey                      = "api key"
+	APIKeys                     = "api keys"
+	ExpiresAt                   = "expires at"
+	AllowedIPs                  = "allowed IPs"
+	APIKeyIsNotValid            = "api key is not valid"
+	APIKeyCreatedKeepTheKeySafe = "api key created, keep the key safe"
+	APIKeyRevoked               = "api key revoked"
+
+	Session                             = "session"
+	Sessions                            = "sessions"
+	SessionIsTerminatedPleaseLoginAgain = "session is terminated, please login again"
+	SessionTerminated                   = "session terminated"
+
+	ImpersonatedUserCantImpersonate = "impersonated user can't impersonate another user"
+	YouCantImpersonateYourself      = "you can't impersonate yourself"
+	ImpersonatingV                  = "impersonating %v"
+
+	VerificationToken                               = "verification token"
+	EmailVerification                               = "email verification"
+	VerificationTokenIsNotValid                     = "verification token is not valid or expired"
+	OpenTheLinkForVerifyingYourEmailItExpiresAfterV = "open the link for verifying your email, it expires after %v"
+	EmailVerifiedSuccessfully                       = "email verified successfully"
+	VerificationSentIfEmailIsPending                = "in case the email is waiting for verification, the link has been sent to it"
+	VerifyYourEmailBeforeLogin                      = "verify your email before login"
+	YourAccountIsWaitingForApproval                 = "your account is waiting for approval"
+	UserIsNotWaitingForApproval                     = "user is not waiting for approval"
+	RegistrationIsWaitingForApproval                = "registration is waiting for approval"
+	VIsRegisteredAndWaitingForApproval              = "%v is registered and waiting for approval"
+	AccountApproved                                 = "account approved"
+	YourAccountIsApprovedYouCanLoginNow             = "your account is approved, you can login now"
+	UserApprovedSuccessfully                        = "user approved successfully"
+
+	System          = "system"
+	YourPermissions = "your permissions"
+
+	YourCompanies                         = "your companies"
+	CompaniesOfUser                       = "companies of the user"
+	CompanySwitched                       = "company switched"
+	YouAreNotAMemberOfThisCompany         = "you are not a member of this company"
+	CrossTenantModeIsOnlyForSuperAdmin    = "cross tenant mode is only allowed for the super admin"
+	ImpersonatedUserCantSwitchCompany     = "impersonated user can't switch the company"
+	YouCantChangeMembershipOfOtherCompany = "you can't change the membership of the companies which you are not a member of"
+
+	ParentRoles                  = "parent roles"
+	RoleCantExtendItsDescendants = "role can't extend itself or the roles which inherit from it"
+
+	Grant                           = "grant"
+	Grants                          = "grants"
+	StartsAt                        = "starts at"
+	EndsAt                          = "ends at"
+	Reason                          = "reason"
+	GrantsOfUser                    = "grants of the user"
+	YourGrants                      = "your grants"
+	GrantRevoked                    = "grant revoked"
+	YouCantGrantResourcesToYourself = "you can't grant resources to yourself"
+
+	View                                = "view"
+	Views                               = "views"
+	Entity                              = "entity"
+	Search                              = "search"
+	Sort                                = "sort"
+	PageSize                            = "page size"
+	YouCanShareTheViewOnlyWithYourRoles = "you can share the view only with your roles"
+	OnlyTheOwnerCanChangeTheView        = "only the owner can change the view"
 )
@@ -9,6 +9,9 @@ const (
 	Active    types.Enum = "active"
 	Inactive  types.Enum = "inactive"
 	Terminate types.Enum = "terminate"
+
+	PendingVerification types.Enum = "pending_verification"
+	PendingApproval     types.Enum = "pending_approval"
 )
 
 // List is used for validation
@@ -16,6 +19,8 @@ var List = []types.Enum{
 	Active,
 	Inactive,
 	Terminate,
+	PendingVerification,
+	PendingApproval,
 }
 
 // Join make a string for showing in the api
@@ -43,7 +43,7 @@ func (p *MessageAPI) FindByID(c *gin.Context) {
 	}
 
 	resp.Record(notification.ViewMessage)
-	resp.Status(http.StatusOK).
+	resp.SetETag(message.Model).Status(http.StatusOK).
 		MessageT(corterm.VInfo, corterm.Message).
 		JSON(message)
 }
@@ -92,7 +92,13 @@ func (p *MessageAPI) List(c *gin.Context) {
 
 	scope := c.Query("scope")
 
-	if data["list"], data["count"], err = p.Service.List(params, scope); err != nil {
+	if params.Aggregated() {
+		data["list"], err = p.Service.Aggregate(params, scope)
+	} else {
+		data["list"], data["count"], err = p.Service.List(params, scope)
+	}
+
+	if err != nil {
 		resp.Error(err).JSON()
 		return
 	}
@@ -100,6 +106,7 @@ func (p *MessageAPI) List(c *gin.Context) {
 	resp.Record(notification.ListMessage)
 	resp.Status(http.StatusOK).
 		MessageT(corterm.ListOfV, corterm.Messages).
+		Extra(params.Keyset(data["list"], p.Service.Repo.Cols)).
 		JSON(data)
 }
 
@@ -120,7 +127,7 @@ func (p *MessageAPI) Create(c *gin.Context) {
 	}
 
 	resp.RecordCreate(notification.CreateMessage, message)
-	resp.Status(http.StatusOK).
+	resp.SetETag(createdMessage.Model).Status(http.StatusOK).
 		MessageT(corterm.VCreatedSuccessfully, corterm.Message).
 		JSON(createdMessage)
 }
@@ -137,24 +144,29 @@ func (p *MessageAPI) Update(c *gin.Context) {
 		return
 	}
 
-	if err = resp.Bind(&message, "E8250051", notification.Domain, corterm.Message); err != nil {
+	if messageBefore, err = p.Service.FindByID(id); err != nil {
+		resp.Error(err).JSON()
 		return
 	}
 
-	if messageBefore, err = p.Service.FindByID(id); err != nil {
-		resp.Error(err).JSON()
+	if err = resp.IfMatch(messageBefore.Model, messageBefore, "E1018593", corterm.Message); err != nil {
+		return
+	}
+
+	if err = resp.BindUpdate(&message, messageBefore, "E8250051", notification.Domain, corterm.Message); err != nil {
 		return
 	}
 
 	message.ID = id
 	message.CreatedAt = messageBefore.CreatedAt
+	message.UpdatedAt = messageBefore.UpdatedAt
 	if messageUpdated, err = p.Service.Save(message); err != nil {
 		resp.Error(err).JSON()
 		return
 	}
 
-	resp.Record(notification.UpdateMessage, messageBefore, message)
-	resp.Status(http.StatusOK).
+	resp.Record(notification.UpdateMessage, messageBefore, messageUpdated)
+	resp.SetETag(messageUpdated.Model).Status(http.StatusOK).
 		MessageT(corterm.VUpdatedSuccessfully, corterm.Message).
 		JSON(messageUpdated)
 }
@@ -181,6 +193,28 @@ func (p *MessageAPI) Delete(c *gin.Context) {
 		JSON()
 }
 
+// Restore the deleted message
+func (p *MessageAPI) Restore(c *gin.Context) {
+	resp := response.New(p.Engine, c, notification.Domain)
+	var err error
+	var message notmodel.Message
+	var id uint
+
+	if id, err = resp.GetID(c.Param("messageID"), "E1073500", corterm.Message); err != nil {
+		return
+	}
+
+	if message, err = p.Service.Restore(id); err != nil {
+		resp.Error(err).JSON()
+		return
+	}
+
+	resp.Record(notification.RestoreMessage, message)
+	resp.Status(http.StatusOK).
+		MessageT(corterm.VRestoredSuccessfully, corterm.Message).
+		JSON(message)
+}
+
 // Excel generate excel files eaced on search
 func (p *MessageAPI) Excel(c *gin.Context) {
 	resp, params := response.NewParam(p.Engine, c, corterm.Messages, notification.Domain)
@@ -19,8 +19,8 @@ type Message struct {
 	CreatedBy   *uint      `json:"created_by"`
 	RecipientID uint       `json:"recipient_id"`
 	Hash        uint64     `gorm:"not null;unique;type:varchar(50)" json:"hash,omitempty"`
-	Title       string     `gorm:"type:varchar(200)" json:"title,omitempty"`
-	Message     string     `gorm:"not null" json:"message,omitempty"`
+	Title       string     `gorm:"type:varchar(200)" json:"title,omitempty" search:"fulltext"`
+	Message     string     `gorm:"not null" json:"message,omitempty" search:"fulltext"`
 	URI         string     `json:"uri"`
 	Part        string     `gorm:"type:varchar(50)" json:"part"`
 	Status      types.Enum `gorm:"not null;default:'new';type:enum('new','seen')" json:"status"`
@@ -10,15 +10,18 @@ import (
 	"omono/internal/param"
 	"omono/pkg/helper"
 	"reflect"
+	"time"
 
 	"github.com/syronz/dict"
 	"github.com/syronz/limberr"
+	"gorm.io/gorm"
 )
 
 // MessageRepo for injecting engine
 type MessageRepo struct {
 	Engine *core.Engine
 	Cols   []string
+	Search helper.Searchable
 }
 
 // ProvideMessageRepo is used in wire and initiate the Cols
@@ -26,6 +29,7 @@ func ProvideMessageRepo(engine *core.Engine) MessageRepo {
 	return MessageRepo{
 		Engine: engine,
 		Cols:   helper.TagExtracter(reflect.TypeOf(notmodel.Message{}), notmodel.MessageTable),
+		Search: helper.SearchExtracter(reflect.TypeOf(notmodel.Message{}), notmodel.MessageTable),
 	}
 }
 
@@ -43,50 +47,102 @@ func (p *MessageRepo) FindByID(id uint) (message notmodel.Message, err error) {
 
 // List returns an array of messages
 func (p *MessageRepo) List(params param.Param) (messages []notmodel.Message, err error) {
+	params.ScopeDeleted(notmodel.MessageTable)
+
 	var colsStr string
 	if colsStr, err = validator.CheckColumns(p.Cols, params.Select); err != nil {
 		err = limberr.Take(err, "E8229822").Build()
 		return
 	}
 
+	params.ScopeSearch(p.Search)
+	if err = params.ScopeSort(p.Cols); err != nil {
+		err = limberr.Take(err, "E1012611").Custom(corerr.ValidationFailedErr).Build()
+		return
+	}
+
 	var whereStr string
-	if whereStr, err = params.ParseWhere(p.Cols); err != nil {
+	var args []interface{}
+	if whereStr, args, err = params.ParseWhere(p.Cols); err != nil {
 		err = limberr.Take(err, "E8255021").Custom(corerr.ValidationFailedErr).Build()
 		return
 	}
 
-	err = p.Engine.ReadDB.Table(notmodel.MessageTable).Select(colsStr).
-		Where(whereStr).
-		Order(params.Order).
+	err = p.Engine.ReadDB.Unscoped().Table(notmodel.MessageTable).Select(colsStr).
+		Where(whereStr, args...).
+		Clauses(params.OrderBy()).
 		Limit(params.Limit).
 		Offset(params.Offset).
 		Find(&messages).Error
 
 	err = p.dbError(err, "E8232329", notmodel.Message{}, corterm.List)
+	params.Arrange(messages)
 
 	return
 }
 
 // Count of messages, mainly calls with List
 func (p *MessageRepo) Count(params param.Param) (count int64, err error) {
+	params.ScopeDeleted(notmodel.MessageTable)
+	params.ScopeSearch(p.Search)
+
 	var whereStr string
-	if whereStr, err = params.ParseWhere(p.Cols); err != nil {
+	var args []interface{}
+	if whereStr, args, err = params.ParseWhere(p.Cols); err != nil {
 		err = limberr.Take(err, "E8292390").Custom(corerr.ValidationFailedErr).Build()
 		return
 	}
 
 	err = p.Engine.ReadDB.Table(notmodel.MessageTable).
-		Where(whereStr).
+		Where(whereStr, args...).
 		Count(&count).Error
 
 	err = p.dbError(err, "E8271205", notmodel.Message{}, corterm.List)
 	return
 }
 
-// Save the message, in case it is not exist create it
+// Aggregate returns the grouped rows of the messages, the conditions are the same as the List
+func (p *MessageRepo) Aggregate(params param.Param) (rows []map[string]interface{}, err error) {
+	params.ScopeDeleted(notmodel.MessageTable)
+	params.ScopeSearch(p.Search)
+
+	var aggregate func(*gorm.DB) *gorm.DB
+	if aggregate, err = params.ScopeAggregate(p.Cols); err != nil {
+		err = limberr.Take(err, "E1066438").Custom(corerr.ValidationFailedErr).Build()
+		return
+	}
+
+	var whereStr string
+	var args []interface{}
+	if whereStr, args, err = params.ParseWhere(p.Cols); err != nil {
+		err = limberr.Take(err, "E1046860").Custom(corerr.ValidationFailedErr).Build()
+		return
+	}
+
+	err = p.Engine.ReadDB.Table(notmodel.MessageTable).
+		Where(whereStr, args...).
+		Scopes(aggregate).
+		Limit(params.Limit).
+		Offset(params.Offset).
+		Find(&rows).Error
+
+	err = p.dbError(err, "E1090465", notmodel.Message{}, corterm.List)
+	return
+}
+
+// Save the message, the updated_at is the version of the message which is fetched before, in case
+// another request changed the message after that it is not saved
 func (p *MessageRepo) Save(message notmodel.Message) (u notmodel.Message, err error) {
-	if err = p.Engine.DB.Table(notmodel.MessageTable).Save(&message).Error; err != nil {
+	result := p.Engine.DB.Table(notmodel.MessageTable).Select("*").
+		Where("updated_at = ?", message.UpdatedAt).Save(&message)
+	if err = result.Error; err != nil {
 		err = p.dbError(err, "E8275412", message, corterm.Updated)
+		return
+	}
+
+	if result.RowsAffected == 0 {
+		err = corerr.PreconditionFailedHelper("E1086422", corterm.Message)
+		return
 	}
 
 	p.Engine.DB.Table(notmodel.MessageTable).Where("id = ?", message.ID).Find(&u)
@@ -109,6 +165,31 @@ func (p *MessageRepo) Delete(message notmodel.Message) (err error) {
 	return
 }
 
+// Restore the soft deleted message
+func (p *MessageRepo) Restore(id uint) (err error) {
+	result := p.Engine.DB.Unscoped().Table(notmodel.MessageTable).
+		Where("not_messages.id = ? AND not_messages.deleted_at IS NOT NULL", id).
+		Update("deleted_at", nil)
+	if err = result.Error; err == nil && result.RowsAffected == 0 {
+		err = gorm.ErrRecordNotFound
+	}
+
+	var message notmodel.Message
+	message.ID = id
+	err = p.dbError(err, "E1072258", message, corterm.Updated)
+	return
+}
+
+// PurgeDeleted hard delete the messages which are soft deleted before the time
+func (p *MessageRepo) PurgeDeleted(before time.Time) (err error) {
+	err = p.Engine.DB.Unscoped().Table(notmodel.MessageTable).
+		Where("deleted_at < ?", before).
+		Delete(&notmodel.Message{}).Error
+
+	err = p.dbError(err, "E1015909", notmodel.Message{}, corterm.Deleted)
+	return
+}
+
 // dbError is an internal method for generate proper dataeace error
 func (p *MessageRepo) dbError(err error, code string, message notmodel.Message, action string) error {
 	switch corerr.ClearDbErr(err) {
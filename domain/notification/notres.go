@@ -1,6 +1,10 @@
 package notification
 
-import "omono/internal/types"
+import (
+	"omono/internal/core/corperm"
+	"omono/internal/core/corterm"
+	"omono/internal/types"
+)
 
 // list of resources for notification domain
 const (
@@ -10,3 +14,7 @@ const (
 	MessageRead  types.Resource = "message:read"
 	MessageExcel types.Resource = "message:excel"
 )
+
+func init() {
+	corperm.Register("notification", corterm.Messages, MessageRead, MessageWrite, MessageExcel)
+}
@@ -36,13 +36,13 @@ func (p *CompanyAPI) FindByID(c *gin.Context) {
 		return
 	}
 
-	if company, err = p.Service.FindByID(id); err != nil {
+	if company, err = p.Service.FindByID(resp.Params(segmodel.CompanyTable), id); err != nil {
 		resp.Error(err).JSON()
 		return
 	}
 
 	resp.Record(segment.ViewCompany)
-	resp.Status(http.StatusOK).
+	resp.SetETag(company.Model).Status(http.StatusOK).
 		MessageT(corterm.VInfo, basterm.Company).
 		JSON(company)
 }
@@ -54,7 +54,13 @@ func (p *CompanyAPI) List(c *gin.Context) {
 	data := make(map[string]interface{})
 	var err error
 
-	if data["list"], data["count"], err = p.Service.List(params); err != nil {
+	if params.Aggregated() {
+		data["list"], err = p.Service.Aggregate(params)
+	} else {
+		data["list"], data["count"], err = p.Service.List(params)
+	}
+
+	if err != nil {
 		resp.Error(err).JSON()
 		return
 	}
@@ -62,6 +68,7 @@ func (p *CompanyAPI) List(c *gin.Context) {
 	resp.Record(segment.ListCompany)
 	resp.Status(http.StatusOK).
 		MessageT(corterm.ListOfV, basterm.Companies).
+		Extra(params.Keyset(data["list"], p.Service.Repo.Cols)).
 		JSON(data)
 }
 
@@ -75,13 +82,13 @@ func (p *CompanyAPI) Create(c *gin.Context) {
 		return
 	}
 
-	if createdCompany, err = p.Service.Create(company); err != nil {
+	if createdCompany, err = p.Service.Create(resp.Params(segmodel.CompanyTable), company); err != nil {
 		resp.Error(err).JSON()
 		return
 	}
 
 	// resp.RecordCreate(segment.CreateCompany, company)
-	resp.Status(http.StatusOK).
+	resp.SetETag(createdCompany.Model).Status(http.StatusOK).
 		MessageT(corterm.VCreatedSuccessfully, basterm.Company).
 		JSON(createdCompany)
 }
@@ -98,19 +105,29 @@ func (p *CompanyAPI) Update(c *gin.Context) {
 		return
 	}
 
-	if err = resp.Bind(&company, "E1086162", segment.Domain, basterm.Company); err != nil {
+	if companyBefore, err = p.Service.FindByID(resp.Params(segmodel.CompanyTable), id); err != nil {
+		resp.Error(err).JSON()
+		return
+	}
+
+	if err = resp.IfMatch(companyBefore.Model, companyBefore, "E1067535", basterm.Company); err != nil {
+		return
+	}
+
+	if err = resp.BindUpdate(&company, companyBefore, "E1086162", segment.Domain, basterm.Company); err != nil {
 		return
 	}
 
 	company.ID = id
 	company.CreatedAt = companyBefore.CreatedAt
-	if companyUpdated, companyBefore, err = p.Service.Save(company); err != nil {
+	company.UpdatedAt = companyBefore.UpdatedAt
+	if companyUpdated, companyBefore, err = p.Service.Save(resp.Params(segmodel.CompanyTable), company); err != nil {
 		resp.Error(err).JSON()
 		return
 	}
 
-	resp.Record(segment.UpdateCompany, companyBefore, company)
-	resp.Status(http.StatusOK).
+	resp.Record(segment.UpdateCompany, companyBefore, companyUpdated)
+	resp.SetETag(companyUpdated.Model).Status(http.StatusOK).
 		MessageT(corterm.VUpdatedSuccessfully, basterm.Company).
 		JSON(companyUpdated)
 }
@@ -126,7 +143,7 @@ func (p *CompanyAPI) Delete(c *gin.Context) {
 		return
 	}
 
-	if company, err = p.Service.Delete(id); err != nil {
+	if company, err = p.Service.Delete(resp.Params(segmodel.CompanyTable), id); err != nil {
 		resp.Error(err).JSON()
 		return
 	}
@@ -137,6 +154,28 @@ func (p *CompanyAPI) Delete(c *gin.Context) {
 		JSON()
 }
 
+// Restore the deleted company
+func (p *CompanyAPI) Restore(c *gin.Context) {
+	resp := response.New(p.Engine, c, segment.Domain)
+	var err error
+	var company segmodel.Company
+	var id uint
+
+	if id, err = resp.GetID(c.Param("companyID"), "E1027073", basterm.Company); err != nil {
+		return
+	}
+
+	if company, err = p.Service.Restore(resp.Params(segmodel.CompanyTable), id); err != nil {
+		resp.Error(err).JSON()
+		return
+	}
+
+	resp.Record(segment.RestoreCompany, company)
+	resp.Status(http.StatusOK).
+		MessageT(corterm.VRestoredSuccessfully, basterm.Company).
+		JSON(company)
+}
+
 // Excel generate excel files based on search
 func (p *CompanyAPI) Excel(c *gin.Context) {
 	resp, params := response.NewParam(p.Engine, c, basterm.Companies, segment.Domain)
@@ -4,10 +4,11 @@ import "omono/internal/types"
 
 // types for subscribe domain
 const (
-	CreateCompany types.Event = "company-create"
-	UpdateCompany types.Event = "company-update"
-	DeleteCompany types.Event = "company-delete"
-	ListCompany   types.Event = "company-list"
-	ViewCompany   types.Event = "company-view"
-	ExcelCompany  types.Event = "company-excel"
+	CreateCompany  types.Event = "company-create"
+	UpdateCompany  types.Event = "company-update"
+	DeleteCompany  types.Event = "company-delete"
+	RestoreCompany types.Event = "company-restore"
+	ListCompany    types.Event = "company-list"
+	ViewCompany    types.Event = "company-view"
+	ExcelCompany   types.Event = "company-excel"
 )
@@ -18,8 +18,8 @@ const (
 // Company model
 type Company struct {
 	gorm.Model
-	Name  string  `gorm:"unique" json:"name,omitempty"`
-	Phone string  `json:"phone"`
+	Name  string  `gorm:"type:varchar(191)" json:"name,omitempty" search:"fulltext"`
+	Phone string  `json:"phone" search:"like"`
 	Notes float64 `json:"notes,omitempty"`
 }
 
@@ -11,6 +11,7 @@ import (
 	"omono/internal/param"
 	"omono/pkg/helper"
 	"reflect"
+	"time"
 
 	"github.com/syronz/dict"
 	"github.com/syronz/limberr"
@@ -22,6 +23,7 @@ import (
 type CompanyRepo struct {
 	Engine *core.Engine
 	Cols   []string
+	Search helper.Searchable
 }
 
 // ProvideCompanyRepo is used in wire and initiate the Cols
@@ -29,14 +31,24 @@ func ProvideCompanyRepo(engine *core.Engine) CompanyRepo {
 	return CompanyRepo{
 		Engine: engine,
 		Cols:   helper.TagExtracter(reflect.TypeOf(segmodel.Company{}), segmodel.CompanyTable),
+		Search: helper.SearchExtracter(reflect.TypeOf(segmodel.Company{}), segmodel.CompanyTable),
 	}
 }
 
-// FindByID finds the company via its id
-func (p *CompanyRepo) FindByID(id uint) (company segmodel.Company, err error) {
-	err = p.Engine.ReadDB.Table(segmodel.CompanyTable).
-		Where("id = ? AND sub_companies.deleted_at is null", id).
-		First(&company).Error
+// companyScope limits the companies to the active company of the params
+const companyScope = "seg_companies.id = %v"
+
+// FindByID finds the company via its id, it is limited to the active company of the params
+func (p *CompanyRepo) FindByID(params param.Param, id uint) (company segmodel.Company, err error) {
+	params.ScopeCompany(companyScope)
+
+	db := p.Engine.ReadDB.Table(segmodel.CompanyTable).
+		Where("id = ? AND seg_companies.deleted_at is null", id)
+	if params.PreCondition != "" {
+		db = db.Where(params.PreCondition)
+	}
+
+	err = db.First(&company).Error
 
 	company.ID = id
 	err = p.dbError(err, "E1045869", company, corterm.List)
@@ -48,7 +60,7 @@ func (p *CompanyRepo) FindByID(id uint) (company segmodel.Company, err error) {
 func (p *CompanyRepo) TxFindCompanyStatus(db *gorm.DB, id uint) (company segmodel.Company, err error) {
 	// err = db.Clauses(clause.Locking{Strength: "UPDATE"}).Table(segmodel.CompanyTable).
 	err = db.Table(segmodel.CompanyTable).
-		Where("id = ? AND sub_companies.deleted_at is null", id).
+		Where("id = ? AND seg_companies.deleted_at is null", id).
 		First(&company).Error
 
 	company.ID = id
@@ -59,50 +71,105 @@ func (p *CompanyRepo) TxFindCompanyStatus(db *gorm.DB, id uint) (company segmode
 
 // List returns an array of companies
 func (p *CompanyRepo) List(params param.Param) (companies []segmodel.Company, err error) {
+	params.ScopeCompany(companyScope)
+	params.ScopeDeleted(segmodel.CompanyTable)
+
 	var colsStr string
 	if colsStr, err = validator.CheckColumns(p.Cols, params.Select); err != nil {
 		err = limberr.Take(err, "E1050070").Build()
 		return
 	}
 
+	params.ScopeSearch(p.Search)
+	if err = params.ScopeSort(p.Cols); err != nil {
+		err = limberr.Take(err, "E1086937").Custom(corerr.ValidationFailedErr).Build()
+		return
+	}
+
 	var whereStr string
-	if whereStr, err = params.ParseWhere(p.Cols); err != nil {
+	var args []interface{}
+	if whereStr, args, err = params.ParseWhere(p.Cols); err != nil {
 		err = limberr.Take(err, "E1084619").Custom(corerr.ValidationFailedErr).Build()
 		return
 	}
 
-	err = p.Engine.ReadDB.Table(segmodel.CompanyTable).Select(colsStr).
-		Where(whereStr).
-		Order(params.Order).
+	err = p.Engine.ReadDB.Unscoped().Table(segmodel.CompanyTable).Select(colsStr).
+		Where(whereStr, args...).
+		Clauses(params.OrderBy()).
 		Limit(params.Limit).
 		Offset(params.Offset).
 		Find(&companies).Error
 
 	err = p.dbError(err, "E1082445", segmodel.Company{}, corterm.List)
+	params.Arrange(companies)
 
 	return
 }
 
 // Count of companies, mainly calls with List
 func (p *CompanyRepo) Count(params param.Param) (count int64, err error) {
+	params.ScopeCompany(companyScope)
+	params.ScopeDeleted(segmodel.CompanyTable)
+	params.ScopeSearch(p.Search)
+
 	var whereStr string
-	if whereStr, err = params.ParseWhere(p.Cols); err != nil {
+	var args []interface{}
+	if whereStr, args, err = params.ParseWhere(p.Cols); err != nil {
 		err = limberr.Take(err, "E1037218").Custom(corerr.ValidationFailedErr).Build()
 		return
 	}
 
 	err = p.Engine.ReadDB.Table(segmodel.CompanyTable).
-		Where(whereStr).
+		Where(whereStr, args...).
 		Count(&count).Error
 
 	err = p.dbError(err, "E1056203", segmodel.Company{}, corterm.List)
 	return
 }
 
-// TxSave the company, in case it is not exist create it
+// Aggregate returns the grouped rows of the companies, the conditions are the same as the List
+func (p *CompanyRepo) Aggregate(params param.Param) (rows []map[string]interface{}, err error) {
+	params.ScopeCompany(companyScope)
+	params.ScopeDeleted(segmodel.CompanyTable)
+	params.ScopeSearch(p.Search)
+
+	var aggregate func(*gorm.DB) *gorm.DB
+	if aggregate, err = params.ScopeAggregate(p.Cols); err != nil {
+		err = limberr.Take(err, "E1059762").Custom(corerr.ValidationFailedErr).Build()
+		return
+	}
+
+	var whereStr string
+	var args []interface{}
+	if whereStr, args, err = params.ParseWhere(p.Cols); err != nil {
+		err = limberr.Take(err, "E1022306").Custom(corerr.ValidationFailedErr).Build()
+		return
+	}
+
+	err = p.Engine.ReadDB.Table(segmodel.CompanyTable).
+		Where(whereStr, args...).
+		Scopes(aggregate).
+		Limit(params.Limit).
+		Offset(params.Offset).
+		Find(&rows).Error
+
+	err = p.dbError(err, "E1065169", segmodel.Company{}, corterm.List)
+	return
+}
+
+// TxSave the company, the updated_at is the version of the company which is fetched before, in case
+// another request changed the company after that it is not saved
 func (p *CompanyRepo) TxSave(db *gorm.DB, company segmodel.Company) (u segmodel.Company, err error) {
-	if err = db.Table(segmodel.CompanyTable).Save(&company).Error; err != nil {
+	result := db.Table(segmodel.CompanyTable).Select("*").
+		Where("updated_at = ?", company.UpdatedAt).Save(&company)
+	if err = result.Error; err != nil {
 		err = p.dbError(err, "E1070874", company, corterm.Updated)
+		return
+	}
+
+	if result.RowsAffected == 0 {
+		err = corerr.PreconditionFailedHelper("E1031147", basterm.Company)
+		return
 	}
 
 	db.Table(segmodel.CompanyTable).Where("id = ?", company.ID).Find(&u)
@@ -127,12 +194,43 @@ func (p *CompanyRepo) TxCreate(db *gorm.DB, company segmodel.Company) (u segmode
 
 // Delete the company
 func (p *CompanyRepo) Delete(company segmodel.Company) (err error) {
-	if err = p.Engine.DB.Unscoped().Table(segmodel.CompanyTable).Delete(&company).Error; err != nil {
+	if err = p.Engine.DB.Table(segmodel.CompanyTable).Delete(&company).Error; err != nil {
 		err = p.dbError(err, "E1095299", company, corterm.Deleted)
 	}
 	return
 }
 
+// Restore the soft deleted company, it is limited to the active company of the params
+func (p *CompanyRepo) Restore(params param.Param, id uint) (err error) {
+	params.ScopeCompany(companyScope)
+
+	db := p.Engine.DB.Unscoped().Table(segmodel.CompanyTable).
+		Where("seg_companies.id = ? AND seg_companies.deleted_at IS NOT NULL", id)
+	if params.PreCondition != "" {
+		db = db.Where(params.PreCondition)
+	}
+
+	result := db.Update("deleted_at", nil)
+	if err = result.Error; err == nil && result.RowsAffected == 0 {
+		err = gorm.ErrRecordNotFound
+	}
+
+	var company segmodel.Company
+	company.ID = id
+	err = p.dbError(err, "E1099700", company, corterm.Updated)
+	return
+}
+
+// PurgeDeleted hard delete the companies which are soft deleted before the time
+func (p *CompanyRepo) PurgeDeleted(before time.Time) (err error) {
+	err = p.Engine.DB.Unscoped().Table(segmodel.CompanyTable).
+		Where("deleted_at < ?", before).
+		Delete(&segmodel.Company{}).Error
+
+	err = p.dbError(err, "E1040448", segmodel.Company{}, corterm.Deleted)
+	return
+}
+
 // dbError is an internal method for generate proper database error
 func (p *CompanyRepo) dbError(err error, code string, company segmodel.Company, action string) error {
 	switch corerr.ClearDbErr(err) {
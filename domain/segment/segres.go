@@ -1,6 +1,10 @@
 package segment
 
-import "omono/internal/types"
+import (
+	"omono/domain/segment/segterm"
+	"omono/internal/core/corperm"
+	"omono/internal/types"
+)
 
 // list of resources for subscribe domain
 const (
@@ -10,3 +14,7 @@ const (
 	CompanyWrite types.Resource = "company:write"
 	CompanyExcel types.Resource = "company:excel"
 )
+
+func init() {
+	corperm.Register("segment", segterm.Companies, CompanyRead, CompanyWrite, CompanyExcel)
+}
@@ -2,13 +2,20 @@ package service
 
 import (
 	"omono/domain/base"
+	"omono/domain/base/basmodel"
 	"omono/domain/base/basrepo"
 	"omono/internal/core"
+	"omono/internal/core/corerr"
+	"omono/internal/core/corperm"
 	"omono/internal/types"
 	"omono/pkg/glog"
+	"omono/pkg/helper/permcache"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/syronz/dict"
+	"github.com/syronz/limberr"
 )
 
 // BasAccessServ defining auth service
@@ -22,10 +29,13 @@ func ProvideBasAccessService(p basrepo.AccessRepo) BasAccessServ {
 	return BasAccessServ{Repo: p, Engine: p.Engine}
 }
 
-var cacheResource map[uint]string
+// permissionCache keeps the resources of the users, it is replaced in the startoff according
+// to the PERMISSION_CACHE_BACKEND
+var permissionCache permcache.Cache = permcache.NewMemory(permcache.DefaultTTL * time.Second)
 
-func init() {
-	cacheResource = make(map[uint]string)
+// BasAccessSetCache replace the permission cache, it should be called before serving requests
+func BasAccessSetCache(cache permcache.Cache) {
+	permissionCache = cache
 }
 
 // CheckAccess is used inside each method to findout if user has permission or not
@@ -38,48 +48,197 @@ func (p *BasAccessServ) CheckAccess(c *gin.Context, resource types.Resource) boo
 		return true
 	}
 
-	var resources string
-	var ok bool
+	set, err := p.setOf(c, userID)
+	glog.CheckError(err, "error in finding the resources for user", userID)
 
-	if resources, ok = cacheResource[userID]; !ok {
-		var err error
-		resources, err = p.Repo.GetUserResources(userID)
-		glog.CheckError(err, "error in finding the resources for user", userID)
-		BasAccessAddToCache(userID, resources)
+	return !set.Has(resource)
+}
+
+// setOf returns the permissions of the request, requests authenticated by api key are limited
+// to the resources of the key's role
+func (p *BasAccessServ) setOf(c *gin.Context, userID uint) (corperm.Set, error) {
+	if keyResources, ok := c.Get("RESOURCES"); ok {
+		return keyResources.(corperm.Set), nil
 	}
 
-	return !strings.Contains(resources, string(resource))
+	return p.permissions(userID)
+}
 
+// cachedPermissions is the value of the permission cache, it should be loaded again at the
+// until because one of the temporary grants of the user starts or ends
+type cachedPermissions struct {
+	set   corperm.Set
+	until *time.Time
 }
 
-func IsSuperAdmin(userID uint) bool {
-	return strings.Contains(cacheResource[userID], string(base.SuperAccess))
+// permissions returns the compiled resources of the user from the cache
+func (p *BasAccessServ) permissions(userID uint) (set corperm.Set, err error) {
+	var value interface{}
+	if value, err = permissionCache.Get(userID, p.load); err != nil {
+		return
+	}
+
+	cached := value.(cachedPermissions)
+	if cached.until != nil && !time.Now().Before(*cached.until) {
+		permissionCache.Invalidate(userID)
+		if value, err = permissionCache.Get(userID, p.load); err != nil {
+			return
+		}
+		cached = value.(cachedPermissions)
+	}
+
+	set = cached.set
+	return
+}
+
+// load compile the resources of the roles beside the active grants of the user, a deny rule in
+// the roles is not overridden by the grants
+func (p *BasAccessServ) load(userID uint) (interface{}, error) {
+	rules, err := p.Repo.GetUserResources(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	grantRepo := basrepo.ProvideGrantRepo(p.Engine)
+	granted, until, err := grantRepo.ActiveResources(userID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	return cachedPermissions{
+		set:   corperm.Compile(append(rules, granted...)...),
+		until: until,
+	}, nil
 }
 
-// CheckRange is used for checking if user has access to special range of data
-func (p *BasAccessServ) CheckRange(companyID, nodeID uint64) bool {
-	if companyID > 0 {
-		if companyID != 1001 {
-			return false
+// CheckRoleSubset prevent the user to act with more privileges than themselves, all resources
+// granted by the roles and their parents should be granted to the user as well unless the user
+// is super admin
+func (p *BasAccessServ) CheckRoleSubset(userID uint, roleIDs ...uint) (err error) {
+	roleServ := ProvideBasRoleService(basrepo.ProvideRoleRepo(p.Engine))
+	for _, v := range roleIDs {
+		if _, err = roleServ.FindByID(v); err != nil {
+			return
 		}
 	}
 
-	return true
+	var rules []corperm.Rules
+	if rules, err = p.Repo.GetRoleResources(roleIDs...); err != nil {
+		err = corerr.Tick(err, "E1010495", "roles' resources not fetched", roleIDs)
+		return
+	}
+
+	return p.CheckRulesSubset(userID, rules...)
 }
 
-// BasAccessAddToCache add the resources to the cacheResource
-func BasAccessAddToCache(userID uint, resources string) {
-	cacheResource[userID] = resources
+// CheckRulesSubset is like CheckRoleSubset for the rules which are not stored in a role, for
+// example the resources of the temporary grants
+func (p *BasAccessServ) CheckRulesSubset(userID uint, rules ...corperm.Rules) (err error) {
+	var userSet corperm.Set
+	if userSet, err = p.permissions(userID); err != nil {
+		err = corerr.Tick(err, "E1058005", "user's resources not fetched", userID)
+		return
+	}
+
+	if userSet.Has(base.SuperAccess) {
+		return
+	}
+
+	for _, v := range rules {
+		if uncovered := userSet.Covers(v); len(uncovered) > 0 {
+			err = limberr.New("role has more resources than the user", "E1093579").
+				Message(corerr.YouDontHavePermissionToThisV, uncovered[0]).
+				Custom(corerr.ForbiddenErr).Build()
+			return
+		}
+	}
+
+	return
 }
 
-func BasAccessDeleteFromCache(userID uint) {
-	delete(cacheResource, userID)
+// Effective returns the registered resources which are granted to the caller, the UI uses them
+// for hiding the actions which the user can't do
+func (p *BasAccessServ) Effective(c *gin.Context, userID uint) (perms basmodel.Permissions,
+	err error) {
+	var set corperm.Set
+	if set, err = p.setOf(c, userID); err != nil {
+		err = corerr.Tick(err, "E1052238", "user's resources not fetched", userID)
+		return
+	}
+
+	_, perms.APIKey = c.Get("API_KEY_ID")
+	perms.UserID = userID
+	perms.Resources = []types.Resource{}
+	for _, v := range corperm.Entries() {
+		if set.Has(v.Resource) {
+			perms.Resources = append(perms.Resources, v.Resource)
+		}
+	}
+
+	return
+}
+
+// Catalog returns all registered resources grouped by domain and entity, the descriptions
+// and titles are translated
+func (p *BasAccessServ) Catalog(lang dict.Lang) (domains []basmodel.ResourceDomain) {
+	for _, v := range corperm.Entries() {
+		if len(domains) == 0 || domains[len(domains)-1].Domain != v.Domain {
+			domains = append(domains, basmodel.ResourceDomain{
+				Domain:   v.Domain,
+				Wildcard: v.Domain + ":" + corperm.Wildcard,
+			})
+		}
+		domain := &domains[len(domains)-1]
+
+		if len(domain.Groups) == 0 || domain.Groups[len(domain.Groups)-1].Group != v.Group {
+			domain.Groups = append(domain.Groups, basmodel.ResourceGroup{
+				Group:    v.Group,
+				Title:    dict.T(v.Group, lang),
+				Wildcard: corperm.EntityOf(v.Resource) + ":" + corperm.Wildcard,
+			})
+		}
+		group := &domain.Groups[len(domain.Groups)-1]
+
+		// the wildcard of the entity is not equal to the group if it has several entities
+		if !strings.HasPrefix(string(v.Resource), strings.TrimSuffix(group.Wildcard,
+			corperm.Wildcard)) {
+			group.Wildcard = ""
+		}
+
+		group.Resources = append(group.Resources, basmodel.ResourceItem{
+			Resource:    v.Resource,
+			Description: dict.T(string(v.Resource), lang),
+		})
+	}
+
+	return
+}
+
+// HasSuperAccess load the resources of the user if they are not cached or their TTL is passed
+func (p *BasAccessServ) HasSuperAccess(userID uint) (super bool, err error) {
+	return p.HasResource(userID, base.SuperAccess)
+}
+
+// HasResource check the resource against the compiled resources of the user, the wildcards,
+// deny rules, parents of the roles and the grants are considered like the CheckAccess
+func (p *BasAccessServ) HasResource(userID uint, resource types.Resource) (has bool, err error) {
+	var set corperm.Set
+	if set, err = p.permissions(userID); err != nil {
+		err = corerr.Tick(err, "E1078445", "user's resources not fetched", userID)
+		return
+	}
+
+	has = set.Has(resource)
+	return
 }
 
-func BasAccessResetCache(userID uint) {
-	cacheResource[userID] = ""
+// BasAccessDeleteFromCache invalidate the cached resources of the users, it should be called
+// after committing the change of their role
+func BasAccessDeleteFromCache(userIDs ...uint) {
+	permissionCache.Invalidate(userIDs...)
 }
 
+// BasAccessResetFullCache invalidate all users, it is used after changing or deleting a role
 func BasAccessResetFullCache() {
-	cacheResource = make(map[uint]string)
+	permissionCache.InvalidateAll()
 }
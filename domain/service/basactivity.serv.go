@@ -76,8 +76,8 @@ func (p *BasActivityServ) ActivityWatcher() {
 // Record will save the activity
 // TODO: Record is deprecated we should go with channels
 func (p *BasActivityServ) Record(c *gin.Context, ev types.Event, data ...interface{}) {
-	var userID uint
-	var username string
+	var userID, actorID uint
+	var username, actorUsername string
 
 	recordType := p.FindRecordType(data...)
 	before, after := p.FillBeforeAfter(recordType, data...)
@@ -99,6 +99,10 @@ func (p *BasActivityServ) Record(c *gin.Context, ev types.Event, data ...interfa
 	if usernameTmp, ok := c.Get("USERNAME"); ok {
 		username = usernameTmp.(string)
 	}
+	if actorIDtmp, ok := c.Get("ACTOR_ID"); ok {
+		actorID = actorIDtmp.(uint)
+		actorUsername = c.GetString("ACTOR_USERNAME")
+	}
 
 	activity := basmodel.Activity{
 		Event:    ev.String(),
@@ -108,6 +112,9 @@ func (p *BasActivityServ) Record(c *gin.Context, ev types.Event, data ...interfa
 		URI:      c.Request.RequestURI,
 		Before:   string(before),
 		After:    string(after),
+
+		ActorID:       actorID,
+		ActorUsername: actorUsername,
 	}
 
 	_, err := p.Repo.Create(activity)
@@ -160,11 +167,18 @@ func (p *BasActivityServ) IsRecordSetInEnvironment(recordType RecordType) bool {
 	return false
 }
 
-// List of activities, it support pagination and search and return back count
+// List of activities, it support pagination and search and return back count. In the aggregate
+// mode the grouped rows are returned as the list
 func (p *BasActivityServ) List(params param.Param) (data map[string]interface{}, err error) {
 
 	data = make(map[string]interface{})
 
+	if params.Aggregated() {
+		data["list"], err = p.Repo.Aggregate(params)
+		glog.CheckError(err, "activities aggregate")
+		return
+	}
+
 	data["list"], err = p.Repo.List(params)
 	glog.CheckError(err, "activities list")
 	if err != nil {
@@ -0,0 +1,211 @@
+package service
+
+import (
+	"fmt"
+	"omono/domain/base/basmodel"
+	"omono/domain/base/basrepo"
+	"omono/domain/base/basterm"
+	"omono/domain/base/enum/userstatus"
+	"omono/internal/core"
+	"omono/internal/core/coract"
+	"omono/internal/core/corerr"
+	"omono/internal/core/corperm"
+	"omono/internal/param"
+	"omono/pkg/glog"
+	"omono/pkg/helper/random"
+	"time"
+
+	"github.com/syronz/limberr"
+)
+
+// APIKeyPrefix is added to the beginning of generated keys for recognizing them in the logs
+const APIKeyPrefix = "omk_"
+
+// BasAPIKeyServ for injecting api key basrepo
+type BasAPIKeyServ struct {
+	Repo   basrepo.APIKeyRepo
+	Engine *core.Engine
+}
+
+// ProvideBasAPIKeyService for api key is used in wire
+func ProvideBasAPIKeyService(p basrepo.APIKeyRepo) BasAPIKeyServ {
+	return BasAPIKeyServ{Repo: p, Engine: p.Engine}
+}
+
+// FindByID for getting api key by it's id, users can just see their own keys except super admin
+func (p *BasAPIKeyServ) FindByID(params param.Param, id uint) (apiKey basmodel.APIKey, err error) {
+	if apiKey, err = p.Repo.FindByID(id); err != nil {
+		err = corerr.Tick(err, "E1054070", "can't fetch the api key", id)
+		return
+	}
+
+	if apiKey.UserID == params.UserID {
+		return
+	}
+
+	var super bool
+	if super, err = p.superAdmin(params.UserID); err != nil || super {
+		return
+	}
+
+	err = limberr.New("api key belongs to another user", "E1086575").
+		Message(corerr.YouDontHavePermissionToThisV, basterm.APIKey).
+		Custom(corerr.ForbiddenErr).Build()
+
+	return
+}
+
+// List of api keys, it support pagination and search and return back count
+func (p *BasAPIKeyServ) List(params param.Param) (apiKeys []basmodel.APIKey,
+	count int64, err error) {
+
+	var super bool
+	if super, err = p.superAdmin(params.UserID); err != nil {
+		return
+	}
+
+	if !super {
+		params.PreCondition = fmt.Sprintf("bas_api_keys.user_id = %v", params.UserID)
+	}
+
+	if apiKeys, err = p.Repo.List(params); err != nil {
+		glog.CheckError(err, "error in api keys list")
+		return
+	}
+
+	if count, err = p.Repo.Count(params); err != nil {
+		glog.CheckError(err, "error in api keys count")
+	}
+
+	return
+}
+
+// Aggregate returns the grouped rows of the api keys, it is the aggregate mode of the List
+func (p *BasAPIKeyServ) Aggregate(params param.Param) (rows []map[string]interface{}, err error) {
+	var super bool
+	if super, err = p.superAdmin(params.UserID); err != nil {
+		return
+	}
+
+	if !super {
+		params.PreCondition = fmt.Sprintf("bas_api_keys.user_id = %v", params.UserID)
+	}
+
+	if rows, err = p.Repo.Aggregate(params); err != nil {
+		glog.CheckError(err, "error in api keys aggregate")
+	}
+
+	return
+}
+
+// Create an api key for the current user, the role of the key can't have a resource which the
+// user doesn't have. The raw key is just returned once
+func (p *BasAPIKeyServ) Create(params param.Param,
+	apiKey basmodel.APIKey) (createdAPIKey basmodel.APIKey, err error) {
+	if err = apiKey.Validate(coract.Create); err != nil {
+		err = corerr.TickValidate(err, "E1074924", corerr.ValidationFailed, apiKey)
+		return
+	}
+
+	accessServ := ProvideBasAccessService(basrepo.ProvideAccessRepo(p.Engine))
+	if err = accessServ.CheckRoleSubset(params.UserID, apiKey.RoleID); err != nil {
+		return
+	}
+
+	var secret string
+	if secret, err = random.Token(24); err != nil {
+		err = corerr.TickCustom(err, corerr.InternalServerErr, "E1077117", "api key not generated")
+		return
+	}
+	secret = APIKeyPrefix + secret
+
+	apiKey.UserID = params.UserID
+	apiKey.CompanyID = params.CompanyID
+	apiKey.Prefix = secret[:12]
+	apiKey.Hash = hashToken(secret)
+	apiKey.LastUsedAt = nil
+	apiKey.RevokedAt = nil
+
+	if createdAPIKey, err = p.Repo.Create(apiKey); err != nil {
+		err = corerr.Tick(err, "E1046247", "api key not saved", apiKey.Name)
+		return
+	}
+
+	createdAPIKey.Secret = secret
+
+	return
+}
+
+// Revoke the api key, it can't be used anymore
+func (p *BasAPIKeyServ) Revoke(params param.Param, id uint) (apiKey basmodel.APIKey, err error) {
+	if apiKey, err = p.FindByID(params, id); err != nil {
+		return
+	}
+
+	if err = p.Repo.Revoke(id); err != nil {
+		err = corerr.Tick(err, "E1081738", "api key not revoked", id)
+		return
+	}
+
+	return
+}
+
+// Authenticate is used in AuthGuard for checking the X-API-Key header
+func (p *BasAPIKeyServ) Authenticate(secret, ip string) (apiKey basmodel.APIKey, err error) {
+	if apiKey, err = p.Repo.FindByHash(hashToken(secret)); err != nil {
+		if limberr.GetCustom(err) == corerr.NotFoundErr {
+			err = apiKeyErr("E1058903", "api key not found")
+			return
+		}
+		err = corerr.Tick(err, "E1044579", "can't fetch the api key")
+		return
+	}
+
+	now := time.Now()
+
+	switch {
+	case apiKey.RevokedAt != nil:
+		err = apiKeyErr("E1082052", "api key is revoked")
+		return
+	case apiKey.ExpiresAt != nil && apiKey.ExpiresAt.Before(now):
+		err = apiKeyErr("E1077764", "api key is expired")
+		return
+	case !apiKey.IsIPAllowed(ip):
+		err = apiKeyErr("E1027468", "ip is not in the allowlist of api key")
+		return
+	case apiKey.UserStatus != userstatus.Active:
+		err = apiKeyErr("E1065112", "owner of the api key is not active")
+		return
+	}
+
+	// the resources of the parents are inherited by the role of the key
+	accessRepo := basrepo.ProvideAccessRepo(p.Engine)
+	var rules []corperm.Rules
+	if rules, err = accessRepo.GetRoleResources(apiKey.RoleID); err != nil {
+		err = corerr.Tick(err, "E1046844", "can't fetch the resources of the api key", apiKey.ID)
+		return
+	}
+	for _, v := range rules {
+		apiKey.Resources = append(apiKey.Resources, v...)
+	}
+
+	// last_used_at is updated once per minute for reducing the writes
+	if apiKey.LastUsedAt == nil || apiKey.LastUsedAt.Add(time.Minute).Before(now) {
+		glog.CheckError(p.Repo.TouchLastUsed(apiKey.ID, now), "update last used of api key",
+			apiKey.ID)
+	}
+
+	return
+}
+
+// superAdmin can see the api keys of the other users
+func (p *BasAPIKeyServ) superAdmin(userID uint) (bool, error) {
+	accessServ := ProvideBasAccessService(basrepo.ProvideAccessRepo(p.Engine))
+	return accessServ.HasSuperAccess(userID)
+}
+
+func apiKeyErr(code, msg string) error {
+	return limberr.New(msg, code).
+		Message(basterm.APIKeyIsNotValid).
+		Custom(corerr.UnauthorizedErr).Build()
+}
@@ -5,20 +5,25 @@ import (
 	"omono/domain/base/basmodel"
 	"omono/domain/base/basrepo"
 	"omono/domain/base/basterm"
+	"omono/domain/base/enum/userstatus"
+	"omono/domain/segment/segrepo"
 	"omono/internal/consts"
 	"omono/internal/core"
 	"omono/internal/core/coract"
 	"omono/internal/core/corerr"
+	"omono/internal/core/corterm"
 	"omono/internal/param"
 	"omono/internal/types"
 	"omono/pkg/glog"
 	"omono/pkg/helper/password"
+	"omono/pkg/helper/random"
 	"time"
 
 	"github.com/syronz/dict"
 	"github.com/syronz/limberr"
 
 	"github.com/dgrijalva/jwt-go"
+	"gorm.io/gorm"
 )
 
 // BasAuthServ defining auth service
@@ -41,106 +46,553 @@ func (p *BasAuthServ) Login(auth basmodel.Auth, params param.Param) (user basmod
 		return
 	}
 
-	jwtKey := p.Engine.Envs.ToByte(base.JWTSecretKey)
+	attemptServ := ProvideBasLoginAttemptService(basrepo.ProvideLoginAttemptRepo(p.Engine))
+
+	var delay time.Duration
+	if delay, err = attemptServ.Check(auth.Username, auth.IP); err != nil {
+		return
+	}
+
+	time.Sleep(delay)
 
 	userServ := ProvideBasUserService(basrepo.ProvideUserRepo(p.Engine))
 	if user, err = userServ.FindByUsername(auth.Username); err != nil {
 		err = limberr.Take(err).Custom(corerr.UnauthorizedErr).
 			Message(basterm.UsernameOrPasswordIsWrong).Build()
+		err = p.countFailure(attemptServ, auth, err)
 		return
 	}
 
 	if password.Verify(auth.Password, user.Password,
 		p.Engine.Envs[base.PasswordSalt]) {
 
-		expirationTime := time.Now().
-			Add(p.Engine.Envs.ToDuration(base.JWTExpiration) * time.Second)
-		claims := &types.JWTClaims{
-			Username: auth.Username,
-			ID:       user.ID,
-			Lang:     user.Lang,
-			StandardClaims: jwt.StandardClaims{
-				ExpiresAt: expirationTime.Unix(),
-			},
+		if err = pendingErr(user); err != nil {
+			return
 		}
 
-		token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-		var extra struct {
-			Token string `json:"token"`
+		passServ := ProvideBasPasswordService(basrepo.ProvidePasswordRepo(p.Engine))
+		if passServ.IsExpired(user) {
+			err = limberr.New("password is expired", "E1075642").
+				Message(basterm.PasswordIsExpiredResetItViaForgotPassword).
+				Custom(corerr.ForbiddenErr).Build()
+			return
 		}
-		if extra.Token, err = token.SignedString(jwtKey); err != nil {
-			err = limberr.Take(err).Message(corerr.InternalServerError).Build()
-			err = corerr.TickCustom(err, corerr.InternalServerErr, "E1042238",
-				"error in generating token")
+
+		mfaServ := ProvideBasMFAService(basrepo.ProvideMFARepo(p.Engine))
+
+		var enabled bool
+		if enabled, err = mfaServ.IsEnabled(user.ID); err != nil {
+			return
+		}
+
+		if enabled || mfaServ.IsRequired(user.AllRoleIDs()...) {
+			var challenge basmodel.MFAChallenge
+			if challenge, err = p.mfaChallenge(user); err != nil {
+				return
+			}
+
+			challenge.EnrollRequired = !enabled
+			user.Extra = challenge
+			user.Password = ""
+			return
+		}
+
+		var authToken basmodel.AuthToken
+		if authToken, err = p.startSession(user, auth); err != nil {
 			return
 		}
 
-		user.Extra = extra
+		// the failures are cleared after issuing the session, in the mfa mode it is the LoginMFA
+		glog.CheckError(attemptServ.Succeed(auth.Username), "reset login attempts", auth.Username)
+
+		user.Extra = authToken
 		user.Password = ""
-		BasAccessDeleteFromCache(user.ID)
 
 	} else {
 		err = limberr.New("wrong password").Message(basterm.UsernameOrPasswordIsWrong).Build()
 		err = corerr.TickCustom(err, corerr.UnauthorizedErr, "E1043108", "wrong password")
+		err = p.countFailure(attemptServ, auth, err)
 	}
 
 	return
 }
 
-// Profile return user's information
-func (p *BasAuthServ) Profile(params param.Param) (user basmodel.User, err error) {
+// countFailure save the failed attempt, in case of lockout the lock error replaces the original one
+func (p *BasAuthServ) countFailure(attemptServ BasLoginAttemptServ, auth basmodel.Auth,
+	err error) error {
+	if errLock := attemptServ.Fail(auth.Username, auth.IP); errLock != nil {
+		return errLock
+	}
+
+	return err
+}
+
+// LoginMFA is the second step of login, the mfa token and the two-factor code are exchanged
+// with the access and refresh tokens
+func (p *BasAuthServ) LoginMFA(auth basmodel.Auth) (user basmodel.User, err error) {
+	if err = auth.Validate(coract.Verify); err != nil {
+		err = limberr.Take(err, "E1091497").
+			Custom(corerr.ValidationFailedErr).Build()
+		return
+	}
+
+	claims := &types.JWTClaims{}
+
+	_, err = jwt.ParseWithClaims(auth.MFAToken, claims, p.Engine.JWTKeys.Keyfunc)
+	if err != nil || !claims.MFAPending {
+		err = limberr.New("mfa token is not valid", "E1036949").
+			Message(corerr.TokenIsNotValid).
+			Custom(corerr.UnauthorizedErr).Build()
+		return
+	}
+
+	attemptServ := ProvideBasLoginAttemptService(basrepo.ProvideLoginAttemptRepo(p.Engine))
+
+	var delay time.Duration
+	if delay, err = attemptServ.Check(claims.Username, auth.IP); err != nil {
+		return
+	}
+
+	time.Sleep(delay)
+
+	mfaServ := ProvideBasMFAService(basrepo.ProvideMFARepo(p.Engine))
+	if err = mfaServ.Verify(claims.ID, auth.Code); err != nil {
+		if limberr.GetCustom(err) == corerr.UnauthorizedErr {
+			auth.Username = claims.Username
+			err = p.countFailure(attemptServ, auth, err)
+		}
+		return
+	}
+
 	userServ := ProvideBasUserService(basrepo.ProvideUserRepo(p.Engine))
+	if user, err = userServ.FindByID(claims.ID); err != nil {
+		err = corerr.TickCustom(err, corerr.UnauthorizedErr, "E1067062",
+			"user of mfa token not found", claims.ID)
+		return
+	}
 
-	if user, err = userServ.FindByID(params.UserID); err != nil {
+	var authToken basmodel.AuthToken
+	if authToken, err = p.startSession(user, auth); err != nil {
 		return
 	}
 
+	glog.CheckError(attemptServ.Succeed(claims.Username), "reset login attempts", claims.Username)
+
+	user.Extra = authToken
+	user.Password = ""
+
 	return
 }
 
-// Logout erase resources from the cache
-func (p *BasAuthServ) Logout(params param.Param) {
-	BasAccessResetCache(params.UserID)
+// mfaChallenge generate a short-lived token which is just accepted for the two-factor steps
+func (p *BasAuthServ) mfaChallenge(user basmodel.User) (challenge basmodel.MFAChallenge, err error) {
+	expirationTime := time.Now().Add(consts.MFATokenDuration * time.Second)
+	claims := &types.JWTClaims{
+		Username:   user.Username,
+		ID:         user.ID,
+		Lang:       user.Lang,
+		MFAPending: true,
+		StandardClaims: jwt.StandardClaims{
+			ExpiresAt: expirationTime.Unix(),
+		},
+	}
+
+	if challenge.MFAToken, err = p.Engine.JWTKeys.Sign(claims); err != nil {
+		err = corerr.TickCustom(err, corerr.InternalServerErr, "E1064730",
+			"mfa token not generated")
+		return
+	}
+
+	challenge.ExpiresAt = expirationTime.Unix()
+
+	return
 }
 
-// TemporaryToken generate instant token for downloading excels and etc
-func (p *BasAuthServ) TemporaryToken(params param.Param) (tmpKey string, err error) {
-	jwtKey := p.Engine.Envs.ToByte(base.JWTSecretKey)
+// Refresh rotate the refresh token and issue a new access token
+func (p *BasAuthServ) Refresh(auth basmodel.Auth) (user basmodel.User, err error) {
+	if err = auth.Validate(coract.Refresh); err != nil {
+		err = limberr.Take(err, "E1012312").
+			Custom(corerr.ValidationFailedErr).Build()
+		return
+	}
 
-	expirationTime := time.Now().Add(consts.TemporaryTokenDuration * time.Second)
+	tokenServ := ProvideBasTokenService(basrepo.ProvideTokenRepo(p.Engine))
+	userServ := ProvideBasUserService(basrepo.ProvideUserRepo(p.Engine))
+
+	db := p.Engine.DB.Begin()
+
+	var refresh basmodel.RefreshToken
+	if refresh, err = tokenServ.TxConsumeRefresh(db, auth.RefreshToken); err != nil {
+		db.Rollback()
+		return
+	}
+
+	if user, err = userServ.FindByID(refresh.UserID); err != nil {
+		err = corerr.TickCustom(err, corerr.UnauthorizedErr, "E1066472",
+			"user of refresh token not found", refresh.UserID)
+		db.Rollback()
+		return
+	}
+
+	// the user might be disabled after issuing the refresh token
+	if err = pendingErr(user); err != nil {
+		db.Rollback()
+		return
+	}
+
+	if user.Status != userstatus.Active {
+		err = limberr.New("user is not active", "E1052728").
+			Message(basterm.YourAccountIsNotActive).
+			Custom(corerr.UnauthorizedErr).Build()
+		db.Rollback()
+		return
+	}
+
+	passServ := ProvideBasPasswordService(basrepo.ProvidePasswordRepo(p.Engine))
+	if passServ.IsExpired(user) {
+		err = limberr.New("password is expired", "E1037279").
+			Message(basterm.PasswordIsExpiredResetItViaForgotPassword).
+			Custom(corerr.ForbiddenErr).Build()
+		db.Rollback()
+		return
+	}
+
+	if refresh.SessionID != 0 {
+		sessionServ := ProvideBasSessionService(basrepo.ProvideSessionRepo(p.Engine))
+		if err = sessionServ.TxExtend(db, refresh.SessionID); err != nil {
+			db.Rollback()
+			return
+		}
+	}
+
+	// the membership or the super access might be changed after issuing the refresh token
+	userCompanyServ := ProvideBasUserCompanyService(basrepo.ProvideUserCompanyRepo(p.Engine))
+	var tenant basmodel.Tenant
+	if tenant, err = userCompanyServ.Resolve(user.ID, basmodel.Tenant{
+		CompanyID:   refresh.CompanyID,
+		CrossTenant: refresh.CrossTenant,
+	}); err != nil {
+		db.Rollback()
+		return
+	}
+
+	var authToken basmodel.AuthToken
+	if authToken, err = p.issueTokens(db, user, refresh.SessionID, tenant); err != nil {
+		db.Rollback()
+		return
+	}
+
+	db.Commit()
+
+	user.Extra = authToken
+	user.Password = ""
+
+	return
+}
+
+// startSession create the session of the login and issue its tokens
+func (p *BasAuthServ) startSession(user basmodel.User,
+	auth basmodel.Auth) (authToken basmodel.AuthToken, err error) {
+	sessionServ := ProvideBasSessionService(basrepo.ProvideSessionRepo(p.Engine))
+	userCompanyServ := ProvideBasUserCompanyService(basrepo.ProvideUserCompanyRepo(p.Engine))
+
+	var tenant basmodel.Tenant
+	if tenant, err = userCompanyServ.Resolve(user.ID, basmodel.Tenant{}); err != nil {
+		return
+	}
+
+	db := p.Engine.DB.Begin()
+
+	var session basmodel.Session
+	if session, err = sessionServ.TxCreate(db, user.ID, auth); err != nil {
+		db.Rollback()
+		return
+	}
+
+	if authToken, err = p.issueTokens(db, user, session.ID, tenant); err != nil {
+		db.Rollback()
+		return
+	}
+
+	db.Commit()
+
+	return
+}
+
+// issueTokens generate a short-lived access token and a refresh token bound to it, both of
+// them carry the tenant
+func (p *BasAuthServ) issueTokens(db *gorm.DB, user basmodel.User, sessionID uint,
+	tenant basmodel.Tenant) (authToken basmodel.AuthToken, err error) {
+	tokenServ := ProvideBasTokenService(basrepo.ProvideTokenRepo(p.Engine))
+
+	var jti string
+	if jti, err = random.Token(16); err != nil {
+		err = limberr.Take(err).Message(corerr.InternalServerError).Build()
+		err = corerr.TickCustom(err, corerr.InternalServerErr, "E1030636",
+			"error in generating jti")
+		return
+	}
+
+	expirationTime := time.Now().
+		Add(p.Engine.Envs.ToDuration(base.JWTExpiration) * time.Second)
 	claims := &types.JWTClaims{
-		ID:   params.UserID,
-		Lang: params.Lang,
+		Username:    user.Username,
+		ID:          user.ID,
+		Lang:        user.Lang,
+		SessionID:   sessionID,
+		CompanyID:   tenant.CompanyID,
+		CrossTenant: tenant.CrossTenant,
 		StandardClaims: jwt.StandardClaims{
 			ExpiresAt: expirationTime.Unix(),
+			Id:        jti,
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	if tmpKey, err = token.SignedString(jwtKey); err != nil {
-		err = corerr.Tick(err, "E1044682", "temporary token not generated")
+	if authToken.Token, err = p.Engine.JWTKeys.Sign(claims); err != nil {
+		err = limberr.Take(err).Message(corerr.InternalServerError).Build()
+		err = corerr.TickCustom(err, corerr.InternalServerErr, "E1042238",
+			"error in generating token")
+		return
+	}
+
+	if authToken.RefreshToken, err = tokenServ.TxCreateRefresh(db, user.ID, jti, sessionID,
+		tenant); err != nil {
 		return
 	}
 
+	authToken.ExpiresAt = expirationTime.Unix()
+
 	return
 }
 
-// TemporaryTokenHour generate instant token for downloading excels and etc
-func (p *BasAuthServ) TemporaryTokenHour(hour int, lang dict.Lang) (tmpKey string, err error) {
-	jwtKey := p.Engine.Envs.ToByte(base.JWTSecretKey)
+// Impersonate issue a short-lived access token for the target user which carries the real
+// actor as well. No refresh token is issued and the token belongs to the actor's session, so
+// terminating the session ends the impersonation too
+func (p *BasAuthServ) Impersonate(params param.Param, targetID uint) (user basmodel.User,
+	err error) {
+	if params.ActorID != 0 {
+		err = limberr.New("nested impersonation", "E1075487").
+			Message(basterm.ImpersonatedUserCantImpersonate).
+			Custom(corerr.ForbiddenErr).Build()
+		return
+	}
 
-	expirationTime := time.Now().Add(time.Duration(hour) * time.Hour)
+	if targetID == params.UserID {
+		err = limberr.New("impersonate himself", "E1021727").
+			Message(basterm.YouCantImpersonateYourself).
+			Custom(corerr.ForbiddenErr).Build()
+		return
+	}
+
+	userServ := ProvideBasUserService(basrepo.ProvideUserRepo(p.Engine))
+
+	var actor basmodel.User
+	if actor, err = userServ.FindByID(params.UserID); err != nil {
+		return
+	}
+
+	if user, err = userServ.FindInCompany(params, targetID); err != nil {
+		return
+	}
+
+	accessServ := ProvideBasAccessService(basrepo.ProvideAccessRepo(p.Engine))
+	if err = accessServ.CheckRoleSubset(actor.ID, user.AllRoleIDs()...); err != nil {
+		return
+	}
+
+	// the impersonation stays in the company of the actor if the target is a member of it
+	userCompanyServ := ProvideBasUserCompanyService(basrepo.ProvideUserCompanyRepo(p.Engine))
+	var tenant basmodel.Tenant
+	if tenant, err = userCompanyServ.Resolve(user.ID, basmodel.Tenant{
+		CompanyID: params.CompanyID,
+	}); err != nil {
+		return
+	}
+
+	var jti string
+	if jti, err = random.Token(16); err != nil {
+		err = corerr.TickCustom(err, corerr.InternalServerErr, "E1076355",
+			"error in generating jti")
+		return
+	}
+
+	expirationTime := time.Now().
+		Add(p.Engine.Envs.ToDuration(base.JWTExpiration) * time.Second)
 	claims := &types.JWTClaims{
-		ID:   consts.UserResultViewerID,
-		Lang: lang,
+		Username:      user.Username,
+		ID:            user.ID,
+		Lang:          user.Lang,
+		SessionID:     params.SessionID,
+		ActorID:       actor.ID,
+		ActorUsername: actor.Username,
+		CompanyID:     tenant.CompanyID,
+		CrossTenant:   tenant.CrossTenant,
 		StandardClaims: jwt.StandardClaims{
 			ExpiresAt: expirationTime.Unix(),
+			Id:        jti,
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	if tmpKey, err = token.SignedString(jwtKey); err != nil {
-		err = corerr.Tick(err, "E1077735", "temporary token hour not generated")
+	var authToken basmodel.AuthToken
+	if authToken.Token, err = p.Engine.JWTKeys.Sign(claims); err != nil {
+		err = corerr.TickCustom(err, corerr.InternalServerErr, "E1037578",
+			"impersonation token not generated", targetID)
+		return
+	}
+
+	authToken.ExpiresAt = expirationTime.Unix()
+	user.Extra = authToken
+	user.Password = ""
+
+	return
+}
+
+// SwitchCompany issue new tokens in the same session for the company and revoke the current
+// ones, the zero company activates the cross tenant mode which is just for the super admin
+func (p *BasAuthServ) SwitchCompany(params param.Param, companyID uint,
+	jti string) (user basmodel.User, err error) {
+	if params.ActorID != 0 {
+		err = limberr.New("switch company in impersonation", "E1047522").
+			Message(basterm.ImpersonatedUserCantSwitchCompany).
+			Custom(corerr.ForbiddenErr).Build()
+		return
+	}
+
+	accessServ := ProvideBasAccessService(basrepo.ProvideAccessRepo(p.Engine))
+
+	var super bool
+	if super, err = accessServ.HasSuperAccess(params.UserID); err != nil {
+		return
+	}
+
+	tenant := basmodel.Tenant{CompanyID: companyID, CrossTenant: companyID == 0}
+
+	if tenant.CrossTenant && !super {
+		err = limberr.New("cross tenant for regular user", "E1080179").
+			Message(basterm.CrossTenantModeIsOnlyForSuperAdmin).
+			Custom(corerr.ForbiddenErr).Build()
+		return
+	}
+
+	if !tenant.CrossTenant {
+		companyServ := ProvideSegCompanyService(segrepo.ProvideCompanyRepo(p.Engine))
+		if _, err = companyServ.FindByID(param.New(), companyID); err != nil {
+			return
+		}
+
+		userCompanyServ := ProvideBasUserCompanyService(basrepo.ProvideUserCompanyRepo(p.Engine))
+
+		var member bool
+		if member, err = userCompanyServ.IsMember(params.UserID, companyID); err != nil {
+			return
+		}
+
+		if !member && !super {
+			err = limberr.New("switch to other company", "E1027448").
+				Message(basterm.YouAreNotAMemberOfThisCompany).
+				Custom(corerr.ForbiddenErr).Build()
+			return
+		}
+	}
+
+	userServ := ProvideBasUserService(basrepo.ProvideUserRepo(p.Engine))
+	if user, err = userServ.FindByID(params.UserID); err != nil {
+		return
+	}
+
+	db := p.Engine.DB.Begin()
+
+	var authToken basmodel.AuthToken
+	if authToken, err = p.issueTokens(db, user, params.SessionID, tenant); err != nil {
+		db.Rollback()
+		return
+	}
+
+	db.Commit()
+
+	tokenServ := ProvideBasTokenService(basrepo.ProvideTokenRepo(p.Engine))
+	if err = tokenServ.Revoke(params.UserID, jti); err != nil {
+		return
+	}
+
+	user.Extra = authToken
+	user.Password = ""
+
+	return
+}
+
+// Profile return user's information beside their active and upcoming grants
+func (p *BasAuthServ) Profile(params param.Param) (user basmodel.User, err error) {
+	userServ := ProvideBasUserService(basrepo.ProvideUserRepo(p.Engine))
+
+	if user, err = userServ.FindByID(params.UserID); err != nil {
+		return
+	}
+
+	grantServ := ProvideBasGrantService(basrepo.ProvideGrantRepo(p.Engine))
+	if user.Grants, err = grantServ.ListByUser(user.ID); err != nil {
+		return
+	}
+
+	return
+}
+
+// Logout revoke the access token and its refresh token and terminate the session, then erase
+// resources from the cache
+func (p *BasAuthServ) Logout(params param.Param, jti string) (err error) {
+	tokenServ := ProvideBasTokenService(basrepo.ProvideTokenRepo(p.Engine))
+	if err = tokenServ.Revoke(params.UserID, jti); err != nil {
+		err = corerr.Tick(err, "E1039692", "logout failed", params.UserID)
+		return
+	}
+
+	// the session of the impersonation token belongs to the actor and it should stay alive
+	if params.SessionID != 0 && params.ActorID == 0 {
+		sessionServ := ProvideBasSessionService(basrepo.ProvideSessionRepo(p.Engine))
+		if err = sessionServ.terminate(params.SessionID); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// TemporaryToken generate instant token for downloading excels and etc, it has its own jti so
+// it can be revoked like the access tokens and terminating the session ends it too
+func (p *BasAuthServ) TemporaryToken(params param.Param) (tmpKey string, err error) {
+	var jti string
+	if jti, err = random.Token(16); err != nil {
+		err = corerr.TickCustom(err, corerr.InternalServerErr, "E1091500",
+			"error in generating jti")
+		return
+	}
+
+	// the temporary token of the impersonation keeps the real actor for the activities
+	var actor basmodel.User
+	if params.ActorID != 0 {
+		userServ := ProvideBasUserService(basrepo.ProvideUserRepo(p.Engine))
+		if actor, err = userServ.FindByID(params.ActorID); err != nil {
+			return
+		}
+	}
+
+	expirationTime := time.Now().Add(consts.TemporaryTokenDuration * time.Second)
+	claims := &types.JWTClaims{
+		ID:            params.UserID,
+		Lang:          params.Lang,
+		SessionID:     params.SessionID,
+		ActorID:       actor.ID,
+		ActorUsername: actor.Username,
+		CompanyID:     params.CompanyID,
+		CrossTenant:   !params.CompanyScoped,
+		StandardClaims: jwt.StandardClaims{
+			ExpiresAt: expirationTime.Unix(),
+			Id:        jti,
+		},
+	}
+
+	if tmpKey, err = p.Engine.JWTKeys.Sign(claims); err != nil {
+		err = corerr.Tick(err, "E1044682", "temporary token not generated")
 		return
 	}
 
@@ -160,7 +612,37 @@ func (p *BasAuthServ) Register(user basmodel.User) (createdUser basmodel.User, e
 		return
 	}
 
-	createdUser, err = userServ.Create(user)
+	regServ := ProvideBasRegistrationService(basrepo.ProvideRegistrationRepo(p.Engine))
+	user.Status = regServ.InitialStatus()
+
+	if user.Status == userstatus.PendingVerification && user.Email == "" {
+		err = limberr.AddInvalidParam(nil, "email",
+			corerr.VisRequired, dict.R(corterm.Email))
+		err = corerr.TickValidate(err, "E1090776", "email is required for verification", user)
+		return
+	}
+
+	if createdUser, err = userServ.Create(param.New(), user); err != nil {
+		return
+	}
+
+	err = regServ.Start(createdUser)
+
+	return
+}
+
+// pendingErr refuse the login of the registered users which are not verified or approved yet
+func pendingErr(user basmodel.User) (err error) {
+	switch user.Status {
+	case userstatus.PendingVerification:
+		err = limberr.New("email is not verified", "E1093018").
+			Message(basterm.VerifyYourEmailBeforeLogin).
+			Custom(corerr.ForbiddenErr).Build()
+	case userstatus.PendingApproval:
+		err = limberr.New("user is not approved", "E1078687").
+			Message(basterm.YourAccountIsWaitingForApproval).
+			Custom(corerr.ForbiddenErr).Build()
+	}
 
 	return
 }
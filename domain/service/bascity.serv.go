@@ -66,6 +66,15 @@ func (p *BasCityServ) List(params param.Param) (cities []basmodel.City,
 	return
 }
 
+// Aggregate returns the grouped rows of the cities, it is the aggregate mode of the List
+func (p *BasCityServ) Aggregate(params param.Param) (rows []map[string]interface{}, err error) {
+	if rows, err = p.Repo.Aggregate(params); err != nil {
+		glog.CheckError(err, "error in cities aggregate")
+	}
+
+	return
+}
+
 // Create a city
 func (p *BasCityServ) Create(city basmodel.City) (createdCity basmodel.City, err error) {
 	if err = city.Validate(coract.Save); err != nil {
@@ -94,6 +103,9 @@ func (p *BasCityServ) Save(city basmodel.City) (savedCity, cityBefore basmodel.C
 	}
 
 	city.CreatedAt = cityBefore.CreatedAt
+	if city.UpdatedAt.IsZero() {
+		city.UpdatedAt = cityBefore.UpdatedAt
+	}
 
 	if savedCity, err = p.Repo.Save(city); err != nil {
 		err = corerr.Tick(err, "E1044237", "city not saved")
@@ -118,11 +130,22 @@ func (p *BasCityServ) Delete(id uint) (city basmodel.City, err error) {
 	return
 }
 
+// Restore the soft deleted city
+func (p *BasCityServ) Restore(id uint) (city basmodel.City, err error) {
+	if err = p.Repo.Restore(id); err != nil {
+		err = corerr.Tick(err, "E1049833", "city not restored", id)
+		return
+	}
+
+	return p.FindByID(id)
+}
+
 // Excel is used for export excel file
 func (p *BasCityServ) Excel(params param.Param) (cities []basmodel.City, err error) {
 	params.Limit = p.Engine.Envs.ToInt(core.ExcelMaxRows)
 	params.Offset = 0
 	params.Order = fmt.Sprintf("%v.id ASC", basmodel.CityTable)
+	params.After, params.Before = "", ""
 
 	if cities, err = p.Repo.List(params); err != nil {
 		err = corerr.Tick(err, "E1019610", "cant generate the excel list for cities")
@@ -0,0 +1,190 @@
+package service
+
+import (
+	"encoding/json"
+	"omono/domain/base"
+	"omono/domain/base/basmodel"
+	"omono/domain/base/basrepo"
+	"omono/domain/base/basterm"
+	"omono/internal/core"
+	"omono/internal/core/coract"
+	"omono/internal/core/corerr"
+	"omono/internal/core/corterm"
+	"omono/internal/param"
+	"omono/pkg/glog"
+	"time"
+
+	"github.com/syronz/limberr"
+)
+
+// BasGrantServ for injecting grant basrepo, it handles the temporary grants of the resources
+type BasGrantServ struct {
+	Repo   basrepo.GrantRepo
+	Engine *core.Engine
+}
+
+// ProvideBasGrantService for grant is used in wire
+func ProvideBasGrantService(p basrepo.GrantRepo) BasGrantServ {
+	return BasGrantServ{Repo: p, Engine: p.Engine}
+}
+
+// FindByID for getting grant by it's id, in the scoped mode the user of the grant should be a
+// member of the active company
+func (p *BasGrantServ) FindByID(params param.Param, id uint) (grant basmodel.Grant, err error) {
+	if grant, err = p.Repo.FindByID(id); err != nil {
+		err = corerr.Tick(err, "E1065529", "can't fetch the grant", id)
+		return
+	}
+
+	userServ := ProvideBasUserService(basrepo.ProvideUserRepo(p.Engine))
+	if _, err = userServ.FindInCompany(params, grant.UserID); err != nil {
+		err = corerr.RecordNotFoundHelper(err, "E1081807", corterm.ID, id, basterm.Grants)
+		grant = basmodel.Grant{}
+		return
+	}
+
+	return
+}
+
+// List of grants, it support pagination and search and return back count
+func (p *BasGrantServ) List(params param.Param) (grants []basmodel.Grant,
+	count int64, err error) {
+
+	if grants, err = p.Repo.List(params); err != nil {
+		glog.CheckError(err, "error in grants list")
+		return
+	}
+
+	if count, err = p.Repo.Count(params); err != nil {
+		glog.CheckError(err, "error in grants count")
+	}
+
+	return
+}
+
+// Aggregate returns the grouped rows of the grants, it is the aggregate mode of the List
+func (p *BasGrantServ) Aggregate(params param.Param) (rows []map[string]interface{}, err error) {
+	if rows, err = p.Repo.Aggregate(params); err != nil {
+		glog.CheckError(err, "error in grants aggregate")
+	}
+
+	return
+}
+
+// ListByUser returns the active and upcoming grants of the user
+func (p *BasGrantServ) ListByUser(userID uint) (grants []basmodel.Grant, err error) {
+	if grants, err = p.Repo.ListByUser(userID, time.Now()); err != nil {
+		err = corerr.Tick(err, "E1030747", "can't fetch the grants of the user", userID)
+		return
+	}
+
+	return
+}
+
+// ListOfUser is used by the admins, in the scoped mode the user should be a member of the
+// active company
+func (p *BasGrantServ) ListOfUser(params param.Param,
+	userID uint) (grants []basmodel.Grant, err error) {
+	userServ := ProvideBasUserService(basrepo.ProvideUserRepo(p.Engine))
+	if _, err = userServ.FindInCompany(params, userID); err != nil {
+		return
+	}
+
+	return p.ListByUser(userID)
+}
+
+// Create a grant, the grantor is the current user and they can't grant a resource which they
+// don't have. Granting to themselves is not allowed
+func (p *BasGrantServ) Create(params param.Param,
+	grant basmodel.Grant) (createdGrant basmodel.Grant, err error) {
+	if grant.StartsAt.IsZero() {
+		grant.StartsAt = time.Now()
+	}
+
+	grant.Resources = grant.Resources.Normalize()
+	if err = grant.Validate(coract.Create); err != nil {
+		err = corerr.TickValidate(err, "E1088685", corerr.ValidationFailed, grant)
+		return
+	}
+
+	if grant.UserID == params.UserID {
+		err = limberr.New("user can't grant resources to themselves", "E1056786").
+			Message(basterm.YouCantGrantResourcesToYourself).
+			Custom(corerr.ForbiddenErr).Build()
+		return
+	}
+
+	userServ := ProvideBasUserService(basrepo.ProvideUserRepo(p.Engine))
+	if _, err = userServ.FindInCompany(params, grant.UserID); err != nil {
+		return
+	}
+
+	accessServ := ProvideBasAccessService(basrepo.ProvideAccessRepo(p.Engine))
+	if err = accessServ.CheckRulesSubset(params.UserID, grant.Resources); err != nil {
+		return
+	}
+
+	grant.GrantorID = params.UserID
+	grant.RevokedAt = nil
+	grant.ExpiredAt = nil
+
+	if createdGrant, err = p.Repo.Create(grant); err != nil {
+		err = corerr.Tick(err, "E1058372", "grant not saved", grant)
+		return
+	}
+
+	BasAccessDeleteFromCache(grant.UserID)
+	return
+}
+
+// Revoke the grant before its end, it is kept for auditing
+func (p *BasGrantServ) Revoke(params param.Param, id uint) (grant basmodel.Grant, err error) {
+	if grant, err = p.FindByID(params, id); err != nil {
+		return
+	}
+
+	if err = p.Repo.Revoke(id, time.Now()); err != nil {
+		err = corerr.Tick(err, "E1018359", "grant not revoked", id)
+		return
+	}
+
+	BasAccessDeleteFromCache(grant.UserID)
+	return
+}
+
+// ExpireWatcher mark the ended grants as expired and record them in the activities, the
+// permissions are reloaded at the end of the grant by the cache itself
+func (p *BasGrantServ) ExpireWatcher() {
+	for range time.Tick(time.Minute) {
+		now := time.Now()
+		grants, err := p.Repo.Expired(now)
+		if err != nil {
+			glog.LogError(err, "fetch expired grants")
+			continue
+		}
+
+		if len(grants) == 0 {
+			continue
+		}
+
+		ids := make([]uint, len(grants))
+		for i, v := range grants {
+			ids[i] = v.ID
+		}
+
+		if err = p.Repo.MarkExpired(ids, now); err != nil {
+			glog.LogError(err, "mark expired grants")
+			continue
+		}
+
+		for _, v := range grants {
+			before, _ := json.Marshal(v)
+			p.Engine.ActivityCh <- basmodel.Activity{
+				Event:    string(base.ExpireGrant),
+				UserID:   v.UserID,
+				Username: v.Username,
+				Before:   string(before),
+			}
+		}
+	}
+}
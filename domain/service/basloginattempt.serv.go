@@ -0,0 +1,139 @@
+package service
+
+import (
+	"omono/domain/base"
+	"omono/domain/base/basmodel"
+	"omono/domain/base/basrepo"
+	"omono/domain/base/basterm"
+	"omono/internal/consts"
+	"omono/internal/core"
+	"omono/internal/core/corerr"
+	"omono/internal/types"
+	"time"
+
+	"github.com/syronz/limberr"
+)
+
+// BasLoginAttemptServ for injecting login attempt basrepo, it protects login against brute-force
+type BasLoginAttemptServ struct {
+	Repo   basrepo.LoginAttemptRepo
+	Engine *core.Engine
+}
+
+// ProvideBasLoginAttemptService for login attempt is used in wire
+func ProvideBasLoginAttemptService(p basrepo.LoginAttemptRepo) BasLoginAttemptServ {
+	return BasLoginAttemptServ{Repo: p, Engine: p.Engine}
+}
+
+// Check returns error in case the username or the IP is locked, otherwise the delay which should
+// be waited before checking the password is returned, it grows by each failure
+func (p *BasLoginAttemptServ) Check(username, ip string) (delay time.Duration, err error) {
+	step := time.Duration(p.setting(base.LoginDelayStep, consts.LoginDelayStep)) * time.Second
+
+	for _, v := range p.subjects(username, ip) {
+		var attempt basmodel.LoginAttempt
+		if attempt, err = p.Repo.Find(v.kind, v.identifier); err != nil {
+			err = corerr.Tick(err, "E1042976", "login attempts not fetched", v.identifier)
+			return
+		}
+
+		if attempt.LockedUntil != nil && attempt.LockedUntil.After(time.Now()) {
+			err = lockedErr("E1024480", *attempt.LockedUntil)
+			return
+		}
+
+		if d := time.Duration(attempt.Failures) * step; d > delay {
+			delay = d
+		}
+	}
+
+	if delay > consts.MaxLoginDelay*time.Second {
+		delay = consts.MaxLoginDelay * time.Second
+	}
+
+	return
+}
+
+// Fail count the failure for username and IP, in case one of them reach the threshold it will
+// be locked and the lock error is returned
+func (p *BasLoginAttemptServ) Fail(username, ip string) (err error) {
+	duration := time.Duration(p.setting(base.LoginLockoutDuration, consts.LoginLockoutDuration)) *
+		time.Second
+	window := time.Now().Add(-duration)
+	until := time.Now().Add(duration)
+
+	for _, v := range p.subjects(username, ip) {
+		if err = p.Repo.Increase(v.kind, v.identifier, window); err != nil {
+			err = corerr.Tick(err, "E1086194", "failed login not counted", v.identifier)
+			return
+		}
+
+		var attempt basmodel.LoginAttempt
+		if attempt, err = p.Repo.Find(v.kind, v.identifier); err != nil {
+			err = corerr.Tick(err, "E1043029", "login attempts not fetched", v.identifier)
+			return
+		}
+
+		if uint(attempt.Failures) < p.setting(v.maxKey, v.max) {
+			continue
+		}
+
+		if err = p.Repo.Lock(v.kind, v.identifier, until); err != nil {
+			err = corerr.Tick(err, "E1050228", "login not locked", v.identifier)
+			return
+		}
+
+		err = lockedErr("E1088650", until)
+		return
+	}
+
+	return
+}
+
+// Succeed reset the failures of the username, the IP's failures remain for preventing an attacker
+// to reset them via his own account
+func (p *BasLoginAttemptServ) Succeed(username string) (err error) {
+	if err = p.Repo.Reset(basmodel.AttemptUsername, username); err != nil {
+		err = corerr.Tick(err, "E1044621", "login attempts not reset", username)
+	}
+
+	return
+}
+
+// Unlock is used by admin for removing the lock of the username
+func (p *BasLoginAttemptServ) Unlock(username string) (err error) {
+	if err = p.Repo.Reset(basmodel.AttemptUsername, username); err != nil {
+		err = corerr.Tick(err, "E1062726", "user not unlocked", username)
+	}
+
+	return
+}
+
+type attemptSubject struct {
+	kind       string
+	identifier string
+	maxKey     types.Setting
+	max        uint
+}
+
+func (p *BasLoginAttemptServ) subjects(username, ip string) []attemptSubject {
+	return []attemptSubject{
+		{basmodel.AttemptUsername, username, base.LoginMaxAttempts, consts.LoginMaxAttempts},
+		{basmodel.AttemptIP, ip, base.LoginIPMaxAttempts, consts.LoginIPMaxAttempts},
+	}
+}
+
+// setting returns the value of the setting, in case it is not a positive number the default is used
+func (p *BasLoginAttemptServ) setting(key types.Setting, def uint) uint {
+	if v := p.Engine.Setting[key].Touint(); v > 0 {
+		return v
+	}
+
+	return def
+}
+
+func lockedErr(code string, until time.Time) error {
+	return limberr.New("too many failed login attempts", code).
+		Message(basterm.TooManyFailedAttemptsTryAfterV, time.Until(until).Round(time.Second)).
+		Custom(corerr.TooManyRequestsErr).Build()
+}
@@ -0,0 +1,233 @@
+package service
+
+import (
+	"omono/domain/base"
+	"omono/domain/base/basmodel"
+	"omono/domain/base/basrepo"
+	"omono/domain/base/basterm"
+	"omono/internal/consts"
+	"omono/internal/core"
+	"omono/internal/core/corerr"
+	"omono/internal/param"
+	"omono/internal/types"
+	"omono/pkg/helper/random"
+	"omono/pkg/helper/totp"
+	"strings"
+	"time"
+
+	"github.com/syronz/limberr"
+)
+
+// BasMFAServ for injecting mfa basrepo, it handles TOTP two-factor authentication
+type BasMFAServ struct {
+	Repo   basrepo.MFARepo
+	Engine *core.Engine
+}
+
+// ProvideBasMFAService for mfa is used in wire
+func ProvideBasMFAService(p basrepo.MFARepo) BasMFAServ {
+	return BasMFAServ{Repo: p, Engine: p.Engine}
+}
+
+// IsEnabled check if the user confirmed the two-factor authentication
+func (p *BasMFAServ) IsEnabled(userID uint) (enabled bool, err error) {
+	var mfa basmodel.MFA
+	if mfa, err = p.Repo.FindByUserID(userID); err != nil {
+		if limberr.GetCustom(err) == corerr.NotFoundErr {
+			err = nil
+			return
+		}
+		err = corerr.Tick(err, "E1060836", "can't check the two-factor status", userID)
+		return
+	}
+
+	enabled = mfa.Enabled
+	return
+}
+
+// IsRequired check the mfa_required_roles setting, it is a comma separated list of role's ID.
+// Holding one of the listed roles is enough
+func (p *BasMFAServ) IsRequired(roleIDs ...uint) bool {
+	for _, v := range strings.Split(p.Engine.Setting[base.MFARequiredRoles].Value, ",") {
+		id, err := types.StrToUint(strings.TrimSpace(v))
+		if err != nil {
+			continue
+		}
+		for _, roleID := range roleIDs {
+			if id == roleID {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Enroll generate a new secret for the user, it is active after confirmation
+func (p *BasMFAServ) Enroll(params param.Param) (enroll basmodel.MFAEnroll, err error) {
+	var enabled bool
+	if enabled, err = p.IsEnabled(params.UserID); err != nil {
+		return
+	}
+
+	if enabled {
+		err = limberr.New("two-factor is already enabled", "E1048265").
+			Message(basterm.TwoFactorAuthenticationIsEnabled).
+			Custom(corerr.ForbiddenErr).Build()
+		return
+	}
+
+	if enroll.Secret, err = totp.GenerateSecret(); err != nil {
+		err = corerr.TickCustom(err, corerr.InternalServerErr, "E1049736",
+			"two-factor secret not generated")
+		return
+	}
+
+	mfa := basmodel.MFA{
+		UserID: params.UserID,
+		Secret: enroll.Secret,
+	}
+
+	if err = p.Repo.Save(mfa); err != nil {
+		err = corerr.Tick(err, "E1019317", "two-factor secret not saved", params.UserID)
+		return
+	}
+
+	userServ := ProvideBasUserService(basrepo.ProvideUserRepo(p.Engine))
+	var user basmodel.User
+	if user, err = userServ.FindByID(params.UserID); err != nil {
+		return
+	}
+
+	enroll.URI = totp.URI(consts.MFAIssuer, user.Username, enroll.Secret)
+
+	return
+}
+
+// Confirm enable the two-factor after checking the first code and return the recovery codes,
+// the raw codes are just returned once
+func (p *BasMFAServ) Confirm(params param.Param, code string) (codes []string, err error) {
+	var mfa basmodel.MFA
+	if mfa, err = p.Repo.FindByUserID(params.UserID); err != nil {
+		err = corerr.Tick(err, "E1049318", "two-factor is not enrolled", params.UserID)
+		return
+	}
+
+	if mfa.Enabled {
+		err = limberr.New("two-factor is already enabled", "E1036911").
+			Message(basterm.TwoFactorAuthenticationIsEnabled).
+			Custom(corerr.ForbiddenErr).Build()
+		return
+	}
+
+	step, ok := totp.Match(mfa.Secret, code, time.Now())
+	if !ok {
+		err = wrongCodeErr("E1030018")
+		return
+	}
+
+	hashes := make([]string, consts.RecoveryCodeCount)
+	codes = make([]string, consts.RecoveryCodeCount)
+	for i := range codes {
+		if codes[i], err = random.Token(5); err != nil {
+			err = corerr.TickCustom(err, corerr.InternalServerErr, "E1065485",
+				"recovery code not generated")
+			return
+		}
+		hashes[i] = hashToken(codes[i])
+	}
+
+	db := p.Engine.DB.Begin()
+
+	if err = p.Repo.TxEnable(db, mfa.ID, step); err != nil {
+		db.Rollback()
+		err = corerr.Tick(err, "E1048737", "two-factor not enabled", params.UserID)
+		return
+	}
+
+	if err = p.Repo.TxReplaceRecoveryCodes(db, params.UserID, hashes); err != nil {
+		db.Rollback()
+		err = corerr.Tick(err, "E1023840", "recovery codes not saved", params.UserID)
+		return
+	}
+
+	db.Commit()
+
+	return
+}
+
+// Verify check the TOTP code, each code is accepted once. In case of failure the code is checked
+// as a recovery code
+func (p *BasMFAServ) Verify(userID uint, code string) (err error) {
+	var mfa basmodel.MFA
+	if mfa, err = p.Repo.FindByUserID(userID); err != nil {
+		err = corerr.Tick(err, "E1039395", "two-factor record not found", userID)
+		return
+	}
+
+	if !mfa.Enabled {
+		err = limberr.New("two-factor is not confirmed", "E1050664").
+			Message(basterm.TwoFactorAuthenticationIsNotEnabled).
+			Custom(corerr.UnauthorizedErr).Build()
+		return
+	}
+
+	// the accepted code is not accepted again inside its window
+	if step, ok := totp.Match(mfa.Secret, code, time.Now()); ok {
+		var used bool
+		if used, err = p.Repo.UseStep(mfa.ID, step); err != nil {
+			err = corerr.Tick(err, "E1026746", "time-step of the code not saved", userID)
+			return
+		}
+
+		if !used {
+			err = wrongCodeErr("E1062457")
+		}
+		return
+	}
+
+	var used bool
+	if used, err = p.Repo.UseRecoveryCode(userID, hashToken(strings.TrimSpace(code))); err != nil {
+		err = corerr.Tick(err, "E1039887", "recovery code not checked", userID)
+		return
+	}
+
+	if !used {
+		err = wrongCodeErr("E1078562")
+	}
+
+	return
+}
+
+// Disable remove the two-factor, it is not possible in case the user's role requires it
+func (p *BasMFAServ) Disable(params param.Param, code string) (err error) {
+	userServ := ProvideBasUserService(basrepo.ProvideUserRepo(p.Engine))
+	var user basmodel.User
+	if user, err = userServ.FindByID(params.UserID); err != nil {
+		return
+	}
+
+	if p.IsRequired(user.AllRoleIDs()...) {
+		err = limberr.New("two-factor is required for the role", "E1029278").
+			Message(basterm.TwoFactorIsRequiredForYourRole).
+			Custom(corerr.ForbiddenErr).Build()
+		return
+	}
+
+	if err = p.Verify(params.UserID, code); err != nil {
+		return
+	}
+
+	if err = p.Repo.Delete(params.UserID); err != nil {
+		err = corerr.Tick(err, "E1034376", "two-factor not disabled", params.UserID)
+		return
+	}
+
+	return
+}
+
+func wrongCodeErr(code string) error {
+	return limberr.New("two-factor code is wrong", code).
+		Message(basterm.TwoFactorCodeIsWrong).
+		Custom(corerr.UnauthorizedErr).Build()
+}
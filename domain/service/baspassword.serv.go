@@ -0,0 +1,292 @@
+package service
+
+import (
+	"omono/domain/base"
+	"omono/domain/base/basmodel"
+	"omono/domain/base/basrepo"
+	"omono/domain/base/basterm"
+	"omono/internal/consts"
+	"omono/internal/core"
+	"omono/internal/core/coract"
+	"omono/internal/core/corerr"
+	"omono/internal/core/corterm"
+	"omono/internal/param"
+	"omono/pkg/glog"
+	"omono/pkg/helper/password"
+	"omono/pkg/helper/random"
+	"time"
+
+	"github.com/syronz/dict"
+	"github.com/syronz/limberr"
+	"gorm.io/gorm"
+)
+
+// BasPasswordServ for injecting password basrepo, it handles change, forgot and reset password
+type BasPasswordServ struct {
+	Repo   basrepo.PasswordRepo
+	Engine *core.Engine
+}
+
+// ProvideBasPasswordService for password is used in wire
+func ProvideBasPasswordService(p basrepo.PasswordRepo) BasPasswordServ {
+	return BasPasswordServ{Repo: p, Engine: p.Engine}
+}
+
+// Change the password of the logged in user after checking the current password
+func (p *BasPasswordServ) Change(params param.Param, pass basmodel.Password) (err error) {
+	if err = pass.Validate(coract.Update); err != nil {
+		err = corerr.TickValidate(err, "E1069128", "validation failed for changing password")
+		return
+	}
+
+	userServ := ProvideBasUserService(basrepo.ProvideUserRepo(p.Engine))
+	var user basmodel.User
+	if user, err = userServ.FindByID(params.UserID); err != nil {
+		return
+	}
+
+	if !password.Verify(pass.CurrentPassword, user.Password, p.Engine.Envs[base.PasswordSalt]) {
+		err = limberr.New("current password is wrong", "E1050370").
+			Message(basterm.CurrentPasswordIsWrong).
+			Custom(corerr.ForbiddenErr).Build()
+		return
+	}
+
+	db := p.Engine.DB.Begin()
+
+	if err = p.txSetPassword(db, user.ID, pass.NewPassword); err != nil {
+		db.Rollback()
+		return
+	}
+
+	db.Commit()
+
+	err = p.invalidateSessions(user.ID)
+
+	return
+}
+
+// Forgot create a reset token and send it to the email of the user, in case the email not exist
+// no error returned for preventing the enumeration of users
+func (p *BasPasswordServ) Forgot(pass basmodel.Password) (err error) {
+	if err = pass.Validate(coract.Forgot); err != nil {
+		err = corerr.TickValidate(err, "E1065878", "validation failed for forgot password")
+		return
+	}
+
+	userRepo := basrepo.ProvideUserRepo(p.Engine)
+	var user basmodel.User
+	if user, err = userRepo.FindByEmail(pass.Email); err != nil {
+		if limberr.GetCustom(err) == corerr.NotFoundErr {
+			err = nil
+			return
+		}
+		err = corerr.Tick(err, "E1076653", "user not fetched for forgot password", pass.Email)
+		return
+	}
+
+	var token string
+	if token, err = random.Token(32); err != nil {
+		err = corerr.TickCustom(err, corerr.InternalServerErr, "E1068815",
+			"reset token not generated")
+		return
+	}
+
+	expiration := p.Engine.Envs.ToDuration(base.PasswordResetExpiration) * time.Second
+	reset := basmodel.PasswordReset{
+		UserID:    user.ID,
+		Token:     hashToken(token),
+		ExpiresAt: time.Now().Add(expiration),
+	}
+
+	if err = p.Repo.CreateReset(reset); err != nil {
+		err = corerr.Tick(err, "E1048045", "reset token not saved", user.ID)
+		return
+	}
+
+	go p.sendResetEmail(user, token, expiration)
+
+	return
+}
+
+// Reset change the password via the token which has been sent to the email, it is single-use
+func (p *BasPasswordServ) Reset(pass basmodel.Password) (user basmodel.User, err error) {
+	if err = pass.Validate(coract.Reset); err != nil {
+		err = corerr.TickValidate(err, "E1050895", "validation failed for resetting password")
+		return
+	}
+
+	db := p.Engine.DB.Begin()
+
+	var reset basmodel.PasswordReset
+	if reset, err = p.Repo.TxFindReset(db, hashToken(pass.Token)); err != nil {
+		db.Rollback()
+		err = corerr.Tick(err, "E1061852", "reset token not found")
+		return
+	}
+
+	if reset.UsedAt != nil || reset.ExpiresAt.Before(time.Now()) {
+		db.Rollback()
+		err = resetTokenErr("E1078119")
+		return
+	}
+
+	var used bool
+	if used, err = p.Repo.TxUseReset(db, reset.ID); err != nil {
+		db.Rollback()
+		err = corerr.Tick(err, "E1058613", "reset token not used", reset.ID)
+		return
+	}
+
+	if !used {
+		db.Rollback()
+		err = resetTokenErr("E1072806")
+		return
+	}
+
+	if err = p.txSetPassword(db, reset.UserID, pass.NewPassword); err != nil {
+		db.Rollback()
+		return
+	}
+
+	db.Commit()
+
+	if err = p.invalidateSessions(reset.UserID); err != nil {
+		return
+	}
+
+	userServ := ProvideBasUserService(basrepo.ProvideUserRepo(p.Engine))
+	if user, err = userServ.FindByID(reset.UserID); err != nil {
+		return
+	}
+
+	attemptServ := ProvideBasLoginAttemptService(basrepo.ProvideLoginAttemptRepo(p.Engine))
+	glog.CheckError(attemptServ.Unlock(user.Username), "unlock user after password reset", user.ID)
+
+	user.Password = ""
+
+	return
+}
+
+// PurgeWatcher delete expired reset tokens periodically
+func (p *BasPasswordServ) PurgeWatcher() {
+	for range time.Tick(time.Hour) {
+		if err := p.Repo.PurgeExpired(time.Now()); err != nil {
+			glog.LogError(err, "purge expired password reset tokens")
+		}
+	}
+}
+
+// txSetPassword hash and save the new password
+func (p *BasPasswordServ) txSetPassword(db *gorm.DB, userID uint, newPassword string) (err error) {
+	if err = p.TxCheckHistory(db, userID, newPassword); err != nil {
+		return
+	}
+
+	var hash string
+	if hash, err = password.Hash(newPassword, p.Engine.Envs[base.PasswordSalt]); err != nil {
+		err = corerr.TickCustom(err, corerr.InternalServerErr, "E1052112",
+			"password not hashed", userID)
+		return
+	}
+
+	userRepo := basrepo.ProvideUserRepo(p.Engine)
+	if err = userRepo.TxUpdatePassword(db, userID, hash); err != nil {
+		err = corerr.Tick(err, "E1058369", "password not updated", userID)
+		return
+	}
+
+	err = p.TxAddHistory(db, userID, hash)
+
+	return
+}
+
+// TxCheckHistory returns validation error in case the password is one of the last passwords,
+// the number of checked passwords comes from password_history setting
+func (p *BasPasswordServ) TxCheckHistory(db *gorm.DB, userID uint, newPassword string) (err error) {
+	n := int(p.Engine.Setting[base.PasswordHistory].Touint())
+	if n == 0 {
+		return
+	}
+
+	var histories []basmodel.PasswordHistory
+	if histories, err = p.Repo.TxLastHistories(db, userID, n); err != nil {
+		err = corerr.Tick(err, "E1021084", "password history not fetched", userID)
+		return
+	}
+
+	for _, v := range histories {
+		if password.Verify(newPassword, v.Password, p.Engine.Envs[base.PasswordSalt]) {
+			err = limberr.AddInvalidParam(err, "password",
+				basterm.VIsUsedRecentlyChooseAnotherOne, dict.R(corterm.Password))
+			err = corerr.TickValidate(err, "E1073294", "password is used recently", userID)
+			return
+		}
+	}
+
+	return
+}
+
+// TxAddHistory save the hashed password in the history
+func (p *BasPasswordServ) TxAddHistory(db *gorm.DB, userID uint, hash string) (err error) {
+	history := basmodel.PasswordHistory{
+		UserID:   userID,
+		Password: hash,
+	}
+
+	if err = p.Repo.TxCreateHistory(db, history); err != nil {
+		err = corerr.Tick(err, "E1035161", "password history not saved", userID)
+	}
+
+	return
+}
+
+// IsExpired check the password_max_age setting, it is in days and zero means no expiration. In
+// case the password_changed_at is not filled the creation of the user is considered
+func (p *BasPasswordServ) IsExpired(user basmodel.User) bool {
+	days := p.Engine.Setting[base.PasswordMaxAge].Touint()
+	if days == 0 {
+		return false
+	}
+
+	changedAt := user.CreatedAt
+	if user.PasswordChangedAt != nil {
+		changedAt = *user.PasswordChangedAt
+	}
+
+	return changedAt.Add(time.Duration(days) * 24 * time.Hour).Before(time.Now())
+}
+
+// BasLoadPasswordPolicy read the password settings and apply them in the validation of users
+func BasLoadPasswordPolicy(engine *core.Engine) {
+	policy := password.Policy{MinLength: consts.MinimumPasswordChar}
+	if v := engine.Setting[base.PasswordMinLength].Touint(); v > 0 {
+		policy.MinLength = int(v)
+	}
+
+	policy.ParseClasses(engine.Setting[base.PasswordRequiredClasses].Value)
+	basmodel.SetPasswordPolicy(policy)
+}
+
+// invalidateSessions revoke all refresh tokens and terminate the sessions of the user
+func (p *BasPasswordServ) invalidateSessions(userID uint) (err error) {
+	tokenServ := ProvideBasTokenService(basrepo.ProvideTokenRepo(p.Engine))
+	err = tokenServ.RevokeUser(userID)
+
+	return
+}
+
+func (p *BasPasswordServ) sendResetEmail(user basmodel.User, token string, expiration time.Duration) {
+	subject := dict.T(basterm.PasswordReset, user.Lang)
+	title := dict.T(basterm.UseTheTokenForResettingPasswordItExpiresAfterV, user.Lang, expiration)
+
+	if err := basMail(p.Engine, user.Email).SendEmail(subject, title, "", token); err != nil {
+		glog.LogError(err, "password reset email not sent", user.ID)
+	}
+}
+
+func resetTokenErr(code string) error {
+	return limberr.New("reset token is used or expired", code).
+		Message(basterm.ResetTokenIsNotValid).
+		Custom(corerr.UnauthorizedErr).Build()
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"omono/domain/base/basmodel"
+	"omono/domain/base/basrepo"
+	"omono/internal/consts"
+	"omono/internal/core"
+	"omono/internal/core/corerr"
+	"omono/internal/types"
+	"omono/pkg/glog"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// BasPermissionEventServ is the database bus of the permission cache, each instance stores its
+// invalidations in bas_permission_events and polls the events of the others. The instance
+// receives its own events as well, invalidating a user twice is harmless
+type BasPermissionEventServ struct {
+	Repo     basrepo.PermissionEventRepo
+	Engine   *core.Engine
+	mu       sync.RWMutex
+	handlers []func(userIDs []uint)
+}
+
+// ProvideBasPermissionEventService for permission event is used in wire
+func ProvideBasPermissionEventService(p basrepo.PermissionEventRepo) *BasPermissionEventServ {
+	return &BasPermissionEventServ{Repo: p, Engine: p.Engine}
+}
+
+// Publish store the invalidation for the other instances
+func (p *BasPermissionEventServ) Publish(userIDs []uint) (err error) {
+	ids := make([]string, len(userIDs))
+	for i, v := range userIDs {
+		ids[i] = strconv.FormatUint(uint64(v), 10)
+	}
+
+	event := basmodel.PermissionEvent{UserIDs: strings.Join(ids, ",")}
+	if err = p.Repo.Create(event); err != nil {
+		err = corerr.Tick(err, "E1081576", "permission event not published", event.UserIDs)
+	}
+
+	return
+}
+
+// Subscribe add the handler which is called for each polled event
+func (p *BasPermissionEventServ) Subscribe(handler func(userIDs []uint)) {
+	p.mu.Lock()
+	p.handlers = append(p.handlers, handler)
+	p.mu.Unlock()
+}
+
+// Watcher polls the new events and delete the old ones, the events before starting the
+// watcher are skipped because the cache of a new instance is empty
+func (p *BasPermissionEventServ) Watcher() {
+	lastID, err := p.Repo.LastID()
+	glog.CheckError(err, "last permission event not fetched")
+
+	lastPurge := time.Now()
+	for range time.Tick(consts.PermissionEventPollInterval * time.Second) {
+		var events []basmodel.PermissionEvent
+		if events, err = p.Repo.ListAfter(lastID); err != nil {
+			glog.LogError(err, "poll permission events")
+			continue
+		}
+
+		for _, v := range events {
+			p.deliver(parseUserIDs(v.UserIDs))
+			lastID = v.ID
+		}
+
+		if time.Since(lastPurge) > time.Hour {
+			lastPurge = time.Now()
+			if err = p.Repo.PurgeBefore(lastPurge.Add(-time.Hour)); err != nil {
+				glog.LogError(err, "purge permission events")
+			}
+		}
+	}
+}
+
+func (p *BasPermissionEventServ) deliver(userIDs []uint) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	for _, handler := range p.handlers {
+		handler(userIDs)
+	}
+}
+
+func parseUserIDs(str string) (userIDs []uint) {
+	for _, v := range strings.Split(str, ",") {
+		if id, err := types.StrToUint(strings.TrimSpace(v)); err == nil && id > 0 {
+			userIDs = append(userIDs, id)
+		}
+	}
+
+	return
+}
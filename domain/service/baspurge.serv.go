@@ -0,0 +1,57 @@
+package service
+
+import (
+	"omono/domain/base"
+	"omono/domain/base/basrepo"
+	"omono/domain/notification/notrepo"
+	"omono/domain/segment/segrepo"
+	"omono/domain/subscriber/subrepo"
+	"omono/internal/core"
+	"omono/pkg/glog"
+	"time"
+)
+
+// deletedPurger is implemented by the repos of the entities which are soft deleted
+type deletedPurger interface {
+	PurgeDeleted(before time.Time) error
+}
+
+// BasPurgeDeletedWatcher hard delete the soft deleted rows after the deleted_retention setting,
+// it is in days and zero keeps them forever. The dependent entities are purged first, a row
+// which is still referenced is kept by its foreign key and the error is logged
+func BasPurgeDeletedWatcher(engine *core.Engine) {
+	messageRepo := notrepo.ProvideMessageRepo(engine)
+	phoneRepo := subrepo.ProvidePhoneRepo(engine)
+	accountRepo := subrepo.ProvideAccountRepo(engine)
+	cityRepo := basrepo.ProvideCityRepo(engine)
+	userRepo := basrepo.ProvideUserRepo(engine)
+	roleRepo := basrepo.ProvideRoleRepo(engine)
+	companyRepo := segrepo.ProvideCompanyRepo(engine)
+
+	purgers := []struct {
+		part   string
+		purger deletedPurger
+	}{
+		{"messages", &messageRepo},
+		{"phones", &phoneRepo},
+		{"accounts", &accountRepo},
+		{"cities", &cityRepo},
+		{"users", &userRepo},
+		{"roles", &roleRepo},
+		{"companies", &companyRepo},
+	}
+
+	for range time.Tick(time.Hour) {
+		days := engine.Setting[base.DeletedRetention].Touint()
+		if days == 0 {
+			continue
+		}
+
+		before := time.Now().Add(-time.Duration(days) * 24 * time.Hour)
+		for _, v := range purgers {
+			if err := v.purger.PurgeDeleted(before); err != nil {
+				glog.LogError(err, "purge deleted "+v.part)
+			}
+		}
+	}
+}
@@ -0,0 +1,293 @@
+package service
+
+import (
+	"fmt"
+	"omono/domain/base"
+	"omono/domain/base/basmodel"
+	"omono/domain/base/basrepo"
+	"omono/domain/base/basterm"
+	"omono/domain/base/enum/userstatus"
+	"omono/domain/notification/notmodel"
+	"omono/domain/notification/notrepo"
+	"omono/internal/consts"
+	"omono/internal/core"
+	"omono/internal/core/coract"
+	"omono/internal/core/corerr"
+	"omono/internal/types"
+	"omono/pkg/glog"
+	"omono/pkg/helper/email"
+	"omono/pkg/helper/random"
+	"strings"
+	"time"
+
+	"github.com/syronz/dict"
+	"github.com/syronz/limberr"
+)
+
+// BasRegistrationServ handles the email verification and the approval of registered users
+type BasRegistrationServ struct {
+	Repo   basrepo.RegistrationRepo
+	Engine *core.Engine
+}
+
+// ProvideBasRegistrationService for registration is used in wire
+func ProvideBasRegistrationService(p basrepo.RegistrationRepo) BasRegistrationServ {
+	return BasRegistrationServ{Repo: p, Engine: p.Engine}
+}
+
+// InitialStatus returns the status of a new registered user based on the settings
+func (p *BasRegistrationServ) InitialStatus() types.Enum {
+	switch {
+	case p.Engine.Setting[base.RegistrationVerifyEmail].ToBool():
+		return userstatus.PendingVerification
+	case p.Engine.Setting[base.RegistrationRequireApproval].ToBool():
+		return userstatus.PendingApproval
+	}
+
+	return userstatus.Active
+}
+
+// Start is called after registration, it sends the verification email or notifies the approvers
+// based on the status of the user
+func (p *BasRegistrationServ) Start(user basmodel.User) (err error) {
+	switch user.Status {
+	case userstatus.PendingVerification:
+		err = p.sendVerification(user)
+	case userstatus.PendingApproval:
+		go p.notifyApprovers(user)
+	}
+
+	return
+}
+
+// Verify mark the email as verified via the token, the user becomes active or waits for the
+// approval in case it is required
+func (p *BasRegistrationServ) Verify(verification basmodel.Verification) (user basmodel.User,
+	err error) {
+	if err = verification.Validate(coract.Verify); err != nil {
+		err = corerr.TickValidate(err, "E1087870", "validation failed for verifying email")
+		return
+	}
+
+	db := p.Engine.DB.Begin()
+
+	var ev basmodel.EmailVerification
+	if ev, err = p.Repo.TxFindVerification(db, hashToken(verification.Token)); err != nil {
+		db.Rollback()
+		err = corerr.Tick(err, "E1027329", "verification token not found")
+		return
+	}
+
+	if ev.UsedAt != nil || ev.ExpiresAt.Before(time.Now()) {
+		db.Rollback()
+		err = verificationTokenErr("E1068237")
+		return
+	}
+
+	var used bool
+	if used, err = p.Repo.TxUseVerification(db, ev.ID); err != nil {
+		db.Rollback()
+		err = corerr.Tick(err, "E1056045", "verification token not used", ev.ID)
+		return
+	}
+
+	if !used {
+		db.Rollback()
+		err = verificationTokenErr("E1095320")
+		return
+	}
+
+	next := userstatus.Active
+	if p.Engine.Setting[base.RegistrationRequireApproval].ToBool() {
+		next = userstatus.PendingApproval
+	}
+
+	userRepo := basrepo.ProvideUserRepo(p.Engine)
+
+	// in case an admin changed the status before, it is not touched
+	var updated bool
+	if updated, err = userRepo.TxUpdateStatus(db, ev.UserID, userstatus.PendingVerification,
+		next); err != nil {
+		db.Rollback()
+		err = corerr.Tick(err, "E1017630", "status of user not updated", ev.UserID)
+		return
+	}
+
+	db.Commit()
+
+	userServ := ProvideBasUserService(userRepo)
+	if user, err = userServ.FindByID(ev.UserID); err != nil {
+		return
+	}
+
+	if updated && next == userstatus.PendingApproval {
+		go p.notifyApprovers(user)
+	}
+
+	user.Password = ""
+
+	return
+}
+
+// Resend the verification email, the result is the same for unknown emails for preventing
+// user enumeration
+func (p *BasRegistrationServ) Resend(verification basmodel.Verification) (err error) {
+	if err = verification.Validate(coract.Resend); err != nil {
+		err = corerr.TickValidate(err, "E1078875", "validation failed for resending verification")
+		return
+	}
+
+	userRepo := basrepo.ProvideUserRepo(p.Engine)
+
+	var user basmodel.User
+	if user, err = userRepo.FindByEmail(verification.Email); err != nil {
+		if limberr.GetCustom(err) == corerr.NotFoundErr {
+			err = nil
+			return
+		}
+		err = corerr.Tick(err, "E1042630", "user not fetched for resending verification",
+			verification.Email)
+		return
+	}
+
+	if user.Status != userstatus.PendingVerification {
+		return
+	}
+
+	err = p.sendVerification(user)
+
+	return
+}
+
+// Approve activate the user which is waiting for approval
+func (p *BasRegistrationServ) Approve(id uint) (user basmodel.User, err error) {
+	userRepo := basrepo.ProvideUserRepo(p.Engine)
+	userServ := ProvideBasUserService(userRepo)
+
+	if user, err = userServ.FindByID(id); err != nil {
+		return
+	}
+
+	db := p.Engine.DB.Begin()
+
+	var updated bool
+	if updated, err = userRepo.TxUpdateStatus(db, id, userstatus.PendingApproval,
+		userstatus.Active); err != nil {
+		db.Rollback()
+		err = corerr.Tick(err, "E1070535", "user not approved", id)
+		return
+	}
+
+	if !updated {
+		db.Rollback()
+		err = limberr.New("user is not pending approval", "E1035121").
+			Message(basterm.UserIsNotWaitingForApproval).
+			Custom(corerr.ValidationFailedErr).Build()
+		return
+	}
+
+	db.Commit()
+
+	user.Status = userstatus.Active
+	user.Password = ""
+
+	if user.Email != "" {
+		go p.sendEmail(user, dict.T(basterm.AccountApproved, user.Lang),
+			dict.T(basterm.YourAccountIsApprovedYouCanLoginNow, user.Lang), "")
+	}
+
+	return
+}
+
+// PurgeWatcher delete expired verification tokens periodically
+func (p *BasRegistrationServ) PurgeWatcher() {
+	for range time.Tick(time.Hour) {
+		if err := p.Repo.PurgeExpired(time.Now()); err != nil {
+			glog.LogError(err, "purge expired email verification tokens")
+		}
+	}
+}
+
+func (p *BasRegistrationServ) sendVerification(user basmodel.User) (err error) {
+	var token string
+	if token, err = random.Token(32); err != nil {
+		err = corerr.TickCustom(err, corerr.InternalServerErr, "E1086430",
+			"verification token not generated")
+		return
+	}
+
+	expiration := consts.EmailVerificationDuration * time.Second
+	verification := basmodel.EmailVerification{
+		UserID:    user.ID,
+		Token:     hashToken(token),
+		ExpiresAt: time.Now().Add(expiration),
+	}
+
+	if err = p.Repo.CreateVerification(verification); err != nil {
+		err = corerr.Tick(err, "E1046344", "verification token not saved", user.ID)
+		return
+	}
+
+	link := fmt.Sprintf("%v/register/verify?token=%v",
+		strings.TrimRight(p.Engine.Envs[core.URL], "/"), token)
+
+	go p.sendEmail(user, dict.T(basterm.EmailVerification, user.Lang),
+		dict.T(basterm.OpenTheLinkForVerifyingYourEmailItExpiresAfterV, user.Lang, expiration),
+		link)
+
+	return
+}
+
+// notifyApprovers send a notification message to the users which have the UserApprove resource
+func (p *BasRegistrationServ) notifyApprovers(user basmodel.User) {
+	userRepo := basrepo.ProvideUserRepo(p.Engine)
+	users, err := userRepo.FindActive()
+	if err != nil {
+		glog.LogError(err, "approvers not fetched", user.ID)
+		return
+	}
+
+	accessServ := ProvideBasAccessService(basrepo.ProvideAccessRepo(p.Engine))
+	messageServ := ProvideNotMessageService(notrepo.ProvideMessageRepo(p.Engine))
+	for _, v := range users {
+		if approver, err := accessServ.HasResource(v.ID, base.UserApprove); err != nil || !approver {
+			continue
+		}
+
+		message := notmodel.Message{
+			RecipientID: v.ID,
+			Title:       dict.T(basterm.RegistrationIsWaitingForApproval, v.Lang),
+			Message:     dict.T(basterm.VIsRegisteredAndWaitingForApproval, v.Lang, user.Username),
+			URI:         fmt.Sprintf("/users/%v", user.ID),
+			Part:        basterm.Users,
+		}
+
+		_, err := messageServ.Create(message)
+		glog.CheckError(err, "approval notification not created", v.ID, user.ID)
+	}
+}
+
+func (p *BasRegistrationServ) sendEmail(user basmodel.User, subject, title, body string) {
+	if err := basMail(p.Engine, user.Email).SendEmail(subject, title, "", body); err != nil {
+		glog.LogError(err, "registration email not sent", user.ID)
+	}
+}
+
+// basMail returns the email config based on the SMTP environments
+func basMail(engine *core.Engine, to string) *email.ConfigEmail {
+	mail := email.Config()
+	mail.From = engine.Envs[base.SMTPFrom]
+	mail.To = to
+	mail.Host = engine.Envs[base.SMTPHost]
+	mail.Port = engine.Envs.ToInt(base.SMTPPort)
+	mail.Username = engine.Envs[base.SMTPUsername]
+	mail.Password = engine.Envs[base.SMTPPassword]
+
+	return mail
+}
+
+func verificationTokenErr(code string) error {
+	return limberr.New("verification token is used or expired", code).
+		Message(basterm.VerificationTokenIsNotValid).
+		Custom(corerr.UnauthorizedErr).Build()
+}
@@ -38,6 +38,45 @@ func (p *BasRoleServ) FindByID(id uint) (role basmodel.Role, err error) {
 		return
 	}
 
+	if role.ParentIDs, err = p.Repo.ParentIDs(id); err != nil {
+		err = corerr.Tick(err, "E1013861", "can't fetch the parents of the role", id)
+		return
+	}
+
+	return
+}
+
+// checkParents verify the existence of the parents and prevent cycles, a role can't extend
+// itself or a role which already inherits from it
+func (p *BasRoleServ) checkParents(role basmodel.Role) (err error) {
+	for _, v := range role.ParentIDs {
+		if _, err = p.Repo.FindByID(v); err != nil {
+			err = corerr.Tick(err, "E1010318", "parent of the role not found", v)
+			return
+		}
+	}
+
+	if role.ID == 0 || len(role.ParentIDs) == 0 {
+		return
+	}
+
+	var ancestors []uint
+	if ancestors, err = p.Repo.Ancestors(role.ParentIDs); err != nil {
+		err = corerr.Tick(err, "E1095941", "can't fetch the ancestors of the role", role.ID)
+		return
+	}
+
+	for _, v := range ancestors {
+		if v == role.ID {
+			err = limberr.New("cycle in the parents of the role", "E1029372").
+				Message(corerr.ValidationFailed).
+				Custom(corerr.ValidationFailedErr).Build()
+			err = limberr.AddInvalidParam(err, "parent_ids",
+				basterm.RoleCantExtendItsDescendants)
+			return
+		}
+	}
+
 	return
 }
 
@@ -57,6 +96,15 @@ func (p *BasRoleServ) List(params param.Param) (roles []basmodel.Role,
 	return
 }
 
+// Aggregate returns the grouped rows of the roles, it is the aggregate mode of the List
+func (p *BasRoleServ) Aggregate(params param.Param) (rows []map[string]interface{}, err error) {
+	if rows, err = p.Repo.Aggregate(params); err != nil {
+		glog.CheckError(err, "error in roles aggregate")
+	}
+
+	return
+}
+
 // Create a role
 func (p *BasRoleServ) Create(role basmodel.Role) (createdRole basmodel.Role, err error) {
 	return p.TxCreate(p.Engine.DB, role)
@@ -64,38 +112,72 @@ func (p *BasRoleServ) Create(role basmodel.Role) (createdRole basmodel.Role, err
 
 // TxCreate is used in case of transaction activated
 func (p *BasRoleServ) TxCreate(db *gorm.DB, role basmodel.Role) (createdRole basmodel.Role, err error) {
+	role.Resources = role.Resources.Normalize()
 	if err = role.Validate(coract.Save); err != nil {
 		err = corerr.TickValidate(err, "E1098554", "validation failed in creating the role", role)
 		return
 	}
 
+	if err = p.checkParents(role); err != nil {
+		return
+	}
+
 	if createdRole, err = p.Repo.TxCreate(db, role); err != nil {
 		err = corerr.Tick(err, "E1042894", "role not created", role)
 		return
 	}
 
+	if err = p.Repo.TxReplaceParents(db, createdRole.ID, role.ParentIDs); err != nil {
+		err = corerr.Tick(err, "E1061900", "parents of the role not saved", role)
+		return
+	}
+	createdRole.ParentIDs = role.ParentIDs
+
 	return
 }
 
-// Save a role, if it is exist update it, if not create it
+// Save a role, if it is exist update it, if not create it. In case the parent_ids is not sent
+// the current parents are kept
 func (p *BasRoleServ) Save(role basmodel.Role) (savedRole, roleBefore basmodel.Role, err error) {
+	if roleBefore, err = p.FindByID(role.ID); err != nil {
+		err = corerr.Tick(err, "E1067466", "can't fetch role by id", role.ID)
+		return
+	}
+
+	if role.ParentIDs == nil {
+		role.ParentIDs = roleBefore.ParentIDs
+	}
+
+	role.Resources = role.Resources.Normalize()
 	if err = role.Validate(coract.Save); err != nil {
 		err = corerr.TickValidate(err, "E1037119", corerr.ValidationFailed, role)
 		return
 	}
 
-	if roleBefore, err = p.FindByID(role.ID); err != nil {
-		err = corerr.Tick(err, "E1067466", "can't fetch role by id", role.ID)
+	if err = p.checkParents(role); err != nil {
 		return
 	}
 
 	role.CreatedAt = roleBefore.CreatedAt
+	if role.UpdatedAt.IsZero() {
+		role.UpdatedAt = roleBefore.UpdatedAt
+	}
 
-	if savedRole, err = p.Repo.Save(role); err != nil {
+	db := p.Engine.DB.Begin()
+	if savedRole, err = p.Repo.TxSave(db, role); err != nil {
 		err = corerr.Tick(err, "E1078742", "role not saved")
+		db.Rollback()
 		return
 	}
 
+	if err = p.Repo.TxReplaceParents(db, role.ID, role.ParentIDs); err != nil {
+		err = corerr.Tick(err, "E1046475", "parents of the role not saved", role)
+		db.Rollback()
+		return
+	}
+	db.Commit()
+	savedRole.ParentIDs = role.ParentIDs
+
 	BasAccessResetFullCache()
 	return
 }
@@ -125,6 +207,22 @@ func (p *BasRoleServ) Delete(id uint) (role basmodel.Role, err error) {
 		return
 	}
 
+	// the additional roles of the users are checked as well
+	userRoleRepo := basrepo.ProvideUserRoleRepo(p.Engine)
+	var usernames []string
+	if usernames, err = userRoleRepo.Usernames(id, 1); err != nil {
+		err = corerr.Tick(err, "E1013703", "related user roles not fetched for deleting the role")
+		return
+	}
+
+	if len(usernames) > 0 {
+		err = limberr.New("role is assigned to a user", "E1068172").
+			Message(corerr.VIsConnectedToAVVPleaseDeleteItFirst, dict.R(basterm.Role),
+				dict.R(basterm.User), usernames[0]).
+			Custom(corerr.ForeignErr).Build()
+		return
+	}
+
 	if err = p.Repo.Delete(role); err != nil {
 		err = corerr.Tick(err, "E1017987", "role not deleted")
 		return
@@ -134,11 +232,24 @@ func (p *BasRoleServ) Delete(id uint) (role basmodel.Role, err error) {
 	return
 }
 
+// Restore the soft deleted role
+func (p *BasRoleServ) Restore(id uint) (role basmodel.Role, err error) {
+	if err = p.Repo.Restore(id); err != nil {
+		err = corerr.Tick(err, "E1034430", "role not restored", id)
+		return
+	}
+
+	BasAccessResetFullCache()
+
+	return p.FindByID(id)
+}
+
 // Excel is used for export excel file
 func (p *BasRoleServ) Excel(params param.Param) (roles []basmodel.Role, err error) {
 	params.Limit = p.Engine.Envs.ToInt(core.ExcelMaxRows)
 	params.Offset = 0
 	params.Order = fmt.Sprintf("%v.id ASC", basmodel.RoleTable)
+	params.After, params.Before = "", ""
 
 	if roles, err = p.Repo.List(params); err != nil {
 		err = corerr.Tick(err, "E1067385", "cant generate the excel list for roles")
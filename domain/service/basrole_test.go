@@ -6,6 +6,7 @@ import (
 	"omono/domain/base/basmodel"
 	"omono/domain/base/basrepo"
 	"omono/internal/core"
+	"omono/internal/core/corperm"
 	"omono/internal/param"
 	"omono/test/kernel"
 	"testing"
@@ -31,7 +32,7 @@ func TestRoleCreate(t *testing.T) {
 		{
 			in: basmodel.Role{
 				Name:        "created 1",
-				Resources:   string(base.SuperAccess),
+				Resources:   corperm.RulesOf(base.SuperAccess),
 				Description: "created 1",
 			},
 			err: nil,
@@ -39,7 +40,7 @@ func TestRoleCreate(t *testing.T) {
 		{
 			in: basmodel.Role{
 				Name:        "created 1",
-				Resources:   string(base.SuperAccess),
+				Resources:   corperm.RulesOf(base.SuperAccess),
 				Description: "created 2",
 			},
 			err: errors.New("duplicate"),
@@ -47,21 +48,21 @@ func TestRoleCreate(t *testing.T) {
 		{
 			in: basmodel.Role{
 				Name:      "minimum fields",
-				Resources: string(base.SuperAccess),
+				Resources: corperm.RulesOf(base.SuperAccess),
 			},
 			err: nil,
 		},
 		{
 			in: basmodel.Role{
 				Name:        "long name: big name big name big name big name big name big name big name big name big name big name big name big name big name big name big name big name big name big name big name big name big name big name big name big name big name big name big name big name big name big name big name big name big name big name big name big name big name big name big name big name big name big name big name big name big name big name big name big name big name big name big name big name big name big name big name big name big name big name big name big name big name big name big name big name big name big name big name big name big name big name big name big name big name big name big name big name big name big name big name big name big name big name big name big name big name big name big name big name big name big name big name big name big name big name big name big name big name big name big name big name",
-				Resources:   string(base.SuperAccess),
+				Resources:   corperm.RulesOf(base.SuperAccess),
 				Description: "created 2",
 			},
 			err: errors.New("data too long for name"),
 		},
 		{
 			in: basmodel.Role{
-				Resources:   string(base.SuperAccess),
+				Resources:   corperm.RulesOf(base.SuperAccess),
 				Description: "created 3",
 			},
 			err: errors.New("name is required"),
@@ -90,7 +91,7 @@ func TestRoleUpdate(t *testing.T) {
 					ID: 5,
 				},
 				Name:        "num 1 update",
-				Resources:   string(base.SuperAccess),
+				Resources:   corperm.RulesOf(base.SuperAccess),
 				Description: "num 1 update",
 			},
 			err: nil,
@@ -0,0 +1,224 @@
+package service
+
+import (
+	"fmt"
+	"omono/domain/base"
+	"omono/domain/base/basmodel"
+	"omono/domain/base/basrepo"
+	"omono/domain/base/basterm"
+	"omono/internal/consts"
+	"omono/internal/core"
+	"omono/internal/core/corerr"
+	"omono/internal/param"
+	"omono/pkg/glog"
+	"time"
+
+	"github.com/syronz/limberr"
+	"gorm.io/gorm"
+)
+
+// BasSessionServ for injecting session basrepo
+type BasSessionServ struct {
+	Repo   basrepo.SessionRepo
+	Engine *core.Engine
+}
+
+// ProvideBasSessionService for session is used in wire
+func ProvideBasSessionService(p basrepo.SessionRepo) BasSessionServ {
+	return BasSessionServ{Repo: p, Engine: p.Engine}
+}
+
+// TxCreate start a session for the login, it lives as long as the refresh token
+func (p *BasSessionServ) TxCreate(db *gorm.DB, userID uint,
+	auth basmodel.Auth) (session basmodel.Session, err error) {
+	now := time.Now()
+
+	userAgent := auth.UserAgent
+	if len(userAgent) > 255 {
+		userAgent = userAgent[:255]
+	}
+
+	session = basmodel.Session{
+		UserID:     userID,
+		IP:         auth.IP,
+		UserAgent:  userAgent,
+		LastSeenAt: now,
+		ExpiresAt:  now.Add(p.Engine.Envs.ToDuration(base.JWTRefreshExpiration) * time.Second),
+	}
+
+	if session, err = p.Repo.TxCreate(db, session); err != nil {
+		err = corerr.Tick(err, "E1093929", "session not created", userID)
+		return
+	}
+
+	return
+}
+
+// TxExtend is called after refreshing the token, terminated sessions can't be extended
+func (p *BasSessionServ) TxExtend(db *gorm.DB, id uint) (err error) {
+	if err = p.Check(id); err != nil {
+		return
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(p.Engine.Envs.ToDuration(base.JWTRefreshExpiration) * time.Second)
+	if err = p.Repo.TxExtend(db, id, expiresAt, now); err != nil {
+		err = corerr.Tick(err, "E1036920", "session not extended", id)
+	}
+
+	return
+}
+
+// Check is used in AuthGuard, the token of a terminated or expired session is refused
+func (p *BasSessionServ) Check(id uint) (err error) {
+	var session basmodel.Session
+	if session, err = p.Repo.FindByID(id); err != nil {
+		if limberr.GetCustom(err) == corerr.NotFoundErr {
+			err = sessionErr("E1085058", "session not found")
+			return
+		}
+		err = corerr.Tick(err, "E1074343", "can't fetch the session", id)
+		return
+	}
+
+	now := time.Now()
+
+	if session.TerminatedAt != nil {
+		err = sessionErr("E1020511", "session is terminated")
+		return
+	}
+
+	if session.ExpiresAt.Before(now) {
+		err = sessionErr("E1036058", "session is expired")
+		return
+	}
+
+	// last_seen_at is updated once per minute for reducing the writes
+	if session.LastSeenAt.Add(time.Minute).Before(now) {
+		glog.CheckError(p.Repo.Touch(id, now), "update last seen of session", id)
+	}
+
+	return
+}
+
+// ListSelf returns the active sessions of the current user, the current session is marked
+func (p *BasSessionServ) ListSelf(params param.Param) (sessions []basmodel.Session,
+	count int64, err error) {
+
+	params.PreCondition = fmt.Sprintf("bas_sessions.user_id = %v AND "+
+		"bas_sessions.terminated_at IS NULL AND bas_sessions.expires_at > '%v'",
+		params.UserID, time.Now().Format(consts.DateTimeLayout))
+
+	if sessions, count, err = p.list(params); err != nil {
+		return
+	}
+
+	for i := range sessions {
+		sessions[i].Current = sessions[i].ID == params.SessionID
+	}
+
+	return
+}
+
+// ListByUser returns all sessions of a user including the terminated ones, it is used by admins
+// and the user should be a member of the active company
+func (p *BasSessionServ) ListByUser(params param.Param, userID uint) (sessions []basmodel.Session,
+	count int64, err error) {
+
+	userServ := ProvideBasUserService(basrepo.ProvideUserRepo(p.Engine))
+	if _, err = userServ.FindInCompany(params, userID); err != nil {
+		return
+	}
+
+	params.PreCondition = fmt.Sprintf("bas_sessions.user_id = %v", userID)
+
+	return p.list(params)
+}
+
+func (p *BasSessionServ) list(params param.Param) (sessions []basmodel.Session,
+	count int64, err error) {
+
+	if sessions, err = p.Repo.List(params); err != nil {
+		glog.CheckError(err, "error in sessions list")
+		return
+	}
+
+	if count, err = p.Repo.Count(params); err != nil {
+		glog.CheckError(err, "error in sessions count")
+	}
+
+	return
+}
+
+// Terminate sign out the session remotely, the owner can terminate it, other users need the
+// manageOthers which is based on the SessionWrite resource
+func (p *BasSessionServ) Terminate(params param.Param, id uint,
+	manageOthers bool) (session basmodel.Session, err error) {
+	if session, err = p.Repo.FindByID(id); err != nil {
+		err = corerr.Tick(err, "E1095068", "can't fetch the session", id)
+		return
+	}
+
+	if session.UserID != params.UserID && !manageOthers {
+		err = limberr.New("session belongs to another user", "E1099954").
+			Message(corerr.YouDontHavePermissionToThisV, basterm.Session).
+			Custom(corerr.ForbiddenErr).Build()
+		return
+	}
+
+	if session.UserID != params.UserID {
+		userServ := ProvideBasUserService(basrepo.ProvideUserRepo(p.Engine))
+		if _, err = userServ.FindInCompany(params, session.UserID); err != nil {
+			return
+		}
+	}
+
+	err = p.terminate(session.ID)
+
+	return
+}
+
+// terminate the session and revoke its refresh tokens, the access tokens are refused by the
+// AuthGuard because of the sid claim
+func (p *BasSessionServ) terminate(id uint) (err error) {
+	if err = p.Repo.Terminate(id); err != nil {
+		err = corerr.Tick(err, "E1063347", "session not terminated", id)
+		return
+	}
+
+	tokenRepo := basrepo.ProvideTokenRepo(p.Engine)
+	if err = tokenRepo.RevokeRefreshBySession(id); err != nil {
+		err = corerr.Tick(err, "E1084684", "refresh tokens of session not revoked", id)
+		return
+	}
+
+	return
+}
+
+// TerminateUser end all sessions of the user, it is used after changing the password or
+// detecting a stolen refresh token
+func (p *BasSessionServ) TerminateUser(userID uint) (err error) {
+	if err = p.Repo.TerminateUser(userID); err != nil {
+		err = corerr.Tick(err, "E1043031", "user's sessions not terminated", userID)
+	}
+
+	return
+}
+
+// PurgeWatcher delete the expired sessions periodically, terminated sessions are kept till
+// their access tokens are expired
+func (p *BasSessionServ) PurgeWatcher() {
+	for range time.Tick(time.Hour) {
+		now := time.Now()
+		terminatedBefore := now.Add(-p.Engine.Envs.ToDuration(base.JWTExpiration) * time.Second)
+		if err := p.Repo.PurgeExpired(now, terminatedBefore); err != nil {
+			glog.LogError(err, "purge expired sessions")
+		}
+	}
+}
+
+func sessionErr(code, msg string) error {
+	return limberr.New(msg, code).
+		Message(basterm.SessionIsTerminatedPleaseLoginAgain).
+		Custom(corerr.UnauthorizedErr).Build()
+}
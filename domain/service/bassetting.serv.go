@@ -54,6 +54,15 @@ func (p *BasSettingServ) List(params param.Param) (settings []basmodel.Setting,
 	return
 }
 
+// Aggregate returns the grouped rows of the settings, it is the aggregate mode of the List
+func (p *BasSettingServ) Aggregate(params param.Param) (rows []map[string]interface{}, err error) {
+	if rows, err = p.Repo.Aggregate(params); err != nil {
+		glog.CheckError(err, "error in settings aggregate")
+	}
+
+	return
+}
+
 // TxCreate is used for creating settings in transaction mode
 func (p *BasSettingServ) TxCreate(db *gorm.DB, setting basmodel.Setting) (u basmodel.Setting, err error) {
 	return p.Repo.TxCreate(db, setting)
@@ -71,6 +80,9 @@ func (p *BasSettingServ) Save(setting basmodel.Setting) (savedSetting, settingBe
 	}
 
 	setting.CreatedAt = settingBefore.CreatedAt
+	if setting.UpdatedAt.IsZero() {
+		setting.UpdatedAt = settingBefore.UpdatedAt
+	}
 
 	if savedSetting, err = p.Repo.Save(setting); err != nil {
 		err = corerr.Tick(err, "E1036118", "error in creating user", setting)
@@ -78,6 +90,7 @@ func (p *BasSettingServ) Save(setting basmodel.Setting) (savedSetting, settingBe
 	}
 
 	corstartoff.LoadSetting(p.Engine)
+	BasLoadPasswordPolicy(p.Engine)
 
 	return
 }
@@ -95,6 +108,7 @@ func (p *BasSettingServ) Update(setting basmodel.Setting) (savedSetting basmodel
 	}
 
 	corstartoff.LoadSetting(p.Engine)
+	BasLoadPasswordPolicy(p.Engine)
 
 	return
 }
@@ -104,6 +118,7 @@ func (p *BasSettingServ) Excel(params param.Param) (settings []basmodel.Setting,
 	params.Limit = p.Engine.Envs.ToInt(core.ExcelMaxRows)
 	params.Offset = 0
 	params.Order = fmt.Sprintf("%v.id ASC", basmodel.SettingTable)
+	params.After, params.Before = "", ""
 
 	if settings, err = p.Repo.List(params); err != nil {
 		err = corerr.Tick(err, "E1086162", "cant generate the excel list for setting")
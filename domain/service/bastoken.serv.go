@@ -0,0 +1,165 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"omono/domain/base"
+	"omono/domain/base/basmodel"
+	"omono/domain/base/basrepo"
+	"omono/internal/core"
+	"omono/internal/core/corerr"
+	"omono/pkg/glog"
+	"omono/pkg/helper/random"
+	"time"
+
+	"github.com/syronz/limberr"
+
+	"gorm.io/gorm"
+)
+
+// BasTokenServ for injecting token basrepo, it handles refresh tokens and revocation
+type BasTokenServ struct {
+	Repo   basrepo.TokenRepo
+	Engine *core.Engine
+}
+
+// ProvideBasTokenService for token is used in wire
+func ProvideBasTokenService(p basrepo.TokenRepo) BasTokenServ {
+	return BasTokenServ{Repo: p, Engine: p.Engine}
+}
+
+// TxCreateRefresh generate a new refresh token for the user and bind it to the access token's jti
+// and the session, the raw token is returned and just the hash is saved
+func (p *BasTokenServ) TxCreateRefresh(db *gorm.DB, userID uint, jti string,
+	sessionID uint, tenant basmodel.Tenant) (token string, err error) {
+	if token, err = random.Token(32); err != nil {
+		err = corerr.TickCustom(err, corerr.InternalServerErr, "E1073758",
+			"refresh token not generated")
+		return
+	}
+
+	refresh := basmodel.RefreshToken{
+		UserID:    userID,
+		Token:     hashToken(token),
+		AccessJTI: jti,
+		SessionID: sessionID,
+		ExpiresAt: time.Now().
+			Add(p.Engine.Envs.ToDuration(base.JWTRefreshExpiration) * time.Second),
+		CompanyID:   tenant.CompanyID,
+		CrossTenant: tenant.CrossTenant,
+	}
+
+	if _, err = p.Repo.TxCreateRefresh(db, refresh); err != nil {
+		err = corerr.Tick(err, "E1034496", "refresh token not saved", userID)
+		return
+	}
+
+	return
+}
+
+// TxConsumeRefresh check the refresh token and revoke it, a new one should be issued by the caller.
+// Using a revoked token means it has been stolen, so all tokens of the user are revoked
+func (p *BasTokenServ) TxConsumeRefresh(db *gorm.DB, token string) (refresh basmodel.RefreshToken, err error) {
+	if refresh, err = p.Repo.TxFindRefresh(db, hashToken(token)); err != nil {
+		err = corerr.Tick(err, "E1062158", "refresh token not found")
+		return
+	}
+
+	if refresh.RevokedAt != nil {
+		glog.CheckError(p.RevokeUser(refresh.UserID), "revoke user's tokens after reuse", refresh.UserID)
+		err = limberr.New("revoked refresh token reused", "E1052250").
+			Message(corerr.TokenIsRevoked).
+			Custom(corerr.UnauthorizedErr).Build()
+		glog.LogError(err, "revoked refresh token has been used again", refresh.UserID)
+		return
+	}
+
+	if refresh.ExpiresAt.Before(time.Now()) {
+		err = limberr.New("refresh token is expired", "E1043203").
+			Message(corerr.TokenIsExpired).
+			Custom(corerr.UnauthorizedErr).Build()
+		return
+	}
+
+	var revoked bool
+	if revoked, err = p.Repo.TxRevokeRefresh(db, refresh.ID); err != nil {
+		err = corerr.Tick(err, "E1031564", "refresh token not revoked", refresh.ID)
+		return
+	}
+
+	if !revoked {
+		err = limberr.New("refresh token used concurrently", "E1082930").
+			Message(corerr.TokenIsRevoked).
+			Custom(corerr.UnauthorizedErr).Build()
+		return
+	}
+
+	return
+}
+
+// Revoke invalidate the access token and the refresh token issued beside it
+func (p *BasTokenServ) Revoke(userID uint, jti string) (err error) {
+	if jti == "" {
+		return
+	}
+
+	revoked := basmodel.RevokedToken{
+		JTI:    jti,
+		UserID: userID,
+		ExpiresAt: time.Now().
+			Add(p.Engine.Envs.ToDuration(base.JWTExpiration) * time.Second),
+	}
+
+	if err = p.Repo.CreateRevoked(revoked); err != nil {
+		err = corerr.Tick(err, "E1099092", "access token not revoked", jti)
+		return
+	}
+
+	if err = p.Repo.RevokeRefreshByJTI(jti); err != nil {
+		err = corerr.Tick(err, "E1064566", "refresh token not revoked", jti)
+		return
+	}
+
+	return
+}
+
+// RevokeUser invalidate all refresh tokens and sessions of the user, the access tokens which
+// have sid are refused by AuthGuard and the rest are valid till their short expiration
+func (p *BasTokenServ) RevokeUser(userID uint) (err error) {
+	if err = p.Repo.RevokeUserRefresh(userID); err != nil {
+		err = corerr.Tick(err, "E1035054", "user's refresh tokens not revoked", userID)
+		return
+	}
+
+	sessionServ := ProvideBasSessionService(basrepo.ProvideSessionRepo(p.Engine))
+	err = sessionServ.TerminateUser(userID)
+
+	return
+}
+
+// IsRevoked is used in AuthGuard for checking the jti of the access token
+func (p *BasTokenServ) IsRevoked(jti string) (revoked bool, err error) {
+	if jti == "" {
+		return
+	}
+
+	if revoked, err = p.Repo.IsRevoked(jti); err != nil {
+		err = corerr.Tick(err, "E1092350", "can't check the revocation list", jti)
+	}
+
+	return
+}
+
+// PurgeWatcher delete expired refresh and revoked tokens periodically
+func (p *BasTokenServ) PurgeWatcher() {
+	for range time.Tick(time.Hour) {
+		if err := p.Repo.PurgeExpired(time.Now()); err != nil {
+			glog.LogError(err, "purge expired tokens")
+		}
+	}
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
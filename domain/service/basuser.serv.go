@@ -5,14 +5,18 @@ import (
 	"omono/domain/base"
 	"omono/domain/base/basmodel"
 	"omono/domain/base/basrepo"
+	"omono/domain/base/basterm"
 	"omono/internal/core"
 	"omono/internal/core/coract"
 	"omono/internal/core/corerr"
+	"omono/internal/core/corterm"
 	"omono/internal/param"
 	"omono/pkg/glog"
 	"omono/pkg/helper/password"
+	"time"
 
 	"github.com/syronz/limberr"
+	"gorm.io/gorm"
 )
 
 // BasUserServ for injecting auth basrepo
@@ -36,6 +40,58 @@ func (p *BasUserServ) FindByID(id uint) (user basmodel.User, err error) {
 		return
 	}
 
+	err = p.fillRoles(&user)
+	return
+}
+
+// fillRoles fetch all roles of the user
+func (p *BasUserServ) fillRoles(user *basmodel.User) (err error) {
+	userRoleRepo := basrepo.ProvideUserRoleRepo(p.Engine)
+	if user.RoleIDs, err = userRoleRepo.RoleIDs(user.ID); err != nil {
+		err = corerr.Tick(err, "E1056509", "can't fetch the roles of the user", user.ID)
+	}
+
+	return
+}
+
+// txReplaceRoles check the existence of the roles and save them for the user
+func (p *BasUserServ) txReplaceRoles(db *gorm.DB, user basmodel.User) (err error) {
+	roleServ := ProvideBasRoleService(basrepo.ProvideRoleRepo(p.Engine))
+	for _, v := range user.RoleIDs {
+		if _, err = roleServ.Repo.FindByID(v); err != nil {
+			err = corerr.Tick(err, "E1045322", "role of the user not found", v)
+			return
+		}
+	}
+
+	userRoleRepo := basrepo.ProvideUserRoleRepo(p.Engine)
+	if err = userRoleRepo.TxReplace(db, user.ID, user.AllRoleIDs()); err != nil {
+		err = corerr.Tick(err, "E1081153", "roles of the user not saved", user.ID)
+	}
+
+	return
+}
+
+// FindInCompany is used for the requests, in the scoped mode the user should be a member of the
+// active company
+func (p *BasUserServ) FindInCompany(params param.Param, id uint) (user basmodel.User, err error) {
+	if user, err = p.FindByID(id); err != nil || !params.CompanyScoped {
+		return
+	}
+
+	userCompanyServ := ProvideBasUserCompanyService(basrepo.ProvideUserCompanyRepo(p.Engine))
+
+	var member bool
+	if member, err = userCompanyServ.IsMember(id, params.CompanyID); err != nil {
+		return
+	}
+
+	if !member {
+		err = limberr.New("user is not a member of the active company", "E1024064").Build()
+		err = corerr.RecordNotFoundHelper(err, "E1060888", corterm.ID, id, basterm.Users)
+		user = basmodel.User{}
+	}
+
 	return
 }
 
@@ -46,6 +102,7 @@ func (p *BasUserServ) FindByUsername(username string) (user basmodel.User, err e
 		return
 	}
 
+	err = p.fillRoles(&user)
 	return
 }
 
@@ -65,8 +122,18 @@ func (p *BasUserServ) List(params param.Param) (users []basmodel.User,
 	return
 }
 
-// Create a user
-func (p *BasUserServ) Create(user basmodel.User) (createdUser basmodel.User, err error) {
+// Aggregate returns the grouped rows of the users, it is the aggregate mode of the List
+func (p *BasUserServ) Aggregate(params param.Param) (rows []map[string]interface{}, err error) {
+	if rows, err = p.Repo.Aggregate(params); err != nil {
+		glog.CheckError(err, "error in users aggregate")
+	}
+
+	return
+}
+
+// Create a user, in the scoped mode the user joins the active company of the creator
+func (p *BasUserServ) Create(params param.Param, user basmodel.User) (createdUser basmodel.User,
+	err error) {
 
 	if err = user.Validate(coract.Create); err != nil {
 		err = corerr.TickValidate(err, "E1043810", "validatation failed in creating user", user)
@@ -78,6 +145,9 @@ func (p *BasUserServ) Create(user basmodel.User) (createdUser basmodel.User, err
 	user.Password, err = password.Hash(user.Password, p.Engine.Envs[base.PasswordSalt])
 	glog.CheckError(err, fmt.Sprintf("Hashing password failed for %+v", user))
 
+	now := time.Now()
+	user.PasswordChangedAt = &now
+
 	if createdUser, err = p.Repo.TxCreate(db, user); err != nil {
 		err = corerr.Tick(err, "E1064180", "error in creating user", user)
 
@@ -85,6 +155,27 @@ func (p *BasUserServ) Create(user basmodel.User) (createdUser basmodel.User, err
 		return
 	}
 
+	passServ := ProvideBasPasswordService(basrepo.ProvidePasswordRepo(p.Engine))
+	if err = passServ.TxAddHistory(db, createdUser.ID, user.Password); err != nil {
+		db.Rollback()
+		return
+	}
+
+	user.ID = createdUser.ID
+	if err = p.txReplaceRoles(db, user); err != nil {
+		db.Rollback()
+		return
+	}
+	createdUser.RoleIDs = user.AllRoleIDs()
+
+	if params.CompanyScoped {
+		userCompanyServ := ProvideBasUserCompanyService(basrepo.ProvideUserCompanyRepo(p.Engine))
+		if err = userCompanyServ.TxJoin(db, createdUser.ID, params.CompanyID); err != nil {
+			db.Rollback()
+			return
+		}
+	}
+
 	db.Commit()
 	createdUser.Password = ""
 
@@ -110,16 +201,31 @@ func (p *BasUserServ) Save(user basmodel.User) (updatedUser, userBefore basmodel
 		}
 	}()
 
-	if user.Password != "" {
+	passServ := ProvideBasPasswordService(basrepo.ProvidePasswordRepo(p.Engine))
+	passwordChanged := user.Password != ""
+
+	if passwordChanged {
+		if err = passServ.TxCheckHistory(db, user.ID, user.Password); err != nil {
+			db.Rollback()
+			return
+		}
+
 		if user.Password, err = password.Hash(user.Password, p.Engine.Envs[base.PasswordSalt]); err != nil {
 			err = corerr.Tick(err, "E1057832", "error in saving user", user)
 		}
+
+		now := time.Now()
+		user.PasswordChangedAt = &now
 	} else {
 		user.Password = userBefore.Password
+		user.PasswordChangedAt = userBefore.PasswordChangedAt
 	}
 
 	userRepo := basrepo.ProvideUserRepo(p.Engine)
 	user.CreatedAt = userBefore.CreatedAt
+	if user.UpdatedAt.IsZero() {
+		user.UpdatedAt = userBefore.UpdatedAt
+	}
 
 	if updatedUser, err = userRepo.TxSave(db, user); err != nil {
 		err = corerr.Tick(err, "E1062983", "error in saving user", user)
@@ -128,10 +234,36 @@ func (p *BasUserServ) Save(user basmodel.User) (updatedUser, userBefore basmodel
 		return
 	}
 
-	BasAccessDeleteFromCache(user.ID)
+	if passwordChanged {
+		if err = passServ.TxAddHistory(db, user.ID, user.Password); err != nil {
+			db.Rollback()
+			return
+		}
+	}
+
+	// in case the role_ids is not sent the other roles are kept and only the primary one is
+	// replaced
+	if user.RoleIDs == nil {
+		for _, v := range userBefore.RoleIDs {
+			if v != userBefore.RoleID {
+				user.RoleIDs = append(user.RoleIDs, v)
+			}
+		}
+	}
+
+	if err = p.txReplaceRoles(db, user); err != nil {
+		db.Rollback()
+		return
+	}
 
 	db.Commit()
 	updatedUser.Password = ""
+	updatedUser.RoleIDs = user.AllRoleIDs()
+
+	// after commit, otherwise a concurrent request may cache the previous roles again
+	if !sameRoles(updatedUser.RoleIDs, userBefore.AllRoleIDs()) {
+		BasAccessDeleteFromCache(user.ID)
+	}
 
 	return
 }
@@ -148,6 +280,42 @@ func (p *BasUserServ) Delete(id uint) (user basmodel.User, err error) {
 		return
 	}
 
+	// the row is kept by the soft delete, so the open sessions of the user are terminated
+	sessionServ := ProvideBasSessionService(basrepo.ProvideSessionRepo(p.Engine))
+	if err = sessionServ.TerminateUser(user.ID); err != nil {
+		return
+	}
+
+	BasAccessDeleteFromCache(user.ID)
+
+	return
+}
+
+// Restore the soft deleted user, it is limited to the members of the active company
+func (p *BasUserServ) Restore(params param.Param, id uint) (user basmodel.User, err error) {
+	if err = p.Repo.Restore(params, id); err != nil {
+		err = corerr.Tick(err, "E1028624", "user not restored", id)
+		return
+	}
+
+	BasAccessDeleteFromCache(id)
+
+	return p.FindByID(id)
+}
+
+// Unlock remove the lockout which is happened because of failed logins
+func (p *BasUserServ) Unlock(id uint) (user basmodel.User, err error) {
+	if user, err = p.FindByID(id); err != nil {
+		err = corerr.Tick(err, "E1010061", "user not found for unlocking")
+		return
+	}
+
+	attemptServ := ProvideBasLoginAttemptService(basrepo.ProvideLoginAttemptRepo(p.Engine))
+	if err = attemptServ.Unlock(user.Username); err != nil {
+		err = corerr.Tick(err, "E1038533", "user not unlocked", id)
+		return
+	}
+
 	return
 }
 
@@ -156,6 +324,7 @@ func (p *BasUserServ) Excel(params param.Param) (users []basmodel.User, err erro
 	params.Limit = p.Engine.Envs.ToInt(core.ExcelMaxRows)
 	params.Offset = 0
 	params.Order = fmt.Sprintf("%v.id ASC", basmodel.UserTable)
+	params.After, params.Before = "", ""
 
 	if users, err = p.Repo.List(params); err != nil {
 		err = corerr.Tick(err, "E1064328", "cant generate the excel list")
@@ -163,3 +332,22 @@ func (p *BasUserServ) Excel(params param.Param) (users []basmodel.User, err erro
 
 	return
 }
+
+// sameRoles compare two lists of roles regardless of their order
+func sameRoles(a, b []uint) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	exist := make(map[uint]bool, len(a))
+	for _, v := range a {
+		exist[v] = true
+	}
+	for _, v := range b {
+		if !exist[v] {
+			return false
+		}
+	}
+
+	return true
+}
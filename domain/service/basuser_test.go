@@ -106,7 +106,7 @@ func TestUserCreate(test *testing.T) {
 	}
 
 	for _, value := range collector {
-		_, err := userService.Create(value.user)
+		_, err := userService.Create(param.New(), value.user)
 		if (value.err == nil && err != nil) || (value.err != nil && err == nil) {
 			test.Errorf("\nERROR FOR :::%+v::: \nRETURNS :::%+v:::, \nIT SHOULD BE :::%+v:::", value.user, err, value.err)
 		}
@@ -0,0 +1,222 @@
+package service
+
+import (
+	"omono/domain/base/basmodel"
+	"omono/domain/base/basrepo"
+	"omono/domain/base/basterm"
+	"omono/domain/segment/segrepo"
+	"omono/internal/core"
+	"omono/internal/core/corerr"
+	"omono/internal/param"
+	"omono/pkg/glog"
+	"time"
+
+	"github.com/syronz/limberr"
+	"gorm.io/gorm"
+)
+
+// BasUserCompanyServ for injecting user company basrepo, it handles the memberships of the users
+// in the companies and resolve the active company of the tokens
+type BasUserCompanyServ struct {
+	Repo   basrepo.UserCompanyRepo
+	Engine *core.Engine
+}
+
+// ProvideBasUserCompanyService for user company is used in wire
+func ProvideBasUserCompanyService(p basrepo.UserCompanyRepo) BasUserCompanyServ {
+	return BasUserCompanyServ{Repo: p, Engine: p.Engine}
+}
+
+// ListByUser returns the companies of the user
+func (p *BasUserCompanyServ) ListByUser(userID uint) (companies []basmodel.UserCompany, err error) {
+	if companies, err = p.Repo.ListByUser(userID); err != nil {
+		err = corerr.Tick(err, "E1092355", "can't fetch the companies of the user", userID)
+		return
+	}
+
+	return
+}
+
+// ListOfUser is used by the admins, in the scoped mode the user should be a member of the
+// active company
+func (p *BasUserCompanyServ) ListOfUser(params param.Param,
+	userID uint) (companies []basmodel.UserCompany, err error) {
+	userServ := ProvideBasUserService(basrepo.ProvideUserRepo(p.Engine))
+	if _, err = userServ.FindInCompany(params, userID); err != nil {
+		return
+	}
+
+	return p.ListByUser(userID)
+}
+
+// IsMember check the membership of the user in the company
+func (p *BasUserCompanyServ) IsMember(userID, companyID uint) (member bool, err error) {
+	if member, err = p.Repo.IsMember(userID, companyID); err != nil {
+		err = corerr.Tick(err, "E1047649", "can't check the membership", userID, companyID)
+		return
+	}
+
+	return
+}
+
+// TxJoin add the user to the company
+func (p *BasUserCompanyServ) TxJoin(db *gorm.DB, userID, companyID uint) (err error) {
+	if err = p.Repo.TxAdd(db, userID, companyID); err != nil {
+		err = corerr.Tick(err, "E1083468", "user not joined the company", userID, companyID)
+		return
+	}
+
+	return
+}
+
+// Resolve returns the tenant which can be issued for the user. The wanted company is kept in
+// case the user is a member of it or is a super admin and the cross tenant mode is kept just for
+// the super admins, otherwise the first company of the user is activated
+func (p *BasUserCompanyServ) Resolve(userID uint, wanted basmodel.Tenant) (tenant basmodel.Tenant,
+	err error) {
+	accessServ := ProvideBasAccessService(basrepo.ProvideAccessRepo(p.Engine))
+
+	var super bool
+	if super, err = accessServ.HasSuperAccess(userID); err != nil {
+		return
+	}
+
+	if wanted.CrossTenant && super {
+		tenant.CrossTenant = true
+		return
+	}
+
+	if wanted.CompanyID != 0 {
+		var member bool
+		if member, err = p.IsMember(userID, wanted.CompanyID); err != nil {
+			return
+		}
+
+		if member || super {
+			tenant.CompanyID = wanted.CompanyID
+			return
+		}
+	}
+
+	var companies []basmodel.UserCompany
+	if companies, err = p.ListByUser(userID); err != nil {
+		return
+	}
+
+	if len(companies) > 0 {
+		tenant.CompanyID = companies[0].CompanyID
+		return
+	}
+
+	// users without any company are limited to the company zero and can't see the tenant's data
+	tenant.CrossTenant = super
+
+	return
+}
+
+// Replace set the companies of the user, in the scoped mode the caller just can add or remove
+// the companies which they are a member of. The updated_at is the version of the user which is
+// fetched before, the companies are not changed in case another request changed the user
+func (p *BasUserCompanyServ) Replace(params param.Param, userID uint, updatedAt time.Time,
+	companyIDs []uint) (companies []basmodel.UserCompany, err error) {
+	var before []basmodel.UserCompany
+	if before, err = p.ListOfUser(params, userID); err != nil {
+		return
+	}
+
+	current := make(map[uint]bool, len(before))
+	for _, v := range before {
+		current[v.CompanyID] = true
+	}
+
+	wanted := make(map[uint]bool, len(companyIDs))
+	var added, removed []uint
+	for _, v := range companyIDs {
+		if wanted[v] {
+			continue
+		}
+		wanted[v] = true
+		if !current[v] {
+			added = append(added, v)
+		}
+	}
+
+	for _, v := range before {
+		if !wanted[v.CompanyID] {
+			removed = append(removed, v.CompanyID)
+		}
+	}
+
+	if err = p.checkChangeable(params, append(added, removed...)); err != nil {
+		return
+	}
+
+	companyServ := ProvideSegCompanyService(segrepo.ProvideCompanyRepo(p.Engine))
+	for _, v := range added {
+		if _, err = companyServ.FindByID(param.New(), v); err != nil {
+			return
+		}
+	}
+
+	db := p.Engine.DB.Begin()
+
+	userRepo := basrepo.ProvideUserRepo(p.Engine)
+	if err = userRepo.TxTouch(db, userID, updatedAt); err != nil {
+		err = corerr.Tick(err, "E1075286", "user is changed before replacing the companies", userID)
+		db.Rollback()
+		return
+	}
+
+	for _, v := range added {
+		if err = p.TxJoin(db, userID, v); err != nil {
+			db.Rollback()
+			return
+		}
+	}
+
+	if len(removed) > 0 {
+		if err = p.Repo.TxRemove(db, userID, removed); err != nil {
+			err = corerr.Tick(err, "E1049333", "user not removed from the companies", userID, removed)
+			db.Rollback()
+			return
+		}
+	}
+
+	db.Commit()
+
+	companies, err = p.ListByUser(userID)
+
+	return
+}
+
+// checkChangeable refuse the companies which the caller is not a member of, it is not applied
+// for the super admin and the cross tenant mode
+func (p *BasUserCompanyServ) checkChangeable(params param.Param, companyIDs []uint) (err error) {
+	if !params.CompanyScoped || len(companyIDs) == 0 {
+		return
+	}
+
+	accessServ := ProvideBasAccessService(basrepo.ProvideAccessRepo(p.Engine))
+
+	var super bool
+	if super, err = accessServ.HasSuperAccess(params.UserID); err != nil || super {
+		return
+	}
+
+	for _, v := range companyIDs {
+		var member bool
+		if member, err = p.IsMember(params.UserID, v); err != nil {
+			return
+		}
+
+		if !member {
+			err = limberr.New("change the membership of other company", "E1028215").
+				Message(basterm.YouCantChangeMembershipOfOtherCompany).
+				Custom(corerr.ForbiddenErr).Build()
+			glog.LogError(err, "membership of company is not changeable", params.UserID, v)
+			return
+		}
+	}
+
+	return
+}
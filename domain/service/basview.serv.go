@@ -0,0 +1,211 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"omono/domain/base/basmodel"
+	"omono/domain/base/basrepo"
+	"omono/domain/base/basterm"
+	"omono/internal/core"
+	"omono/internal/core/coract"
+	"omono/internal/core/corerr"
+	"omono/internal/core/corterm"
+	"omono/internal/param"
+	"omono/pkg/glog"
+
+	"github.com/syronz/limberr"
+)
+
+// BasViewServ for injecting view basrepo, it handles the saved views of the lists
+type BasViewServ struct {
+	Repo   basrepo.ViewRepo
+	Engine *core.Engine
+}
+
+// ProvideBasViewService for view is used in wire
+func ProvideBasViewService(p basrepo.ViewRepo) BasViewServ {
+	return BasViewServ{Repo: p, Engine: p.Engine}
+}
+
+// viewScope limits the views to the views of the user and the views shared with their roles
+const viewScope = "(bas_views.user_id = %v OR bas_views.role_id IN (SELECT " +
+	"bas_user_roles.role_id FROM bas_user_roles WHERE bas_user_roles.user_id = %v))"
+
+// FindByID for getting view by it's id, the view should belong to the user or be shared with
+// one of their roles
+func (p *BasViewServ) FindByID(params param.Param, id uint) (view basmodel.View, err error) {
+	if view, err = p.Repo.FindByID(id); err != nil {
+		err = corerr.Tick(err, "E1023021", "can't fetch the view", id)
+		return
+	}
+
+	if view.UserID == params.UserID {
+		return
+	}
+
+	if view.RoleID == nil || !p.hasRole(params.UserID, *view.RoleID) {
+		err = errors.New("view is not visible for the user")
+		err = corerr.RecordNotFoundHelper(err, "E1081242", corterm.ID, id, basterm.Views)
+		view = basmodel.View{}
+	}
+
+	return
+}
+
+// List of views, it support pagination and search and return back count
+func (p *BasViewServ) List(params param.Param) (views []basmodel.View,
+	count int64, err error) {
+	params.PreCondition = fmt.Sprintf(viewScope, params.UserID, params.UserID)
+
+	if views, err = p.Repo.List(params); err != nil {
+		glog.CheckError(err, "error in views list")
+		return
+	}
+
+	if count, err = p.Repo.Count(params); err != nil {
+		glog.CheckError(err, "error in views count")
+	}
+
+	return
+}
+
+// Aggregate returns the grouped rows of the views, it is the aggregate mode of the List
+func (p *BasViewServ) Aggregate(params param.Param) (rows []map[string]interface{}, err error) {
+	params.PreCondition = fmt.Sprintf(viewScope, params.UserID, params.UserID)
+
+	if rows, err = p.Repo.Aggregate(params); err != nil {
+		glog.CheckError(err, "error in views aggregate")
+	}
+
+	return
+}
+
+// Create a view for the current user
+func (p *BasViewServ) Create(params param.Param,
+	view basmodel.View) (createdView basmodel.View, err error) {
+	view.UserID = params.UserID
+	if err = p.check(params, view); err != nil {
+		err = corerr.TickValidate(err, "E1027626", corerr.ValidationFailed, view)
+		return
+	}
+
+	if createdView, err = p.Repo.Create(view); err != nil {
+		err = corerr.Tick(err, "E1019702", "view not saved", view)
+		return
+	}
+
+	return
+}
+
+// Save a view, just the owner can change it
+func (p *BasViewServ) Save(params param.Param,
+	view basmodel.View) (savedView, viewBefore basmodel.View, err error) {
+	if viewBefore, err = p.owned(params, view.ID); err != nil {
+		return
+	}
+
+	view.UserID = viewBefore.UserID
+	view.CreatedAt = viewBefore.CreatedAt
+	if view.UpdatedAt.IsZero() {
+		view.UpdatedAt = viewBefore.UpdatedAt
+	}
+	if err = p.check(params, view); err != nil {
+		err = corerr.TickValidate(err, "E1053145", corerr.ValidationFailed, view)
+		return
+	}
+
+	if savedView, err = p.Repo.Save(view); err != nil {
+		err = corerr.Tick(err, "E1098616", "view not saved", view)
+		return
+	}
+
+	return
+}
+
+// Delete the view, just the owner can delete it
+func (p *BasViewServ) Delete(params param.Param, id uint) (view basmodel.View, err error) {
+	if view, err = p.owned(params, id); err != nil {
+		return
+	}
+
+	if err = p.Repo.Delete(view); err != nil {
+		err = corerr.Tick(err, "E1048312", "view not deleted", id)
+		return
+	}
+
+	return
+}
+
+// owned returns the view in case the user is its owner, the shared views are read only
+func (p *BasViewServ) owned(params param.Param, id uint) (view basmodel.View, err error) {
+	if view, err = p.FindByID(params, id); err != nil {
+		return
+	}
+
+	if view.UserID != params.UserID {
+		err = limberr.New("view belongs to another user", "E1087606").
+			Message(basterm.OnlyTheOwnerCanChangeTheView).
+			Custom(corerr.ForbiddenErr).Build()
+		view = basmodel.View{}
+	}
+
+	return
+}
+
+// check validates the view, it can be shared only with the roles of the user
+func (p *BasViewServ) check(params param.Param, view basmodel.View) (err error) {
+	err = view.Validate(coract.Save)
+
+	if view.RoleID != nil && !p.hasRole(params.UserID, *view.RoleID) {
+		err = limberr.AddInvalidParam(err, "role_id",
+			basterm.YouCanShareTheViewOnlyWithYourRoles)
+	}
+
+	return
+}
+
+func (p *BasViewServ) hasRole(userID, roleID uint) bool {
+	userRoleRepo := basrepo.ProvideUserRoleRepo(p.Engine)
+	roleIDs, err := userRoleRepo.RoleIDs(userID)
+	if err != nil {
+		glog.CheckError(err, "error in fetching the roles of the user")
+		return false
+	}
+
+	for _, v := range roleIDs {
+		if v == roleID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Param returns the saved part of the param, it is used by the middleware of the views. The
+// view should belong to the entity of the list
+func (p *BasViewServ) Param(params param.Param, id uint,
+	entity string) (view param.View, err error) {
+	var saved basmodel.View
+	if saved, err = p.FindByID(params, id); err != nil {
+		return
+	}
+
+	if saved.Entity != entity {
+		err = limberr.New("view belongs to another entity", "E1057219").
+			Message(corerr.ValidationFailed).
+			Custom(corerr.ValidationFailedErr).Build()
+		err = limberr.AddInvalidParam(err, "view",
+			"view %v doesn't belong to the %v", id, entity)
+		return
+	}
+
+	view = param.View{
+		Filter:   saved.Filter,
+		Search:   saved.Search,
+		Select:   saved.Select,
+		Sort:     saved.Sort,
+		PageSize: saved.PageSize,
+	}
+
+	return
+}
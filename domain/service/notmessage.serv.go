@@ -77,6 +77,35 @@ func (p *NotMessageServ) FindByHash(hash uint64) (message notmodel.Message, err
 func (p *NotMessageServ) List(params param.Param, scope string) (messages []notmodel.Message,
 	count int64, err error) {
 
+	scopeMessages(&params, scope)
+
+	if messages, err = p.Repo.List(params); err != nil {
+		glog.CheckError(err, "error in messages list")
+		return
+	}
+
+	if count, err = p.Repo.Count(params); err != nil {
+		glog.CheckError(err, "error in messages count")
+	}
+
+	return
+}
+
+// Aggregate returns the grouped rows of the messages, it is the aggregate mode of the List
+func (p *NotMessageServ) Aggregate(params param.Param,
+	scope string) (rows []map[string]interface{}, err error) {
+
+	scopeMessages(&params, scope)
+
+	if rows, err = p.Repo.Aggregate(params); err != nil {
+		glog.CheckError(err, "error in messages aggregate")
+	}
+
+	return
+}
+
+// scopeMessages limits the messages to the received, new or sent messages of the user
+func scopeMessages(params *param.Param, scope string) {
 	switch scope {
 	case "":
 		fallthrough
@@ -89,17 +118,6 @@ func (p *NotMessageServ) List(params param.Param, scope string) (messages []notm
 		params.PreCondition = fmt.Sprintf(" not_messages.created_by = '%v' ",
 			params.UserID)
 	}
-
-	if messages, err = p.Repo.List(params); err != nil {
-		glog.CheckError(err, "error in messages list")
-		return
-	}
-
-	if count, err = p.Repo.Count(params); err != nil {
-		glog.CheckError(err, "error in messages count")
-	}
-
-	return
 }
 
 // Create a message
@@ -157,11 +175,22 @@ func (p *NotMessageServ) Delete(id uint) (message notmodel.Message, err error) {
 	return
 }
 
+// Restore the soft deleted message
+func (p *NotMessageServ) Restore(id uint) (message notmodel.Message, err error) {
+	if err = p.Repo.Restore(id); err != nil {
+		err = corerr.Tick(err, "E1083136", "message not restored", id)
+		return
+	}
+
+	return p.FindByID(id)
+}
+
 // Excel is used for export excel file
 func (p *NotMessageServ) Excel(params param.Param) (messages []notmodel.Message, err error) {
 	params.Limit = p.Engine.Envs.ToInt(core.ExcelMaxRows)
 	params.Offset = 0
 	params.Order = fmt.Sprintf("%v.id ASC", notmodel.MessageTable)
+	params.After, params.Before = "", ""
 
 	if messages, err = p.Repo.List(params); err != nil {
 		err = corerr.Tick(err, "E8229024", "cant generate the excel list for messages")
@@ -2,6 +2,7 @@ package service
 
 import (
 	"fmt"
+	"omono/domain/base/basrepo"
 	"omono/domain/segment/segmodel"
 	"omono/domain/segment/segrepo"
 	"omono/internal/core"
@@ -27,9 +28,9 @@ func ProvideSegCompanyService(p segrepo.CompanyRepo) SegCompanyServ {
 	}
 }
 
-// FindByID for getting company by it's id
-func (p *SegCompanyServ) FindByID(id uint) (company segmodel.Company, err error) {
-	if company, err = p.Repo.FindByID(id); err != nil {
+// FindByID for getting company by it's id, in the scoped mode just the active company is found
+func (p *SegCompanyServ) FindByID(params param.Param, id uint) (company segmodel.Company, err error) {
+	if company, err = p.Repo.FindByID(params, id); err != nil {
 		err = corerr.Tick(err, "E1049049", "can't fetch the company", id)
 		return
 	}
@@ -63,8 +64,18 @@ func (p *SegCompanyServ) List(params param.Param) (companies []segmodel.Company,
 	return
 }
 
-// Create a company
-func (p *SegCompanyServ) Create(company segmodel.Company) (createdCompany segmodel.Company, err error) {
+// Aggregate returns the grouped rows of the companies, it is the aggregate mode of the List
+func (p *SegCompanyServ) Aggregate(params param.Param) (rows []map[string]interface{}, err error) {
+	if rows, err = p.Repo.Aggregate(params); err != nil {
+		glog.CheckError(err, "error in companies aggregate")
+	}
+
+	return
+}
+
+// Create a company, the creator joins it for being able to switch to it
+func (p *SegCompanyServ) Create(params param.Param,
+	company segmodel.Company) (createdCompany segmodel.Company, err error) {
 	db := p.Engine.DB.Begin()
 
 	defer func() {
@@ -75,13 +86,21 @@ func (p *SegCompanyServ) Create(company segmodel.Company) (createdCompany segmod
 		}
 	}()
 
-	if createdCompany, err = p.TxCreate(p.Repo.Engine.DB, company); err != nil {
+	if createdCompany, err = p.TxCreate(db, company); err != nil {
 		err = corerr.Tick(err, "E1014394", "error in creating company for user", createdCompany)
 
 		db.Rollback()
 		return
 	}
 
+	if params.UserID != 0 {
+		userCompanyServ := ProvideBasUserCompanyService(basrepo.ProvideUserCompanyRepo(p.Engine))
+		if err = userCompanyServ.TxJoin(db, params.UserID, createdCompany.ID); err != nil {
+			db.Rollback()
+			return
+		}
+	}
+
 	db.Commit()
 
 	return
@@ -103,13 +122,17 @@ func (p *SegCompanyServ) TxCreate(db *gorm.DB, company segmodel.Company) (create
 }
 
 // Save a company, if it is exist update it, if not create it
-func (p *SegCompanyServ) Save(company segmodel.Company) (savedCompany, companyBefore segmodel.Company, err error) {
-	if companyBefore, err = p.FindByID(company.ID); err != nil {
+func (p *SegCompanyServ) Save(params param.Param,
+	company segmodel.Company) (savedCompany, companyBefore segmodel.Company, err error) {
+	if companyBefore, err = p.FindByID(params, company.ID); err != nil {
 		err = corerr.Tick(err, "E1073641", "company not exist")
 		return
 	}
 
 	company.CreatedAt = companyBefore.CreatedAt
+	if company.UpdatedAt.IsZero() {
+		company.UpdatedAt = companyBefore.UpdatedAt
+	}
 
 	savedCompany, err = p.TxSave(p.Engine.DB, company)
 	return
@@ -131,8 +154,8 @@ func (p *SegCompanyServ) TxSave(db *gorm.DB, company segmodel.Company) (savedCom
 }
 
 // Delete company, it is soft delete
-func (p *SegCompanyServ) Delete(id uint) (company segmodel.Company, err error) {
-	if company, err = p.FindByID(id); err != nil {
+func (p *SegCompanyServ) Delete(params param.Param, id uint) (company segmodel.Company, err error) {
+	if company, err = p.FindByID(params, id); err != nil {
 		err = corerr.Tick(err, "E1038835", "company not found for deleting")
 		return
 	}
@@ -145,11 +168,22 @@ func (p *SegCompanyServ) Delete(id uint) (company segmodel.Company, err error) {
 	return
 }
 
+// Restore the soft deleted company, it is limited to the active company of the params
+func (p *SegCompanyServ) Restore(params param.Param, id uint) (company segmodel.Company, err error) {
+	if err = p.Repo.Restore(params, id); err != nil {
+		err = corerr.Tick(err, "E1084801", "company not restored", id)
+		return
+	}
+
+	return p.FindByID(params, id)
+}
+
 // Excel is used for export excel file
 func (p *SegCompanyServ) Excel(params param.Param) (companies []segmodel.Company, err error) {
 	params.Limit = p.Engine.Envs.ToInt(core.ExcelMaxRows)
 	params.Offset = 0
 	params.Order = fmt.Sprintf("%v.id ASC", segmodel.CompanyTable)
+	params.After, params.Before = "", ""
 
 	if companies, err = p.Repo.List(params); err != nil {
 		err = corerr.Tick(err, "E1023076", "cant generate the excel list for companies")
@@ -2,6 +2,7 @@ package service
 
 import (
 	"fmt"
+	"omono/domain/segment/segterm"
 	"omono/domain/subscriber/enum/accountstatus"
 	"omono/domain/subscriber/submodel"
 	"omono/domain/subscriber/subrepo"
@@ -11,6 +12,8 @@ import (
 	"omono/internal/param"
 	"omono/pkg/glog"
 
+	"github.com/syronz/dict"
+	"github.com/syronz/limberr"
 	"gorm.io/gorm"
 )
 
@@ -30,9 +33,9 @@ func ProvideSubAccountService(p subrepo.AccountRepo, phoneServ SubPhoneServ) Sub
 	}
 }
 
-// FindByID for getting account by it's id
-func (p *SubAccountServ) FindByID(id uint) (account submodel.Account, err error) {
-	if account, err = p.Repo.FindByID(id); err != nil {
+// FindByID for getting account by it's id, it is limited to the active company of the params
+func (p *SubAccountServ) FindByID(params param.Param, id uint) (account submodel.Account, err error) {
+	if account, err = p.Repo.FindByID(params, id); err != nil {
 		err = corerr.Tick(err, "E1049049", "can't fetch the account", id)
 		return
 	}
@@ -71,8 +74,30 @@ func (p *SubAccountServ) List(params param.Param) (accounts []submodel.Account,
 	return
 }
 
-// Create a account
-func (p *SubAccountServ) Create(account submodel.Account) (createdAccount submodel.Account, err error) {
+// Aggregate returns the grouped rows of the accounts, it is the aggregate mode of the List
+func (p *SubAccountServ) Aggregate(params param.Param) (rows []map[string]interface{}, err error) {
+	if rows, err = p.Repo.Aggregate(params); err != nil {
+		glog.CheckError(err, "error in accounts aggregate")
+	}
+
+	return
+}
+
+// Create a account, in the scoped mode it belongs to the active company and in the cross tenant
+// mode the company should be determined
+func (p *SubAccountServ) Create(params param.Param,
+	account submodel.Account) (createdAccount submodel.Account, err error) {
+	if params.CompanyScoped {
+		account.CompanyID = params.CompanyID
+	}
+
+	if account.CompanyID == 0 {
+		err = limberr.AddInvalidParam(nil, "company_id",
+			corerr.VisRequired, dict.R(segterm.Company))
+		err = corerr.TickValidate(err, "E1053291", "company of the account is required", account)
+		return
+	}
+
 	db := p.Engine.DB.Begin()
 
 	defer func() {
@@ -83,7 +108,7 @@ func (p *SubAccountServ) Create(account submodel.Account) (createdAccount submod
 		}
 	}()
 
-	if createdAccount, err = p.TxCreate(p.Repo.Engine.DB, account); err != nil {
+	if createdAccount, err = p.TxCreate(db, account); err != nil {
 		err = corerr.Tick(err, "E1014394", "error in creating account for user", createdAccount)
 
 		db.Rollback()
@@ -119,14 +144,22 @@ func (p *SubAccountServ) TxCreate(db *gorm.DB, account submodel.Account) (create
 	return
 }
 
-// Save a account, if it is exist update it, if not create it
-func (p *SubAccountServ) Save(account submodel.Account) (savedAccount, accountBefore submodel.Account, err error) {
-	if accountBefore, err = p.FindByID(account.ID); err != nil {
+// Save a account, if it is exist update it, if not create it. The account can't be moved to
+// another company in the scoped mode
+func (p *SubAccountServ) Save(params param.Param,
+	account submodel.Account) (savedAccount, accountBefore submodel.Account, err error) {
+	if accountBefore, err = p.FindByID(params, account.ID); err != nil {
 		err = corerr.Tick(err, "E1073641", "account not exist")
 		return
 	}
 
 	account.CreatedAt = accountBefore.CreatedAt
+	if account.UpdatedAt.IsZero() {
+		account.UpdatedAt = accountBefore.UpdatedAt
+	}
+	if params.CompanyScoped || account.CompanyID == 0 {
+		account.CompanyID = accountBefore.CompanyID
+	}
 
 	savedAccount, err = p.TxSave(p.Engine.DB, account)
 	return
@@ -148,8 +181,8 @@ func (p *SubAccountServ) TxSave(db *gorm.DB, account submodel.Account) (savedAcc
 }
 
 // Delete account, it is soft delete
-func (p *SubAccountServ) Delete(id uint) (account submodel.Account, err error) {
-	if account, err = p.FindByID(id); err != nil {
+func (p *SubAccountServ) Delete(params param.Param, id uint) (account submodel.Account, err error) {
+	if account, err = p.FindByID(params, id); err != nil {
 		err = corerr.Tick(err, "E1038835", "account not found for deleting")
 		return
 	}
@@ -162,11 +195,22 @@ func (p *SubAccountServ) Delete(id uint) (account submodel.Account, err error) {
 	return
 }
 
+// Restore the soft deleted account, it is limited to the active company of the params
+func (p *SubAccountServ) Restore(params param.Param, id uint) (account submodel.Account, err error) {
+	if err = p.Repo.Restore(params, id); err != nil {
+		err = corerr.Tick(err, "E1057517", "account not restored", id)
+		return
+	}
+
+	return p.FindByID(params, id)
+}
+
 // Excel is used for export excel file
 func (p *SubAccountServ) Excel(params param.Param) (accounts []submodel.Account, err error) {
 	params.Limit = p.Engine.Envs.ToInt(core.ExcelMaxRows)
 	params.Offset = 0
 	params.Order = fmt.Sprintf("%v.id ASC", submodel.AccountTable)
+	params.After, params.Before = "", ""
 
 	if accounts, err = p.Repo.List(params); err != nil {
 		err = corerr.Tick(err, "E1023076", "cant generate the excel list for accounts")
@@ -177,10 +221,10 @@ func (p *SubAccountServ) Excel(params param.Param) (accounts []submodel.Account,
 }
 
 // IsActive check the status of an account
-func (p *SubAccountServ) IsActive(id uint) (bool, submodel.Account, error) {
+func (p *SubAccountServ) IsActive(params param.Param, id uint) (bool, submodel.Account, error) {
 	var account submodel.Account
 	var err error
-	if account, err = p.FindByID(id); err != nil {
+	if account, err = p.FindByID(params, id); err != nil {
 		return false, account, corerr.Tick(err, "E1059307", "account not exist", id)
 	}
 
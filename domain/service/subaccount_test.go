@@ -34,16 +34,17 @@ func TestAccountCreate(t *testing.T) {
 	}{
 		{
 			in: submodel.Account{
-				NameEn: "child 1 of asset ",
-				NameKu: helper.StrPointer("3"),
-				Type:   accounttype.VIP,
-				Status: accountstatus.Active,
+				CompanyID: 1,
+				NameEn:    "child 1 of asset ",
+				NameKu:    helper.StrPointer("3"),
+				Type:      accounttype.VIP,
+				Status:    accountstatus.Active,
 			},
 			err: nil,
 		},
 	}
 	for _, v := range samples {
-		_, err := accountServ.Create(v.in)
+		_, err := accountServ.Create(param.New(), v.in)
 		if (v.err == nil && err != nil) || (v.err != nil && err == nil) {
 			t.Errorf("\nERROR FOR :::%+v::: \nRETURNS :::%+v:::, \nIT SHOULD BE :::%+v:::", v.in, err, v.err)
 		}
@@ -55,7 +56,7 @@ func TestAccountDelete(t *testing.T) {
 
 	var id uint = 21
 
-	if _, err := testAccountServ.Delete(id); err != nil {
+	if _, err := testAccountServ.Delete(param.New(), id); err != nil {
 		t.Errorf("there is an error for deleting the account, %v", err.Error())
 	}
 }
@@ -114,7 +115,7 @@ func TestAccountUpdate(t *testing.T) {
 		},
 	}
 	for _, v := range samples {
-		_, _, err := accountServ.Save(v.in)
+		_, _, err := accountServ.Save(param.New(), v.in)
 		if (v.err == nil && err != nil) || (v.err != nil && err == nil) {
 			t.Errorf("\nERROR FOR :::%+v::: \nRETURNS :::%+v:::, \nIT SHOULD BE :::%+v:::", v.in, err, v.err)
 		}
@@ -29,9 +29,10 @@ func ProvideSubPhoneService(p subrepo.PhoneRepo) SubPhoneServ {
 	}
 }
 
-// FindByID for getting phone by it's id
-func (p *SubPhoneServ) FindByID(id uint) (phone submodel.Phone, err error) {
-	if phone, err = p.Repo.FindByID(id); err != nil {
+// FindByID for getting phone by it's id, in the scoped mode the phone should be joined to an
+// account of the active company
+func (p *SubPhoneServ) FindByID(params param.Param, id uint) (phone submodel.Phone, err error) {
+	if phone, err = p.Repo.FindByID(params, id); err != nil {
 		err = corerr.Tick(err, "E1057387", "can't fetch the phone", id)
 		return
 	}
@@ -78,12 +79,40 @@ func (p *SubPhoneServ) List(params param.Param) (phones []submodel.Phone,
 	return
 }
 
-// Create a phone
-func (p *SubPhoneServ) Create(phone submodel.Phone) (createdPhone submodel.Phone, err error) {
+// Aggregate returns the grouped rows of the phones, it is the aggregate mode of the List
+func (p *SubPhoneServ) Aggregate(params param.Param) (rows []map[string]interface{}, err error) {
+	if rows, err = p.Repo.Aggregate(params); err != nil {
+		glog.CheckError(err, "error in phones aggregate")
+	}
+
+	return
+}
+
+// Create a phone, the account of the phone should be in the active company
+func (p *SubPhoneServ) Create(params param.Param,
+	phone submodel.Phone) (createdPhone submodel.Phone, err error) {
+	if err = p.checkAccount(params, phone.AccountID); err != nil {
+		return
+	}
 
 	return p.TxCreate(p.Repo.Engine.DB, phone)
 }
 
+// checkAccount refuse the accounts which are not in the active company
+func (p *SubPhoneServ) checkAccount(params param.Param, accountID uint) (err error) {
+	if !params.CompanyScoped {
+		return
+	}
+
+	accountRepo := subrepo.ProvideAccountRepo(p.Engine)
+	if _, err = accountRepo.FindByID(params, accountID); err != nil {
+		err = corerr.Tick(err, "E1037497", "account of the phone is not in the company", accountID)
+		return
+	}
+
+	return
+}
+
 // TxCreate used in case of transaction activated
 func (p *SubPhoneServ) TxCreate(db *gorm.DB, phone submodel.Phone) (createdPhone submodel.Phone, err error) {
 	if err = phone.Validate(coract.Save); err != nil {
@@ -129,19 +158,23 @@ func (p *SubPhoneServ) TxCreate(db *gorm.DB, phone submodel.Phone) (createdPhone
 }
 
 // Save a phone, if it is exist update it, if not create it
-func (p *SubPhoneServ) Save(phone submodel.Phone) (savedPhone submodel.Phone, err error) {
+func (p *SubPhoneServ) Save(params param.Param, phone submodel.Phone) (savedPhone submodel.Phone,
+	err error) {
 	if err = phone.Validate(coract.Save); err != nil {
 		err = corerr.TickValidate(err, "E1031666", corerr.ValidationFailed, phone)
 		return
 	}
 
 	var phoneBefore submodel.Phone
-	if phoneBefore, err = p.FindByID(phone.ID); err != nil {
+	if phoneBefore, err = p.FindByID(params, phone.ID); err != nil {
 		err = corerr.Tick(err, "E1082861", "phone not exist")
 		return
 	}
 
 	phone.CreatedAt = phoneBefore.CreatedAt
+	if phone.UpdatedAt.IsZero() {
+		phone.UpdatedAt = phoneBefore.UpdatedAt
+	}
 
 	if savedPhone, err = p.Repo.Save(phone); err != nil {
 		err = corerr.Tick(err, "E1031295", "phone not saved")
@@ -152,8 +185,8 @@ func (p *SubPhoneServ) Save(phone submodel.Phone) (savedPhone submodel.Phone, er
 }
 
 // Delete phone, it is soft delete
-func (p *SubPhoneServ) Delete(id uint) (phone submodel.Phone, err error) {
-	if phone, err = p.FindByID(id); err != nil {
+func (p *SubPhoneServ) Delete(params param.Param, id uint) (phone submodel.Phone, err error) {
+	if phone, err = p.FindByID(params, id); err != nil {
 		err = corerr.Tick(err, "E1044187", "phone not found for deleting")
 		return
 	}
@@ -166,13 +199,28 @@ func (p *SubPhoneServ) Delete(id uint) (phone submodel.Phone, err error) {
 	return
 }
 
+// Restore the soft deleted phone, it is limited to the active company of the params
+func (p *SubPhoneServ) Restore(params param.Param, id uint) (phone submodel.Phone, err error) {
+	if err = p.Repo.Restore(params, id); err != nil {
+		err = corerr.Tick(err, "E1022133", "phone not restored", id)
+		return
+	}
+
+	return p.FindByID(params, id)
+}
+
 // Separate phone, it is soft delete
-func (p *SubPhoneServ) Separate(id uint) (aPhone submodel.AccountPhone, err error) {
+func (p *SubPhoneServ) Separate(params param.Param, id uint) (aPhone submodel.AccountPhone,
+	err error) {
 	if aPhone, err = p.Repo.FindAccountPhoneByID(id); err != nil {
 		err = corerr.Tick(err, "E1049677", "account-phone not found for deleting")
 		return
 	}
 
+	if err = p.checkAccount(params, aPhone.AccountID); err != nil {
+		return
+	}
+
 	if err = p.Repo.SeparateAccountPhone(aPhone); err != nil {
 		err = corerr.Tick(err, "E1040009", "account-phone not deleted")
 		return
@@ -186,6 +234,7 @@ func (p *SubPhoneServ) Excel(params param.Param) (phones []submodel.Phone, err e
 	params.Limit = p.Engine.Envs.ToInt(core.ExcelMaxRows)
 	params.Offset = 0
 	params.Order = fmt.Sprintf("%v.id ASC", submodel.PhoneTable)
+	params.After, params.Before = "", ""
 
 	if phones, err = p.Repo.List(params); err != nil {
 		err = corerr.Tick(err, "E1066621", "cant generate the excel list for phones")
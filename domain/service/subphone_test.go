@@ -77,7 +77,7 @@ func TestPhoneCreate(test *testing.T) {
 	}
 
 	for _, value := range testCollector {
-		_, err := phoneService.Create(value.phone)
+		_, err := phoneService.Create(param.New(), value.phone)
 		if (value.err == nil && err != nil) || (value.err != nil && err == nil) {
 			test.Errorf("\nERROR FOR :::%+v::: \nRETURNS :::%+v:::, \nIT SHOULD BE :::%+v:::", value.phone, err, value.err)
 		}
@@ -127,7 +127,7 @@ func TestPhoneSave(test *testing.T) {
 
 	for _, value := range collector {
 
-		_, err := phoneService.Save(value.phone)
+		_, err := phoneService.Save(param.New(), value.phone)
 		if (value.err == nil && err != nil) || (value.err != nil && err == nil) {
 			test.Errorf("\nERROR FOR :::%+v::: \nRETURNS :::%+v:::, \nIT SHOULD BE :::%+v:::", value.phone, err, value.err)
 		}
@@ -35,13 +35,13 @@ func (p *AccountAPI) FindByID(c *gin.Context) {
 		return
 	}
 
-	if account, err = p.Service.FindByID(id); err != nil {
+	if account, err = p.Service.FindByID(resp.Params(submodel.AccountTable), id); err != nil {
 		resp.Error(err).JSON()
 		return
 	}
 
 	resp.Record(subscriber.ViewAccount)
-	resp.Status(http.StatusOK).
+	resp.SetETag(account.Model).Status(http.StatusOK).
 		MessageT(corterm.VInfo, basterm.Account).
 		JSON(account)
 }
@@ -53,7 +53,13 @@ func (p *AccountAPI) List(c *gin.Context) {
 	data := make(map[string]interface{})
 	var err error
 
-	if data["list"], data["count"], err = p.Service.List(params); err != nil {
+	if params.Aggregated() {
+		data["list"], err = p.Service.Aggregate(params)
+	} else {
+		data["list"], data["count"], err = p.Service.List(params)
+	}
+
+	if err != nil {
 		resp.Error(err).JSON()
 		return
 	}
@@ -61,6 +67,7 @@ func (p *AccountAPI) List(c *gin.Context) {
 	resp.Record(subscriber.ListAccount)
 	resp.Status(http.StatusOK).
 		MessageT(corterm.ListOfV, basterm.Accounts).
+		Extra(params.Keyset(data["list"], p.Service.Repo.Cols)).
 		JSON(data)
 }
 
@@ -74,13 +81,13 @@ func (p *AccountAPI) Create(c *gin.Context) {
 		return
 	}
 
-	if createdAccount, err = p.Service.Create(account); err != nil {
+	if createdAccount, err = p.Service.Create(resp.Params(submodel.AccountTable), account); err != nil {
 		resp.Error(err).JSON()
 		return
 	}
 
 	// resp.RecordCreate(subscriber.CreateAccount, account)
-	resp.Status(http.StatusOK).
+	resp.SetETag(createdAccount.Model).Status(http.StatusOK).
 		MessageT(corterm.VCreatedSuccessfully, basterm.Account).
 		JSON(createdAccount)
 }
@@ -97,19 +104,29 @@ func (p *AccountAPI) Update(c *gin.Context) {
 		return
 	}
 
-	if err = resp.Bind(&account, "E1086162", subscriber.Domain, basterm.Account); err != nil {
+	if accountBefore, err = p.Service.FindByID(resp.Params(submodel.AccountTable), id); err != nil {
+		resp.Error(err).JSON()
+		return
+	}
+
+	if err = resp.IfMatch(accountBefore.Model, accountBefore, "E1083208", basterm.Account); err != nil {
+		return
+	}
+
+	if err = resp.BindUpdate(&account, accountBefore, "E1086162", subscriber.Domain, basterm.Account); err != nil {
 		return
 	}
 
 	account.ID = id
 	account.CreatedAt = accountBefore.CreatedAt
-	if accountUpdated, accountBefore, err = p.Service.Save(account); err != nil {
+	account.UpdatedAt = accountBefore.UpdatedAt
+	if accountUpdated, accountBefore, err = p.Service.Save(resp.Params(submodel.AccountTable), account); err != nil {
 		resp.Error(err).JSON()
 		return
 	}
 
-	resp.Record(subscriber.UpdateAccount, accountBefore, account)
-	resp.Status(http.StatusOK).
+	resp.Record(subscriber.UpdateAccount, accountBefore, accountUpdated)
+	resp.SetETag(accountUpdated.Model).Status(http.StatusOK).
 		MessageT(corterm.VUpdatedSuccessfully, basterm.Account).
 		JSON(accountUpdated)
 }
@@ -125,7 +142,7 @@ func (p *AccountAPI) Delete(c *gin.Context) {
 		return
 	}
 
-	if account, err = p.Service.Delete(id); err != nil {
+	if account, err = p.Service.Delete(resp.Params(submodel.AccountTable), id); err != nil {
 		resp.Error(err).JSON()
 		return
 	}
@@ -136,6 +153,28 @@ func (p *AccountAPI) Delete(c *gin.Context) {
 		JSON()
 }
 
+// Restore the deleted account
+func (p *AccountAPI) Restore(c *gin.Context) {
+	resp := response.New(p.Engine, c, subscriber.Domain)
+	var err error
+	var account submodel.Account
+	var id uint
+
+	if id, err = resp.GetID(c.Param("accountID"), "E1023356", basterm.Account); err != nil {
+		return
+	}
+
+	if account, err = p.Service.Restore(resp.Params(submodel.AccountTable), id); err != nil {
+		resp.Error(err).JSON()
+		return
+	}
+
+	resp.Record(subscriber.RestoreAccount, account)
+	resp.Status(http.StatusOK).
+		MessageT(corterm.VRestoredSuccessfully, basterm.Account).
+		JSON(account)
+}
+
 // Excel generate excel files based on search
 func (p *AccountAPI) Excel(c *gin.Context) {
 	resp, params := response.NewParam(p.Engine, c, basterm.Accounts, subscriber.Domain)
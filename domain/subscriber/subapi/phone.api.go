@@ -37,13 +37,13 @@ func (p *PhoneAPI) FindByID(c *gin.Context) {
 		return
 	}
 
-	if phone, err = p.Service.FindByID(id); err != nil {
+	if phone, err = p.Service.FindByID(resp.Params(submodel.PhoneTable), id); err != nil {
 		resp.Error(err).JSON()
 		return
 	}
 
 	resp.Record(subscriber.ViewPhone)
-	resp.Status(http.StatusOK).
+	resp.SetETag(phone.Model).Status(http.StatusOK).
 		MessageT(corterm.VInfo, basterm.Phone).
 		JSON(phone)
 }
@@ -55,7 +55,13 @@ func (p *PhoneAPI) List(c *gin.Context) {
 	data := make(map[string]interface{})
 	var err error
 
-	if data["list"], data["count"], err = p.Service.List(params); err != nil {
+	if params.Aggregated() {
+		data["list"], err = p.Service.Aggregate(params)
+	} else {
+		data["list"], data["count"], err = p.Service.List(params)
+	}
+
+	if err != nil {
 		resp.Error(err).JSON()
 		return
 	}
@@ -63,6 +69,7 @@ func (p *PhoneAPI) List(c *gin.Context) {
 	resp.Record(subscriber.ListPhone)
 	resp.Status(http.StatusOK).
 		MessageT(corterm.ListOfV, basterm.Phones).
+		Extra(params.Keyset(data["list"], p.Service.Repo.Cols)).
 		JSON(data)
 }
 
@@ -76,13 +83,13 @@ func (p *PhoneAPI) Create(c *gin.Context) {
 		return
 	}
 
-	if createdPhone, err = p.Service.Create(phone); err != nil {
+	if createdPhone, err = p.Service.Create(resp.Params(submodel.PhoneTable), phone); err != nil {
 		resp.Error(err).JSON()
 		return
 	}
 
 	resp.RecordCreate(subscriber.CreatePhone, phone)
-	resp.Status(http.StatusOK).
+	resp.SetETag(createdPhone.Model).Status(http.StatusOK).
 		MessageT(corterm.VCreatedSuccessfully, basterm.Phone).
 		JSON(createdPhone)
 }
@@ -99,23 +106,28 @@ func (p *PhoneAPI) Update(c *gin.Context) {
 		return
 	}
 
-	if err = resp.Bind(&phone, "E1073908", subscriber.Domain, basterm.Phone); err != nil {
+	if phoneBefore, err = p.Service.FindByID(resp.Params(submodel.PhoneTable), id); err != nil {
+		resp.Error(err).JSON()
 		return
 	}
 
-	if phoneBefore, err = p.Service.FindByID(id); err != nil {
-		resp.Error(err).JSON()
+	if err = resp.IfMatch(phoneBefore.Model, phoneBefore, "E1094645", basterm.Phone); err != nil {
+		return
+	}
+
+	if err = resp.BindUpdate(&phone, phoneBefore, "E1073908", subscriber.Domain, basterm.Phone); err != nil {
 		return
 	}
 
 	phone.ID = id
-	if phoneUpdated, err = p.Service.Save(phone); err != nil {
+	phone.UpdatedAt = phoneBefore.UpdatedAt
+	if phoneUpdated, err = p.Service.Save(resp.Params(submodel.PhoneTable), phone); err != nil {
 		resp.Error(err).JSON()
 		return
 	}
 
-	resp.Record(subscriber.UpdatePhone, phoneBefore, phone)
-	resp.Status(http.StatusOK).
+	resp.Record(subscriber.UpdatePhone, phoneBefore, phoneUpdated)
+	resp.SetETag(phoneUpdated.Model).Status(http.StatusOK).
 		MessageT(corterm.VUpdatedSuccessfully, basterm.Phone).
 		JSON(phoneUpdated)
 }
@@ -131,7 +143,7 @@ func (p *PhoneAPI) Delete(c *gin.Context) {
 		return
 	}
 
-	if phone, err = p.Service.Delete(id); err != nil {
+	if phone, err = p.Service.Delete(resp.Params(submodel.PhoneTable), id); err != nil {
 		resp.Error(err).JSON()
 		return
 	}
@@ -142,6 +154,28 @@ func (p *PhoneAPI) Delete(c *gin.Context) {
 		JSON()
 }
 
+// Restore the deleted phone
+func (p *PhoneAPI) Restore(c *gin.Context) {
+	resp := response.New(p.Engine, c, subscriber.Domain)
+	var err error
+	var phone submodel.Phone
+	var id uint
+
+	if id, err = resp.GetID(c.Param("phoneID"), "E1098851", basterm.Phone); err != nil {
+		return
+	}
+
+	if phone, err = p.Service.Restore(resp.Params(submodel.PhoneTable), id); err != nil {
+		resp.Error(err).JSON()
+		return
+	}
+
+	resp.Record(subscriber.RestorePhone, phone)
+	resp.Status(http.StatusOK).
+		MessageT(corterm.VRestoredSuccessfully, basterm.Phone).
+		JSON(phone)
+}
+
 // Separate phone
 func (p *PhoneAPI) Separate(c *gin.Context) {
 	resp := response.New(p.Engine, c, subscriber.Domain)
@@ -153,7 +187,7 @@ func (p *PhoneAPI) Separate(c *gin.Context) {
 		return
 	}
 
-	if aPhone, err = p.Service.Separate(id); err != nil {
+	if aPhone, err = p.Service.Separate(resp.Params(submodel.PhoneTable), id); err != nil {
 		resp.Error(err).JSON()
 		return
 	}
@@ -21,9 +21,9 @@ const (
 // Account model
 type Account struct {
 	gorm.Model
-	CompanyID uint       `json:"company_id,omitempty"`
-	NameEn    string     `gorm:"unique" json:"name_en,omitempty"`
-	NameKu    *string    `gorm:"unique" json:"name_ku,omitempty" `
+	CompanyID uint       `gorm:"index:company_id_idx" json:"company_id,omitempty"`
+	NameEn    string     `gorm:"type:varchar(191)" json:"name_en,omitempty" search:"fulltext"`
+	NameKu    *string    `gorm:"type:varchar(191)" json:"name_ku,omitempty" search:"fulltext"`
 	Type      types.Enum `json:"type,omitempty"`
 	Status    types.Enum `gorm:"default:'active';type:enum('active','inactive')" json:"status,omitempty"`
 	Credit    float64    `json:"credit,omitempty"`
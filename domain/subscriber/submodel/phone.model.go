@@ -19,8 +19,8 @@ const (
 // Phone model
 type Phone struct {
 	gorm.Model
-	Phone     string `gorm:"not null;unique" json:"phone,omitempty"`
-	Notes     string `json:"notes"`
+	Phone     string `gorm:"type:varchar(191);not null" json:"phone,omitempty" search:"like"`
+	Notes     string `json:"notes" search:"fulltext"`
 	AccountID uint   `gorm:"-" json:"account_id" table:"-"`
 	Default   byte   `gorm:"-" json:"default" table:"-"`
 }
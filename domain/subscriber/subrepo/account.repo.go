@@ -10,6 +10,7 @@ import (
 	"omono/internal/param"
 	"omono/pkg/helper"
 	"reflect"
+	"time"
 
 	"github.com/syronz/dict"
 	"github.com/syronz/limberr"
@@ -21,6 +22,7 @@ import (
 type AccountRepo struct {
 	Engine *core.Engine
 	Cols   []string
+	Search helper.Searchable
 }
 
 // ProvideAccountRepo is used in wire and initiate the Cols
@@ -28,14 +30,24 @@ func ProvideAccountRepo(engine *core.Engine) AccountRepo {
 	return AccountRepo{
 		Engine: engine,
 		Cols:   helper.TagExtracter(reflect.TypeOf(submodel.Account{}), submodel.AccountTable),
+		Search: helper.SearchExtracter(reflect.TypeOf(submodel.Account{}), submodel.AccountTable),
 	}
 }
 
-// FindByID finds the account via its id
-func (p *AccountRepo) FindByID(id uint) (account submodel.Account, err error) {
-	err = p.Engine.ReadDB.Table(submodel.AccountTable).
-		Where("id = ? AND sub_accounts.deleted_at is null", id).
-		First(&account).Error
+// accountScope limits the accounts to the active company of the params
+const accountScope = "sub_accounts.company_id = %v"
+
+// FindByID finds the account via its id, it is limited to the active company of the params
+func (p *AccountRepo) FindByID(params param.Param, id uint) (account submodel.Account, err error) {
+	params.ScopeCompany(accountScope)
+
+	db := p.Engine.ReadDB.Table(submodel.AccountTable).
+		Where("id = ? AND sub_accounts.deleted_at is null", id)
+	if params.PreCondition != "" {
+		db = db.Where(params.PreCondition)
+	}
+
+	err = db.First(&account).Error
 
 	account.ID = id
 	err = p.dbError(err, "E1045869", account, corterm.List)
@@ -58,50 +70,105 @@ func (p *AccountRepo) TxFindAccountStatus(db *gorm.DB, id uint) (account submode
 
 // List returns an array of accounts
 func (p *AccountRepo) List(params param.Param) (accounts []submodel.Account, err error) {
+	params.ScopeCompany(accountScope)
+	params.ScopeDeleted(submodel.AccountTable)
+
 	var colsStr string
 	if colsStr, err = validator.CheckColumns(p.Cols, params.Select); err != nil {
 		err = limberr.Take(err, "E1050070").Build()
 		return
 	}
 
+	params.ScopeSearch(p.Search)
+	if err = params.ScopeSort(p.Cols); err != nil {
+		err = limberr.Take(err, "E1027758").Custom(corerr.ValidationFailedErr).Build()
+		return
+	}
+
 	var whereStr string
-	if whereStr, err = params.ParseWhere(p.Cols); err != nil {
+	var args []interface{}
+	if whereStr, args, err = params.ParseWhere(p.Cols); err != nil {
 		err = limberr.Take(err, "E1084619").Custom(corerr.ValidationFailedErr).Build()
 		return
 	}
 
-	err = p.Engine.ReadDB.Table(submodel.AccountTable).Select(colsStr).
-		Where(whereStr).
-		Order(params.Order).
+	err = p.Engine.ReadDB.Unscoped().Table(submodel.AccountTable).Select(colsStr).
+		Where(whereStr, args...).
+		Clauses(params.OrderBy()).
 		Limit(params.Limit).
 		Offset(params.Offset).
 		Find(&accounts).Error
 
 	err = p.dbError(err, "E1082445", submodel.Account{}, corterm.List)
+	params.Arrange(accounts)
 
 	return
 }
 
 // Count of accounts, mainly calls with List
 func (p *AccountRepo) Count(params param.Param) (count int64, err error) {
+	params.ScopeCompany(accountScope)
+	params.ScopeDeleted(submodel.AccountTable)
+	params.ScopeSearch(p.Search)
+
 	var whereStr string
-	if whereStr, err = params.ParseWhere(p.Cols); err != nil {
+	var args []interface{}
+	if whereStr, args, err = params.ParseWhere(p.Cols); err != nil {
 		err = limberr.Take(err, "E1037218").Custom(corerr.ValidationFailedErr).Build()
 		return
 	}
 
 	err = p.Engine.ReadDB.Table(submodel.AccountTable).
-		Where(whereStr).
+		Where(whereStr, args...).
 		Count(&count).Error
 
 	err = p.dbError(err, "E1056203", submodel.Account{}, corterm.List)
 	return
 }
 
-// TxSave the account, in case it is not exist create it
+// Aggregate returns the grouped rows of the accounts, the conditions are the same as the List
+func (p *AccountRepo) Aggregate(params param.Param) (rows []map[string]interface{}, err error) {
+	params.ScopeCompany(accountScope)
+	params.ScopeDeleted(submodel.AccountTable)
+	params.ScopeSearch(p.Search)
+
+	var aggregate func(*gorm.DB) *gorm.DB
+	if aggregate, err = params.ScopeAggregate(p.Cols); err != nil {
+		err = limberr.Take(err, "E1078020").Custom(corerr.ValidationFailedErr).Build()
+		return
+	}
+
+	var whereStr string
+	var args []interface{}
+	if whereStr, args, err = params.ParseWhere(p.Cols); err != nil {
+		err = limberr.Take(err, "E1020883").Custom(corerr.ValidationFailedErr).Build()
+		return
+	}
+
+	err = p.Engine.ReadDB.Table(submodel.AccountTable).
+		Where(whereStr, args...).
+		Scopes(aggregate).
+		Limit(params.Limit).
+		Offset(params.Offset).
+		Find(&rows).Error
+
+	err = p.dbError(err, "E1061642", submodel.Account{}, corterm.List)
+	return
+}
+
+// TxSave the account, the updated_at is the version of the account which is fetched before, in case
+// another request changed the account after that it is not saved
 func (p *AccountRepo) TxSave(db *gorm.DB, account submodel.Account) (u submodel.Account, err error) {
-	if err = db.Table(submodel.AccountTable).Save(&account).Error; err != nil {
+	result := db.Table(submodel.AccountTable).Select("*").
+		Where("updated_at = ?", account.UpdatedAt).Save(&account)
+	if err = result.Error; err != nil {
 		err = p.dbError(err, "E1070874", account, corterm.Updated)
+		return
+	}
+
+	if result.RowsAffected == 0 {
+		err = corerr.PreconditionFailedHelper("E1050628", basterm.Account)
+		return
 	}
 
 	db.Table(submodel.AccountTable).Where("id = ?", account.ID).Find(&u)
@@ -126,12 +193,43 @@ func (p *AccountRepo) TxCreate(db *gorm.DB, account submodel.Account) (u submode
 
 // Delete the account
 func (p *AccountRepo) Delete(account submodel.Account) (err error) {
-	if err = p.Engine.DB.Unscoped().Table(submodel.AccountTable).Delete(&account).Error; err != nil {
+	if err = p.Engine.DB.Table(submodel.AccountTable).Delete(&account).Error; err != nil {
 		err = p.dbError(err, "E1095299", account, corterm.Deleted)
 	}
 	return
 }
 
+// Restore the soft deleted account, it is limited to the active company of the params
+func (p *AccountRepo) Restore(params param.Param, id uint) (err error) {
+	params.ScopeCompany(accountScope)
+
+	db := p.Engine.DB.Unscoped().Table(submodel.AccountTable).
+		Where("sub_accounts.id = ? AND sub_accounts.deleted_at IS NOT NULL", id)
+	if params.PreCondition != "" {
+		db = db.Where(params.PreCondition)
+	}
+
+	result := db.Update("deleted_at", nil)
+	if err = result.Error; err == nil && result.RowsAffected == 0 {
+		err = gorm.ErrRecordNotFound
+	}
+
+	var account submodel.Account
+	account.ID = id
+	err = p.dbError(err, "E1085488", account, corterm.Updated)
+	return
+}
+
+// PurgeDeleted hard delete the accounts which are soft deleted before the time
+func (p *AccountRepo) PurgeDeleted(before time.Time) (err error) {
+	err = p.Engine.DB.Unscoped().Table(submodel.AccountTable).
+		Where("deleted_at < ?", before).
+		Delete(&submodel.Account{}).Error
+
+	err = p.dbError(err, "E1012092", submodel.Account{}, corterm.Deleted)
+	return
+}
+
 // dbError is an internal method for generate proper database error
 func (p *AccountRepo) dbError(err error, code string, account submodel.Account, action string) error {
 	switch corerr.ClearDbErr(err) {
@@ -10,6 +10,7 @@ import (
 	"omono/internal/param"
 	"omono/pkg/helper"
 	"reflect"
+	"time"
 
 	"github.com/syronz/dict"
 	"github.com/syronz/limberr"
@@ -21,6 +22,7 @@ import (
 type PhoneRepo struct {
 	Engine *core.Engine
 	Cols   []string
+	Search helper.Searchable
 }
 
 // ProvidePhoneRepo is used in wire and initiate the Cols
@@ -28,14 +30,26 @@ func ProvidePhoneRepo(engine *core.Engine) PhoneRepo {
 	return PhoneRepo{
 		Engine: engine,
 		Cols:   helper.TagExtracter(reflect.TypeOf(submodel.Phone{}), submodel.PhoneTable),
+		Search: helper.SearchExtracter(reflect.TypeOf(submodel.Phone{}), submodel.PhoneTable),
 	}
 }
 
-// FindByID finds the phone via its id
-func (p *PhoneRepo) FindByID(id uint) (phone submodel.Phone, err error) {
-	err = p.Engine.ReadDB.Table(submodel.PhoneTable).
-		Where("id = ?", id).
-		First(&phone).Error
+// phoneScope limits the phones to the ones joined to an account of the active company
+const phoneScope = "sub_phones.id IN (SELECT sub_account_phones.phone_id FROM sub_account_phones " +
+	"INNER JOIN sub_accounts ON sub_accounts.id = sub_account_phones.account_id " +
+	"WHERE sub_accounts.company_id = %v)"
+
+// FindByID finds the phone via its id, it is limited to the active company of the params
+func (p *PhoneRepo) FindByID(params param.Param, id uint) (phone submodel.Phone, err error) {
+	params.ScopeCompany(phoneScope)
+
+	db := p.Engine.ReadDB.Table(submodel.PhoneTable).
+		Where("id = ?", id)
+	if params.PreCondition != "" {
+		db = db.Where(params.PreCondition)
+	}
+
+	err = db.First(&phone).Error
 
 	phone.ID = id
 	err = p.dbError(err, "E1057421", phone, corterm.List)
@@ -79,50 +93,105 @@ func (p *PhoneRepo) FindByPhone(phoneNumber string) (phone submodel.Phone, err e
 
 // List returns an array of phones
 func (p *PhoneRepo) List(params param.Param) (phones []submodel.Phone, err error) {
+	params.ScopeCompany(phoneScope)
+	params.ScopeDeleted(submodel.PhoneTable)
+
 	var colsStr string
 	if colsStr, err = validator.CheckColumns(p.Cols, params.Select); err != nil {
 		err = limberr.Take(err, "E1071147").Build()
 		return
 	}
 
+	params.ScopeSearch(p.Search)
+	if err = params.ScopeSort(p.Cols); err != nil {
+		err = limberr.Take(err, "E1034890").Custom(corerr.ValidationFailedErr).Build()
+		return
+	}
+
 	var whereStr string
-	if whereStr, err = params.ParseWhere(p.Cols); err != nil {
+	var args []interface{}
+	if whereStr, args, err = params.ParseWhere(p.Cols); err != nil {
 		err = limberr.Take(err, "E1066154").Custom(corerr.ValidationFailedErr).Build()
 		return
 	}
 
-	err = p.Engine.ReadDB.Table(submodel.PhoneTable).Select(colsStr).
-		Where(whereStr).
-		Order(params.Order).
+	err = p.Engine.ReadDB.Unscoped().Table(submodel.PhoneTable).Select(colsStr).
+		Where(whereStr, args...).
+		Clauses(params.OrderBy()).
 		Limit(params.Limit).
 		Offset(params.Offset).
 		Find(&phones).Error
 
 	err = p.dbError(err, "E1058608", submodel.Phone{}, corterm.List)
+	params.Arrange(phones)
 
 	return
 }
 
 // Count of phones, mainly calls with List
 func (p *PhoneRepo) Count(params param.Param) (count int64, err error) {
+	params.ScopeCompany(phoneScope)
+	params.ScopeDeleted(submodel.PhoneTable)
+	params.ScopeSearch(p.Search)
+
 	var whereStr string
-	if whereStr, err = params.ParseWhere(p.Cols); err != nil {
+	var args []interface{}
+	if whereStr, args, err = params.ParseWhere(p.Cols); err != nil {
 		err = limberr.Take(err, "E1083854").Custom(corerr.ValidationFailedErr).Build()
 		return
 	}
 
 	err = p.Engine.ReadDB.Table(submodel.PhoneTable).
-		Where(whereStr).
+		Where(whereStr, args...).
 		Count(&count).Error
 
 	err = p.dbError(err, "E1099536", submodel.Phone{}, corterm.List)
 	return
 }
 
-// Save the phone, in case it is not exist create it
+// Aggregate returns the grouped rows of the phones, the conditions are the same as the List
+func (p *PhoneRepo) Aggregate(params param.Param) (rows []map[string]interface{}, err error) {
+	params.ScopeCompany(phoneScope)
+	params.ScopeDeleted(submodel.PhoneTable)
+	params.ScopeSearch(p.Search)
+
+	var aggregate func(*gorm.DB) *gorm.DB
+	if aggregate, err = params.ScopeAggregate(p.Cols); err != nil {
+		err = limberr.Take(err, "E1017192").Custom(corerr.ValidationFailedErr).Build()
+		return
+	}
+
+	var whereStr string
+	var args []interface{}
+	if whereStr, args, err = params.ParseWhere(p.Cols); err != nil {
+		err = limberr.Take(err, "E1067431").Custom(corerr.ValidationFailedErr).Build()
+		return
+	}
+
+	err = p.Engine.ReadDB.Table(submodel.PhoneTable).
+		Where(whereStr, args...).
+		Scopes(aggregate).
+		Limit(params.Limit).
+		Offset(params.Offset).
+		Find(&rows).Error
+
+	err = p.dbError(err, "E1059448", submodel.Phone{}, corterm.List)
+	return
+}
+
+// Save the phone, the updated_at is the version of the phone which is fetched before, in case
+// another request changed the phone after that it is not saved
 func (p *PhoneRepo) Save(phone submodel.Phone) (u submodel.Phone, err error) {
-	if err = p.Engine.DB.Table(submodel.PhoneTable).Save(&phone).Error; err != nil {
+	result := p.Engine.DB.Table(submodel.PhoneTable).Select("*").
+		Where("updated_at = ?", phone.UpdatedAt).Save(&phone)
+	if err = result.Error; err != nil {
 		err = p.dbError(err, "E1038506", phone, corterm.Updated)
+		return
+	}
+
+	if result.RowsAffected == 0 {
+		err = corerr.PreconditionFailedHelper("E1049292", basterm.Phone)
+		return
 	}
 
 	p.Engine.DB.Table(submodel.PhoneTable).Where("id = ?", phone.ID).Find(&u)
@@ -179,6 +248,37 @@ func (p *PhoneRepo) Delete(phone submodel.Phone) (err error) {
 	return
 }
 
+// Restore the soft deleted phone, it is limited to the active company of the params
+func (p *PhoneRepo) Restore(params param.Param, id uint) (err error) {
+	params.ScopeCompany(phoneScope)
+
+	db := p.Engine.DB.Unscoped().Table(submodel.PhoneTable).
+		Where("sub_phones.id = ? AND sub_phones.deleted_at IS NOT NULL", id)
+	if params.PreCondition != "" {
+		db = db.Where(params.PreCondition)
+	}
+
+	result := db.Update("deleted_at", nil)
+	if err = result.Error; err == nil && result.RowsAffected == 0 {
+		err = gorm.ErrRecordNotFound
+	}
+
+	var phone submodel.Phone
+	phone.ID = id
+	err = p.dbError(err, "E1024117", phone, corterm.Updated)
+	return
+}
+
+// PurgeDeleted hard delete the phones which are soft deleted before the time
+func (p *PhoneRepo) PurgeDeleted(before time.Time) (err error) {
+	err = p.Engine.DB.Unscoped().Table(submodel.PhoneTable).
+		Where("deleted_at < ?", before).
+		Delete(&submodel.Phone{}).Error
+
+	err = p.dbError(err, "E1068941", submodel.Phone{}, corterm.Deleted)
+	return
+}
+
 // dbError is an internal method for generate proper database error
 func (p *PhoneRepo) dbError(err error, code string, phone submodel.Phone, action string) error {
 	switch corerr.ClearDbErr(err) {
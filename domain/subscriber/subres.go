@@ -1,6 +1,10 @@
 package subscriber
 
-import "omono/internal/types"
+import (
+	"omono/domain/subscriber/subterm"
+	"omono/internal/core/corperm"
+	"omono/internal/types"
+)
 
 // list of resources for subscribe domain
 const (
@@ -14,3 +18,8 @@ const (
 	PhoneWrite types.Resource = "phone:write"
 	PhoneExcel types.Resource = "phone:excel"
 )
+
+func init() {
+	corperm.Register("subscriber", subterm.Accounts, AccountRead, AccountWrite, AccountExcel)
+	corperm.Register("subscriber", subterm.Phones, PhoneRead, PhoneWrite, PhoneExcel)
+}
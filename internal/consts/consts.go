@@ -10,6 +10,20 @@ const (
 
 	// TemporaryTokenDuration = 100 * 100000 //in seconds
 	TemporaryTokenDuration = 10
+	MFATokenDuration       = 300 //in seconds
+	RecoveryCodeCount      = 10
+	MFAIssuer              = "omono"
+
+	// default values in case the login settings are not valid
+	LoginMaxAttempts     = 5
+	LoginIPMaxAttempts   = 50
+	LoginLockoutDuration = 900 //in seconds
+	LoginDelayStep       = 1   //in seconds
+	MaxLoginDelay        = 10  //in seconds
+
+	EmailVerificationDuration = 86400 //in seconds
+
+	PermissionEventPollInterval = 5 //in seconds
 
 	MaxRowsCount = 1 << 62
 
@@ -26,6 +40,9 @@ const (
 	UserSuperAdminID   = 79
 	UserResultViewerID = 12
 
+	// the seeded company, the admin and the seeded accounts belong to it
+	DefaultCompanyID = 1
+
 	// it is used in chart of accounts after this numbers show more button emerge
 	MaxChildrenForChartOfAccounts = 20
 )
@@ -5,11 +5,16 @@ type Action string
 
 // Action enums
 const (
-	Update Action = "update"
-	Create Action = "create"
-	Delete Action = "delete"
-	Login  Action = "login"
-	Save   Action = "save"
-	Active Action = "active"
-	Fetch  Action = "fetch"
+	Update  Action = "update"
+	Create  Action = "create"
+	Delete  Action = "delete"
+	Login   Action = "login"
+	Refresh Action = "refresh"
+	Verify  Action = "verify"
+	Forgot  Action = "forgot"
+	Reset   Action = "reset"
+	Resend  Action = "resend"
+	Save    Action = "save"
+	Active  Action = "active"
+	Fetch   Action = "fetch"
 )
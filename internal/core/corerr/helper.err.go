@@ -25,3 +25,11 @@ func ValidationFailedHelper(err error, code string) error {
 		Message(ValidationFailed).
 		Custom(ValidationFailedErr).Build()
 }
+
+// PreconditionFailedHelper help generate proper error in case the record is changed by another
+// request after fetching it
+func PreconditionFailedHelper(code string, part string) error {
+	return limberr.New("the record is changed after fetching it", code).
+		Message(VIsChangedByAnotherRequest, dict.R(part)).
+		Custom(PreconditionFailedErr).Build()
+}
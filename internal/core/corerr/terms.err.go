@@ -27,6 +27,7 @@ const (
 	TokenIsRequired                      = "token is required"
 	TokenIsNotValid                      = "token is not valid"
 	TokenIsExpired                       = "token is expired"
+	TokenIsRevoked                       = "token is revoked"
 	VNotExist                            = "%v not exist"
 	ForbiddenToVV                        = "forbidden to %v %v"
 	Forbidden                            = "forbidden"
@@ -40,4 +41,9 @@ const (
 	NeedDataToBeInserted                 = "need data to be inserted"
 	YouDontHavePermissionToThisV         = "you don't have permission to this %v"
 	PleaseLoginAgain                     = "please login again"
+	TooManyRequests                      = "too many requests"
+	PreconditionFailed                   = "precondition failed"
+	PreconditionRequired                 = "precondition required"
+	IfMatchIsRequiredForUpdatingV        = "if-match header is required for updating the %v"
+	VIsChangedByAnotherRequest           = "%v is changed by another request, please reload it"
 )
@@ -19,7 +19,10 @@ const (
 	InternalServerErr
 	BindingErr
 	ForbiddenErr
-	PreDataInsertedErr //428
+	PreDataInsertedErr      //428
+	TooManyRequestsErr      //429
+	PreconditionFailedErr   //412
+	PreconditionRequiredErr //428
 )
 
 // UniqErrorMap is used for categorized errors and connect error with error page also primary fill
@@ -98,4 +101,25 @@ func init() {
 		Domain: base.Domain,
 		Status: http.StatusPreconditionRequired,
 	}
+
+	UniqErrorMap[TooManyRequestsErr] = limberr.ErrorTheme{
+		Type:   "#TOO_MANY_REQUESTS",
+		Title:  TooManyRequests,
+		Domain: base.Domain,
+		Status: http.StatusTooManyRequests,
+	}
+
+	UniqErrorMap[PreconditionFailedErr] = limberr.ErrorTheme{
+		Type:   "#PRECONDITION_FAILED",
+		Title:  PreconditionFailed,
+		Domain: base.Domain,
+		Status: http.StatusPreconditionFailed,
+	}
+
+	UniqErrorMap[PreconditionRequiredErr] = limberr.ErrorTheme{
+		Type:   "#IF_MATCH_REQUIRED",
+		Title:  PreconditionRequired,
+		Domain: base.Domain,
+		Status: http.StatusPreconditionRequired,
+	}
 }
@@ -0,0 +1,119 @@
+package corperm
+
+import (
+	"encoding/json"
+	"omono/internal/types"
+	"reflect"
+	"testing"
+)
+
+func init() {
+	Register("base", "users", "user:read", "user:write")
+	Register("base", "roles", "role:read")
+	Register("base", "system", "supper:access")
+	Register("subscriber", "accounts", "account:read", "account:write")
+	Register("subscriber", "phones", "phone:read")
+}
+
+func TestParseRules(t *testing.T) {
+	samples := []struct {
+		in  string
+		out Rules
+	}{
+		{`["user:read","role:read"]`, Rules{"user:read", "role:read"}},
+		{"user:read, role:read", Rules{"user:read", "role:read"}},
+		{" user:read ,, ", Rules{"user:read"}},
+		{"", nil},
+	}
+
+	for _, v := range samples {
+		rules, err := ParseRules(v.in)
+		if err != nil || !reflect.DeepEqual(rules, v.out) {
+			t.Errorf("for %q expected %v, got %v, %v", v.in, v.out, rules, err)
+		}
+	}
+}
+
+func TestRulesJSON(t *testing.T) {
+	var role struct {
+		Resources Rules `json:"resources"`
+	}
+
+	for _, v := range []string{`{"resources":["user:read","!user:write"]}`,
+		`{"resources":"user:read, !user:write"}`} {
+		if err := json.Unmarshal([]byte(v), &role); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(role.Resources, Rules{"user:read", "!user:write"}) {
+			t.Errorf("for %v got %v", v, role.Resources)
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	rules := Rules{" user:read", "role:read", "user:read", "", "!"}.Normalize()
+	if !reflect.DeepEqual(rules, Rules{"role:read", "user:read"}) {
+		t.Errorf("got %v", rules)
+	}
+}
+
+func TestInvalid(t *testing.T) {
+	rules := Rules{"user:read", "!user:write", "user:*", "subscriber:*", "*",
+		"user:reader", "superuser:*", "!typo"}
+	invalid := rules.Invalid()
+
+	if !reflect.DeepEqual(invalid, []string{"user:reader", "superuser:*", "!typo"}) {
+		t.Errorf("got %v", invalid)
+	}
+}
+
+func TestHas(t *testing.T) {
+	samples := []struct {
+		rules    Rules
+		resource types.Resource
+		has      bool
+	}{
+		{Rules{"user:read"}, "user:read", true},
+		{Rules{"superuser:reader"}, "user:read", false},
+		{Rules{"user:reader"}, "user:read", false},
+		{Rules{"user:*"}, "user:write", true},
+		{Rules{"user:*"}, "role:read", false},
+		{Rules{"subscriber:*"}, "phone:read", true},
+		{Rules{"subscriber:*"}, "user:read", false},
+		{Rules{"*"}, "role:read", true},
+		{Rules{"user:*", "!user:write"}, "user:write", false},
+		{Rules{"user:*", "!user:write"}, "user:read", true},
+		{Rules{"*", "!subscriber:*"}, "account:read", false},
+		{Rules{"!user:read"}, "user:read", false},
+	}
+
+	for _, v := range samples {
+		if has := Compile(v.rules).Has(v.resource); has != v.has {
+			t.Errorf("%v for %v expected %v, got %v", v.rules, v.resource, v.has, has)
+		}
+	}
+}
+
+func TestCompileSeveralRoles(t *testing.T) {
+	set := Compile(Rules{"user:*"}, Rules{"role:read", "!user:write"})
+
+	if !set.Has("role:read") || !set.Has("user:read") {
+		t.Error("resources of both roles should be granted")
+	}
+	if set.Has("user:write") {
+		t.Error("deny of a role should refuse the grant of the other")
+	}
+}
+
+func TestCovers(t *testing.T) {
+	set := Compile(Rules{"user:*", "account:read"})
+
+	if uncovered := set.Covers(Rules{"user:read", "user:*", "!account:read"}); len(uncovered) > 0 {
+		t.Errorf("all rules should be covered, uncovered %v", uncovered)
+	}
+
+	uncovered := set.Covers(Rules{"subscriber:*"})
+	if !reflect.DeepEqual(uncovered, []string{"account:write", "phone:read"}) {
+		t.Errorf("got %v", uncovered)
+	}
+}
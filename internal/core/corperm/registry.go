@@ -0,0 +1,102 @@
+package corperm
+
+import (
+	"omono/internal/types"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Entry is a registered resource, the resource itself is the term of its description and the
+// group is the term of the section in the role editor
+type Entry struct {
+	Resource types.Resource `json:"resource"`
+	Domain   string         `json:"domain"`
+	Group    string         `json:"group"`
+}
+
+var registry = struct {
+	sync.RWMutex
+	entries map[types.Resource]Entry
+}{entries: make(map[types.Resource]Entry)}
+
+// Register add a group of resources of the domain to the registry, each domain calls it inside
+// the init of its *res.go file
+func Register(domain, group string, resources ...types.Resource) {
+	registry.Lock()
+	defer registry.Unlock()
+
+	for _, v := range resources {
+		registry.entries[v] = Entry{Resource: v, Domain: domain, Group: group}
+	}
+}
+
+// Entries returns all registered resources sorted by domain, group and resource
+func Entries() []Entry {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	entries := make([]Entry, 0, len(registry.entries))
+	for _, v := range registry.entries {
+		entries = append(entries, v)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Domain != entries[j].Domain {
+			return entries[i].Domain < entries[j].Domain
+		}
+		if entries[i].Group != entries[j].Group {
+			return entries[i].Group < entries[j].Group
+		}
+		return entries[i].Resource < entries[j].Resource
+	})
+
+	return entries
+}
+
+// IsRegistered check the existence of the resource
+func IsRegistered(resource types.Resource) bool {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	_, ok := registry.entries[resource]
+	return ok
+}
+
+// EntityOf returns the part of the resource before the colon, user for user:read
+func EntityOf(resource types.Resource) string {
+	str := string(resource)
+	if i := strings.Index(str, ":"); i >= 0 {
+		return str[:i]
+	}
+
+	return str
+}
+
+// domainOf returns the domain of the registered resource
+func domainOf(resource types.Resource) string {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	return registry.entries[resource].Domain
+}
+
+// isValidPattern accept a registered resource, * and a wildcard which matches at least one
+// registered resource like user:* or subscriber:*
+func isValidPattern(pattern string) bool {
+	if pattern == Wildcard {
+		return true
+	}
+
+	if !strings.HasSuffix(pattern, ":"+Wildcard) {
+		return IsRegistered(types.Resource(pattern))
+	}
+
+	for _, v := range Entries() {
+		if matchPattern(pattern, v.Resource, v.Domain) {
+			return true
+		}
+	}
+
+	return false
+}
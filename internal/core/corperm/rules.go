@@ -0,0 +1,125 @@
+package corperm
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"omono/internal/types"
+	"sort"
+	"strings"
+)
+
+const (
+	// Wildcard matches all resources, with a prefix like user:* or subscriber:* it matches the
+	// resources of the entity or the domain
+	Wildcard = "*"
+	// Deny is the prefix of the rules which refuse the resource even if another rule grants it
+	Deny = "!"
+)
+
+// Rules are the resources of a role, it is stored as a JSON array in the database
+type Rules []string
+
+// RulesOf make rules from the resources
+func RulesOf(resources ...types.Resource) Rules {
+	rules := make(Rules, len(resources))
+	for i, v := range resources {
+		rules[i] = string(v)
+	}
+
+	return rules
+}
+
+// ParseRules accept a JSON array or the comma separated list which used to be stored in the
+// resources of the roles
+func ParseRules(str string) (rules Rules, err error) {
+	str = strings.TrimSpace(str)
+	if strings.HasPrefix(str, "[") {
+		err = json.Unmarshal([]byte(str), &rules)
+		return
+	}
+
+	for _, v := range strings.Split(str, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			rules = append(rules, v)
+		}
+	}
+
+	return
+}
+
+// Normalize trim the rules and remove the empty and duplicated ones, the result is sorted
+func (p Rules) Normalize() Rules {
+	exist := make(map[string]bool, len(p))
+	rules := make(Rules, 0, len(p))
+
+	for _, v := range p {
+		v = strings.TrimSpace(v)
+		if v == "" || v == Deny || exist[v] {
+			continue
+		}
+		exist[v] = true
+		rules = append(rules, v)
+	}
+
+	sort.Strings(rules)
+	return rules
+}
+
+// Invalid returns the rules which don't refer to any registered resource
+func (p Rules) Invalid() (invalid []string) {
+	for _, v := range p {
+		if !isValidPattern(strings.TrimPrefix(v, Deny)) {
+			invalid = append(invalid, v)
+		}
+	}
+
+	return
+}
+
+// String is used in the excel and logs
+func (p Rules) String() string {
+	return strings.Join(p, ", ")
+}
+
+// UnmarshalJSON accept a JSON array or a comma separated string, the string was the type of
+// the resources before
+func (p *Rules) UnmarshalJSON(b []byte) (err error) {
+	var str string
+	if err = json.Unmarshal(b, &str); err == nil {
+		*p, err = ParseRules(str)
+		return
+	}
+
+	var rules []string
+	err = json.Unmarshal(b, &rules)
+	*p = rules
+
+	return
+}
+
+// Value save the rules as a JSON array
+func (p Rules) Value() (driver.Value, error) {
+	if p == nil {
+		return "[]", nil
+	}
+
+	b, err := json.Marshal(p)
+	return string(b), err
+}
+
+// Scan read both JSON and the legacy comma separated rules
+func (p *Rules) Scan(value interface{}) (err error) {
+	switch v := value.(type) {
+	case nil:
+		*p = nil
+	case []byte:
+		*p, err = ParseRules(string(v))
+	case string:
+		*p, err = ParseRules(v)
+	default:
+		err = errors.New("rules should be stored as text")
+	}
+
+	return
+}
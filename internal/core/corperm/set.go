@@ -0,0 +1,88 @@
+package corperm
+
+import (
+	"omono/internal/types"
+	"strings"
+)
+
+// Set is the compiled rules of one or several roles, a resource is granted if at least one
+// rule allows it and no deny rule matches it
+type Set struct {
+	allow []string
+	deny  []string
+}
+
+// Compile merge the rules into a set
+func Compile(rules ...Rules) (set Set) {
+	for _, role := range rules {
+		for _, v := range role.Normalize() {
+			if strings.HasPrefix(v, Deny) {
+				set.deny = append(set.deny, strings.TrimPrefix(v, Deny))
+				continue
+			}
+			set.allow = append(set.allow, v)
+		}
+	}
+
+	return
+}
+
+// Has check if the resource is granted
+func (p Set) Has(resource types.Resource) bool {
+	domain := domainOf(resource)
+	return matchAny(p.allow, resource, domain) && !matchAny(p.deny, resource, domain)
+}
+
+// Covers check if all resources granted by the rules are granted by the set as well, it is
+// used for preventing a user to grant more than what they have. Deny rules only limit the
+// rules so they are always covered
+func (p Set) Covers(rules Rules) (uncovered []string) {
+	for _, v := range rules.Normalize() {
+		if strings.HasPrefix(v, Deny) {
+			continue
+		}
+
+		if !strings.HasSuffix(v, Wildcard) {
+			if !p.Has(types.Resource(v)) {
+				uncovered = append(uncovered, v)
+			}
+			continue
+		}
+
+		for _, e := range Entries() {
+			if matchPattern(v, e.Resource, e.Domain) && !p.Has(e.Resource) {
+				uncovered = append(uncovered, string(e.Resource))
+			}
+		}
+	}
+
+	return
+}
+
+func matchAny(patterns []string, resource types.Resource, domain string) bool {
+	for _, v := range patterns {
+		if matchPattern(v, resource, domain) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchPattern compare the exact resource, * or the entity and domain wildcards
+func matchPattern(pattern string, resource types.Resource, domain string) bool {
+	if pattern == string(resource) || pattern == Wildcard {
+		return true
+	}
+
+	prefix := strings.TrimSuffix(pattern, ":"+Wildcard)
+	if prefix == pattern {
+		return false
+	}
+
+	if domain != "" && prefix == domain {
+		return true
+	}
+
+	return strings.HasPrefix(string(resource), prefix+":")
+}
@@ -28,10 +28,11 @@ const (
 	Tag         = "tag"
 	Tags        = "tags"
 
-	VCreatedSuccessfully = "%v created successfully"
-	VUpdatedSuccessfully = "%v updated successfully"
-	VDeletedSuccessfully = "%v deleted successfully"
-	ListOfV              = "list of %v"
-	TemporaryToken       = "temporary token"
-	VInfo                = "%v info"
+	VCreatedSuccessfully  = "%v created successfully"
+	VUpdatedSuccessfully  = "%v updated successfully"
+	VDeletedSuccessfully  = "%v deleted successfully"
+	VRestoredSuccessfully = "%v restored successfully"
+	ListOfV               = "list of %v"
+	TemporaryToken        = "temporary token"
+	VInfo                 = "%v info"
 )
@@ -3,6 +3,7 @@ package core
 import (
 	"omono/domain/base/basmodel"
 	"omono/internal/types"
+	"omono/pkg/helper/jwtkey"
 
 	"github.com/sirupsen/logrus"
 	goaes "github.com/syronz/goAES"
@@ -20,6 +21,7 @@ type Engine struct {
 	AES        goaes.BuildModel
 	Setting    map[types.Setting]types.SettingMap
 	ActivityCh chan basmodel.Activity
+	JWTKeys    *jwtkey.Keyset
 }
 
 // Clone return an engine just like before
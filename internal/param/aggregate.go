@@ -0,0 +1,37 @@
+package param
+
+import (
+	"omono/pkg/helper/sqluri"
+
+	"gorm.io/gorm"
+)
+
+// Aggregated is true in case the group_by or agg is asked, the grouped rows are returned
+// instead of the list
+func (p *Param) Aggregated() bool {
+	return p.GroupBy != "" || p.Agg != ""
+}
+
+// ScopeAggregate validate the group_by and agg against the cols, the returned scope is passed
+// to the Scopes of the query instead of the Select and the order of the list. The default limit
+// of the list is lifted, so all the buckets are returned unless the page_size is asked
+func (p *Param) ScopeAggregate(cols []string) (scope func(*gorm.DB) *gorm.DB, err error) {
+	var agg sqluri.Aggregate
+	if agg, err = sqluri.ParseAggregate(p.GroupBy, p.Agg, cols); err != nil {
+		return
+	}
+
+	if !p.limitAsked {
+		p.Limit, p.Offset = -1, 0
+	}
+
+	scope = func(db *gorm.DB) *gorm.DB {
+		db = db.Select(agg.Select)
+		if agg.Group != "" {
+			db = db.Group(agg.Group).Order(agg.Group)
+		}
+		return db
+	}
+
+	return
+}
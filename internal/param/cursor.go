@@ -0,0 +1,241 @@
+package param
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"omono/pkg/helper/sqluri"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/syronz/limberr"
+	"gorm.io/gorm/schema"
+)
+
+// cursorValue is the value of a key of the sort in the cursor, times are kept separately for
+// binding them as time
+type cursorValue struct {
+	Value interface{} `json:"v,omitempty"`
+	Time  *time.Time  `json:"t,omitempty"`
+}
+
+// keyset keeps the keys of the sort plus the id, the cursor has a value for each key and it
+// is sent to the client as an opaque string
+type keyset struct {
+	keys      []sqluri.SortKey
+	before    bool
+	condition string
+	args      []interface{}
+}
+
+// scopeCursor in case of after or before only the rows beyond the cursor are returned and the
+// offset is ignored. Count doesn't call it because the count is the total
+func (p *Param) scopeCursor(keys []sqluri.SortKey, cols []string) (err error) {
+	raw, name := p.After, "after"
+	if p.Before != "" {
+		if raw != "" {
+			err = errors.New("after and before are used together")
+			return limberr.AddInvalidParam(err, "before",
+				"after and before can't be used together")
+		}
+		raw, name = p.Before, "before"
+	}
+
+	id, _, ok := sqluri.Column("id", cols)
+	if !ok || len(keys) == 0 || (raw != "" && hasNulls(keys)) {
+		if raw == "" {
+			return
+		}
+		err = fmt.Errorf("sort %q can't be used for the cursor", p.Sort)
+		return limberr.AddInvalidParam(err, "sort", "sort %v can't be used for the cursor", p.Sort)
+	}
+
+	p.keyset = keyset{keys: withID(keys, id), before: p.Before != ""}
+	if raw == "" {
+		return
+	}
+
+	var values []interface{}
+	if values, err = decodeCursor(raw, len(p.keyset.keys)); err != nil {
+		return limberr.AddInvalidParam(err, name, "cursor is not valid")
+	}
+
+	// rows after the cursor in the direction of the sort, before inverts the direction and the
+	// result is reversed by Arrange. Each key is compared when the previous keys are equal
+	var ors []string
+	for i, v := range p.keyset.keys {
+		var ands []string
+		for j, prev := range p.keyset.keys[:i] {
+			ands = append(ands, prev.Column+" = ?")
+			p.keyset.args = append(p.keyset.args, values[j])
+		}
+
+		cmp := " > ?"
+		if v.Desc != p.keyset.before {
+			cmp = " < ?"
+		}
+		ands = append(ands, v.Column+cmp)
+		p.keyset.args = append(p.keyset.args, values[i])
+
+		ors = append(ors, "("+strings.Join(ands, " AND ")+")")
+	}
+	p.keyset.condition = "(" + strings.Join(ors, " OR ") + ")"
+
+	p.Offset = 0
+	return
+}
+
+// Arrange is called by the List of the repos after fetching the rows, the rows of the before
+// are fetched in the inverted order and they are reversed to the requested order
+func (p *Param) Arrange(list interface{}) {
+	if !p.keyset.before {
+		return
+	}
+
+	swap := reflect.Swapper(list)
+	for i, j := 0, reflect.ValueOf(list).Len()-1; i < j; i, j = i+1, j-1 {
+		swap(i, j)
+	}
+}
+
+// Keyset returns the cursors of the first and last rows of the list, prev_cursor is used as the
+// before and next_cursor as the after. In case the sort is not suitable for the cursor or in the
+// aggregate mode nil is returned, the NULL values can't be compared
+func (p *Param) Keyset(list interface{}, cols []string) map[string]interface{} {
+	keys, err := p.sortKeys(cols)
+	id, _, ok := sqluri.Column("id", cols)
+	rows := reflect.ValueOf(list)
+	if err != nil || !ok || len(keys) == 0 || hasNulls(keys) || p.Aggregated() ||
+		rows.Kind() != reflect.Slice || rows.Len() == 0 {
+		return nil
+	}
+
+	keys = withID(keys, id)
+	cursors := map[string]interface{}{"prev_cursor": nil, "next_cursor": nil}
+	if cursor, ok := encodeCursor(rows.Index(0), keys); ok {
+		cursors["prev_cursor"] = cursor
+	}
+	if cursor, ok := encodeCursor(rows.Index(rows.Len()-1), keys); ok {
+		cursors["next_cursor"] = cursor
+	}
+
+	return cursors
+}
+
+func hasNulls(keys []sqluri.SortKey) bool {
+	for _, v := range keys {
+		if v.Nulls != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// encodeCursor returns false in case a key of the row is NULL or it is not a field of the row,
+// the NULLs can't be compared and the cursor of them returns no rows
+func encodeCursor(row reflect.Value, keys []sqluri.SortKey) (cursor string, ok bool) {
+	values := make([]cursorValue, len(keys))
+	for i, key := range keys {
+		var v reflect.Value
+		if v, ok = fieldOf(row, key.Field); !ok {
+			return
+		}
+
+		switch t := v.Interface().(type) {
+		case time.Time:
+			values[i].Time = &t
+		default:
+			values[i].Value = t
+		}
+	}
+
+	data, err := json.Marshal(values)
+	if err != nil {
+		return "", false
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data), true
+}
+
+func decodeCursor(raw string, size int) (values []interface{}, err error) {
+	var data []byte
+	if data, err = base64.RawURLEncoding.DecodeString(raw); err != nil {
+		return
+	}
+
+	// numbers are kept as they are, json.Number is bound as a string and the database cast it
+	var cvs []cursorValue
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err = decoder.Decode(&cvs); err != nil {
+		return
+	}
+
+	if len(cvs) != size {
+		err = errors.New("the cursor doesn't belong to the sort")
+		return
+	}
+
+	for _, v := range cvs {
+		switch {
+		case v.Time != nil:
+			values = append(values, *v.Time)
+		case v.Value != nil:
+			values = append(values, v.Value)
+		default:
+			err = errors.New("the cursor has a null value")
+			return nil, err
+		}
+	}
+
+	return
+}
+
+// fieldOf find the field of the row by its json name, fields without json tag like the fields
+// of the gorm.Model are named by the naming of the gorm
+func fieldOf(row reflect.Value, name string) (reflect.Value, bool) {
+	for row.Kind() == reflect.Ptr || row.Kind() == reflect.Interface {
+		if row.IsNil() {
+			return reflect.Value{}, false
+		}
+		row = row.Elem()
+	}
+
+	if row.Kind() != reflect.Struct {
+		return reflect.Value{}, false
+	}
+
+	naming := schema.NamingStrategy{}
+	for i := 0; i < row.NumField(); i++ {
+		field := row.Type().Field(i)
+		tag := strings.Split(field.Tag.Get("json"), ",")[0]
+
+		if field.Anonymous && tag == "" {
+			if v, ok := fieldOf(row.Field(i), name); ok {
+				return v, true
+			}
+			continue
+		}
+
+		if tag == "" {
+			tag = naming.ColumnName("", field.Name)
+		}
+
+		if tag == name {
+			v := row.Field(i)
+			for v.Kind() == reflect.Ptr {
+				if v.IsNil() {
+					return reflect.Value{}, false
+				}
+				v = v.Elem()
+			}
+			return v, true
+		}
+	}
+
+	return reflect.Value{}, false
+}
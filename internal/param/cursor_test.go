@@ -0,0 +1,199 @@
+package param
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"omono/pkg/helper/sqluri"
+	"reflect"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type cursorRow struct {
+	gorm.Model
+	NameEn string  `json:"name_en"`
+	NameKu *string `json:"name_ku"`
+	Credit float64 `json:"credit"`
+}
+
+var cursorCols = []string{"sub_accounts.id", "sub_accounts.created_at", "sub_accounts.name_en",
+	"sub_accounts.name_ku", "sub_accounts.credit"}
+
+func TestCursorRoundTrip(t *testing.T) {
+	created := time.Date(2021, 3, 4, 5, 6, 7, 8e6, time.UTC)
+	ku := "ku"
+	row := cursorRow{NameEn: "en", NameKu: &ku, Credit: 12.5}
+	row.ID = 18
+	row.CreatedAt = created
+
+	samples := []struct {
+		sort string
+		out  []interface{}
+	}{
+		{"name_en", []interface{}{"en", json.Number("18")}},
+		{"-created_at", []interface{}{created, json.Number("18")}},
+		{"name_ku,-credit", []interface{}{"ku", json.Number("12.5"), json.Number("18")}},
+		{"-id", []interface{}{json.Number("18")}},
+	}
+
+	for _, v := range samples {
+		keys, err := sqluri.ParseSort(v.sort, cursorCols)
+		if err != nil {
+			t.Fatalf("sort %q: %v", v.sort, err)
+		}
+		keys = withID(keys, "sub_accounts.id")
+
+		cursor, ok := encodeCursor(reflect.ValueOf(&row), keys)
+		if !ok {
+			t.Errorf("sort %q: cursor is not encoded", v.sort)
+			continue
+		}
+
+		values, err := decodeCursor(cursor, len(keys))
+		if err != nil {
+			t.Errorf("sort %q: %v", v.sort, err)
+			continue
+		}
+
+		if len(values) != len(v.out) {
+			t.Errorf("sort %q: got %v, should be %v", v.sort, values, v.out)
+			continue
+		}
+
+		for i := range values {
+			if tm, ok := values[i].(time.Time); ok {
+				if !tm.Equal(v.out[i].(time.Time)) {
+					t.Errorf("sort %q: got %v, should be %v", v.sort, tm, v.out[i])
+				}
+				continue
+			}
+			if values[i] != v.out[i] {
+				t.Errorf("sort %q: got %#v, should be %#v", v.sort, values[i], v.out[i])
+			}
+		}
+	}
+}
+
+func TestEncodeCursorRefused(t *testing.T) {
+	row := cursorRow{NameEn: "en"}
+	row.ID = 3
+
+	samples := []struct {
+		keys []sqluri.SortKey
+	}{
+		// the name_ku is NULL
+		{[]sqluri.SortKey{{Column: "sub_accounts.name_ku", Field: "name_ku"}}},
+		// the field is not part of the row
+		{[]sqluri.SortKey{{Column: "bas_users.username", Field: "creator"}}},
+	}
+
+	for _, v := range samples {
+		keys := withID(v.keys, "sub_accounts.id")
+		if cursor, ok := encodeCursor(reflect.ValueOf(row), keys); ok {
+			t.Errorf("keys %+v should be refused, got %q", v.keys, cursor)
+		}
+	}
+}
+
+func TestDecodeCursorErrors(t *testing.T) {
+	encode := func(s string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(s))
+	}
+
+	samples := []struct {
+		raw  string
+		size int
+	}{
+		{"not base64!", 1},
+		{encode(`{"v":1}`), 1},
+		{encode(`[{"v":1}`), 1},
+		{encode(`[{"v":1}]`), 2},
+		{encode(`[{"v":"a"},{"v":1},{"v":2}]`), 2},
+		{encode(`[{},{"v":1}]`), 2},
+		{encode(`[{"v":null},{"v":1}]`), 2},
+		{encode(`[{"t":"yesterday"}]`), 1},
+	}
+
+	for _, v := range samples {
+		if values, err := decodeCursor(v.raw, v.size); err == nil {
+			t.Errorf("cursor %q should be refused, got %v", v.raw, values)
+		}
+	}
+}
+
+func TestScopeCursor(t *testing.T) {
+	cursorOf := func(values ...interface{}) string {
+		cvs := make([]cursorValue, len(values))
+		for i, v := range values {
+			cvs[i].Value = v
+		}
+		data, _ := json.Marshal(cvs)
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+
+	samples := []struct {
+		sort      string
+		after     string
+		before    string
+		condition string
+		args      []interface{}
+	}{
+		{"name_en", cursorOf("a", 5), "",
+			"((sub_accounts.name_en > ?) OR (sub_accounts.name_en = ? AND sub_accounts.id > ?))",
+			[]interface{}{"a", "a", json.Number("5")}},
+		{"-name_en", cursorOf("a", 5), "",
+			"((sub_accounts.name_en < ?) OR (sub_accounts.name_en = ? AND sub_accounts.id < ?))",
+			[]interface{}{"a", "a", json.Number("5")}},
+		{"name_en", "", cursorOf("a", 5),
+			"((sub_accounts.name_en < ?) OR (sub_accounts.name_en = ? AND sub_accounts.id < ?))",
+			[]interface{}{"a", "a", json.Number("5")}},
+		{"-credit,name_en", cursorOf(2, "b", 7), "",
+			"((sub_accounts.credit < ?) OR (sub_accounts.credit = ? AND sub_accounts.name_en > ?) OR " +
+				"(sub_accounts.credit = ? AND sub_accounts.name_en = ? AND sub_accounts.id > ?))",
+			[]interface{}{json.Number("2"), json.Number("2"), "b", json.Number("2"), "b",
+				json.Number("7")}},
+		{"-id", cursorOf(9), "", "((sub_accounts.id < ?))", []interface{}{json.Number("9")}},
+	}
+
+	for _, v := range samples {
+		p := Param{Sort: v.sort}
+		p.After, p.Before, p.Offset = v.after, v.before, 20
+
+		if err := p.ScopeSort(cursorCols); err != nil {
+			t.Errorf("sort %q: %v", v.sort, err)
+			continue
+		}
+
+		if p.keyset.condition != v.condition || !reflect.DeepEqual(p.keyset.args, v.args) ||
+			p.Offset != 0 {
+			t.Errorf("\nsort: %q\nout: %v %v, offset: %v\nshould be: %v %v", v.sort,
+				p.keyset.condition, p.keyset.args, p.Offset, v.condition, v.args)
+		}
+	}
+}
+
+func TestScopeCursorErrors(t *testing.T) {
+	samples := []struct {
+		sort   string
+		after  string
+		before string
+	}{
+		{"name_en", "x", "y"},
+		{"name_en", "tampered", ""},
+		{"name_ku:nulls_last", base64.RawURLEncoding.EncodeToString([]byte(`[{"v":"a"},{"v":1}]`)),
+			""},
+		{"name_en,credit", base64.RawURLEncoding.EncodeToString([]byte(`[{"v":"a"},{"v":1}]`)),
+			""},
+	}
+
+	for _, v := range samples {
+		p := Param{Sort: v.sort}
+		p.After, p.Before = v.after, v.before
+
+		if err := p.ScopeSort(cursorCols); err == nil {
+			t.Errorf("sort %q, after %q, before %q should be refused", v.sort, v.after, v.before)
+		}
+	}
+}
@@ -2,13 +2,14 @@ package param
 
 import "omono/pkg/helper/sqluri"
 
-// parseFilter call parser for convert urlQuery to SQL query
-func (p *Param) parseFilter(cols []string) (result string, err error) {
+// parseFilter compile the filter of the urlQuery to a condition with placeholders, the values
+// are returned as the args for binding
+func (p *Param) parseFilter(cols []string) (result string, args []interface{}, err error) {
 	if p.Filter == "" {
 		return
 	}
 
-	if result, err = sqluri.Parser(p.Filter, cols); err != nil {
+	if result, args, err = sqluri.Compile(p.Filter, cols); err != nil {
 		return
 	}
 
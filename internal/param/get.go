@@ -16,10 +16,17 @@ func Get(c *gin.Context, engine *core.Engine, part string) (param Param) {
 	generateOrder(c, &param, part)
 	generateSelectedColumns(c, &param)
 	generateLimit(c, &param)
-	generateOffset(c, &param)
 
-	// param.Search = strings.TrimSpace(c.Query("search"))
+	param.After = c.Query("after")
+	param.Before = c.Query("before")
+
+	param.Search = strings.TrimSpace(c.Query("search"))
 	param.Filter = strings.TrimSpace(c.Query("filter"))
+	param.GroupBy = strings.TrimSpace(c.Query("group_by"))
+	param.Agg = strings.TrimSpace(c.Query("agg"))
+
+	applyView(c, &param)
+	generateOffset(c, &param)
 
 	userID, ok := c.Get("USER_ID")
 	if ok {
@@ -27,6 +34,20 @@ func Get(c *gin.Context, engine *core.Engine, part string) (param Param) {
 		param.UserID = userID.(uint)
 	}
 
+	if sessionID, ok := c.Get("SESSION_ID"); ok {
+		param.SessionID = sessionID.(uint)
+	}
+
+	if actorID, ok := c.Get("ACTOR_ID"); ok {
+		param.ActorID = actorID.(uint)
+	}
+
+	if companyID, ok := c.Get("COMPANY_ID"); ok {
+		param.CompanyID = companyID.(uint)
+	}
+	_, crossTenant := c.Get("CROSS_TENANT")
+	param.CompanyScoped = param.UserID != 0 && !crossTenant
+
 	if c.Query("deleted") == "true" {
 		param.ShowDeletedRows = true
 	}
@@ -38,19 +59,24 @@ func Get(c *gin.Context, engine *core.Engine, part string) (param Param) {
 	return param
 }
 
+// generateOrder set the default order of the part, the sort of the client is validated by the
+// repos. The order_by and direction are kept for the old clients and converted to the sort
 func generateOrder(c *gin.Context, param *Param, part string) {
-	orderBy := part + ".id"
-	direction := "desc"
+	param.Order = part + ".id desc"
+	param.Sort = strings.TrimSpace(c.Query("sort"))
 
-	if c.Query("order_by") != "" {
-		orderBy = c.Query("order_by")
+	if param.Sort != "" || (c.Query("order_by") == "" && c.Query("direction") == "") {
+		return
 	}
 
-	if c.Query("direction") != "" {
-		direction = c.Query("direction")
+	param.Sort = strings.TrimSpace(c.Query("order_by"))
+	if param.Sort == "" {
+		param.Sort = "id"
 	}
 
-	param.Order = orderBy + " " + direction
+	if !strings.EqualFold(c.Query("direction"), "asc") {
+		param.Sort = "-" + param.Sort
+	}
 }
 
 func generateSelectedColumns(c *gin.Context, param *Param) {
@@ -70,6 +96,7 @@ func generateLimit(c *gin.Context, param *Param) {
 			glog.CheckError(err, "Limit is not a number")
 			param.Limit = 10
 		}
+		param.limitAsked = true
 	}
 }
 
@@ -12,11 +12,26 @@ type Param struct {
 	Pagination
 	Search          string
 	Filter          string
+	Sort            string
+	GroupBy         string
+	Agg             string
 	PreCondition    string
 	UserID          uint
+	SessionID       uint
+	ActorID         uint
 	Lang            dict.Lang
 	ErrPanel        string
 	ShowDeletedRows bool
+	CompanyID       uint
+	// CompanyScoped is true for the requests of the users, the repos of the tenant's data add
+	// the predicate of the CompanyID to the PreCondition. Internal params and the cross tenant
+	// mode of the super admin are not limited
+	CompanyScoped bool
+	search        search
+	keyset        keyset
+	// limitAsked is true in case the page_size is in the query, otherwise the aggregate mode
+	// doesn't truncate the buckets by the default limit
+	limitAsked bool
 }
 
 // Pagination is a struct, contains the fields which affected the front-end pagination
@@ -25,6 +40,10 @@ type Pagination struct {
 	Order  string
 	Limit  int
 	Offset int
+	// After and Before are the cursors of the keyset pagination, they are used instead of the
+	// Offset
+	After  string
+	Before string
 }
 
 // New return an intiate of the param with default limit
@@ -48,3 +67,34 @@ func NewForDelete(table string, col string, id interface{}) Param {
 
 	return param
 }
+
+// ScopeCompany add the predicate of the active company to the PreCondition, the pattern has one
+// %v for the id of the company
+func (p *Param) ScopeCompany(pattern string) {
+	if !p.CompanyScoped {
+		return
+	}
+
+	p.addPreCondition(fmt.Sprintf(pattern, p.CompanyID))
+}
+
+// ScopeDeleted add the predicate of the deleted_at to the PreCondition, in case of the
+// ?deleted=true just the deleted rows are returned. The repos use the Unscoped in the List for
+// disabling the condition of the gorm
+func (p *Param) ScopeDeleted(table string) {
+	if p.ShowDeletedRows {
+		p.addPreCondition(table + ".deleted_at IS NOT NULL")
+		return
+	}
+
+	p.addPreCondition(table + ".deleted_at IS NULL")
+}
+
+func (p *Param) addPreCondition(condition string) {
+	if p.PreCondition == "" {
+		p.PreCondition = condition
+		return
+	}
+
+	p.PreCondition = fmt.Sprintf("(%v) AND %v", p.PreCondition, condition)
+}
@@ -0,0 +1,57 @@
+package param
+
+import (
+	"fmt"
+	"omono/pkg/helper"
+	"strings"
+	"unicode"
+)
+
+// search is the condition of the search parameter and the relevance which is used for ordering
+// the result in case the sort is not asked
+type search struct {
+	condition string
+	args      []interface{}
+	relevance string
+	term      string
+}
+
+// ScopeSearch add the condition of the search to the where, fulltext columns are matched in the
+// boolean mode and each word should exist as a prefix. Like columns are ORed with it
+func (p *Param) ScopeSearch(s helper.Searchable) {
+	p.search = search{}
+
+	words := strings.FieldsFunc(p.Search, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) == 0 {
+		return
+	}
+
+	var conditions []string
+
+	if len(s.FullText) > 0 {
+		match := fmt.Sprintf("MATCH (%v) AGAINST (? IN BOOLEAN MODE)",
+			strings.Join(s.FullText, ", "))
+		p.search.term = "+" + strings.Join(words, "* +") + "*"
+		conditions = append(conditions, match)
+		p.search.args = append(p.search.args, p.search.term)
+		if p.Sort == "" {
+			p.search.relevance = match
+		}
+	}
+
+	like := "%" + escapeLike(strings.TrimSpace(p.Search)) + "%"
+	for _, v := range s.Like {
+		conditions = append(conditions, v+" LIKE ?")
+		p.search.args = append(p.search.args, like)
+	}
+
+	if len(conditions) > 0 {
+		p.search.condition = "(" + strings.Join(conditions, " OR ") + ")"
+	}
+}
+
+func escapeLike(str string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(str)
+}
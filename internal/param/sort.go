@@ -0,0 +1,94 @@
+package param
+
+import (
+	"omono/pkg/helper/sqluri"
+	"strings"
+
+	"gorm.io/gorm/clause"
+)
+
+// ScopeSort validate the sort of the client against the cols and prepare the keyset pagination,
+// it is called by the List of the repos. In case the sort is not asked the Order is used
+func (p *Param) ScopeSort(cols []string) (err error) {
+	p.keyset = keyset{}
+
+	var keys []sqluri.SortKey
+	if keys, err = p.sortKeys(cols); err != nil {
+		return
+	}
+
+	return p.scopeCursor(keys, cols)
+}
+
+// sortKeys returns the columns of the sort, the Order is set by the server and it is used in
+// case it is a column with an optional direction. Searching without sort is ordered by the
+// relevance and it has no keys
+func (p *Param) sortKeys(cols []string) (keys []sqluri.SortKey, err error) {
+	if p.Sort != "" {
+		return sqluri.ParseSort(p.Sort, cols)
+	}
+
+	order := strings.Fields(p.Order)
+	if len(order) == 0 || len(order) > 2 || p.Search != "" {
+		return
+	}
+
+	var key sqluri.SortKey
+	var ok bool
+	if key.Column, key.Field, ok = sqluri.Column(order[0], cols); !ok {
+		return
+	}
+
+	if len(order) == 2 {
+		key.Desc = strings.EqualFold(order[1], "desc")
+		if !key.Desc && !strings.EqualFold(order[1], "asc") {
+			return
+		}
+	}
+
+	return []sqluri.SortKey{key}, nil
+}
+
+// OrderBy is passed to the Clauses of the query instead of the Order, in case of searching
+// without sort the most relevant rows come first. The id is added to the keys of the sort for
+// having a stable order and the direction is inverted for the before
+func (p *Param) OrderBy() clause.OrderBy {
+	if len(p.keyset.keys) > 0 {
+		keys := p.keyset.keys
+		if p.keyset.before {
+			keys = make([]sqluri.SortKey, len(p.keyset.keys))
+			for i, v := range p.keyset.keys {
+				v.Desc = !v.Desc
+				switch v.Nulls {
+				case "first":
+					v.Nulls = "last"
+				case "last":
+					v.Nulls = "first"
+				}
+				keys[i] = v
+			}
+		}
+		return clause.OrderBy{Expression: clause.Expr{SQL: sqluri.OrderOf(keys)}}
+	}
+
+	if p.search.relevance == "" {
+		return clause.OrderBy{Expression: clause.Expr{SQL: p.Order}}
+	}
+
+	return clause.OrderBy{Expression: clause.Expr{
+		SQL:  p.search.relevance + " DESC, " + p.Order,
+		Vars: []interface{}{p.search.term},
+	}}
+}
+
+// withID add the id to the end of the keys in case it is not part of the sort, the rows with
+// the same values are ordered by their id
+func withID(keys []sqluri.SortKey, id string) []sqluri.SortKey {
+	for _, v := range keys {
+		if v.Column == id {
+			return keys
+		}
+	}
+
+	return append(keys, sqluri.SortKey{Column: id, Field: "id", Desc: keys[len(keys)-1].Desc})
+}
@@ -0,0 +1,50 @@
+package param
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// View is the saved part of the param, the middleware of the views put it in the context for
+// the list and excel endpoints. The values of the query have priority over the view, except the
+// filter which is joined to the filter of the view
+type View struct {
+	Filter   string
+	Search   string
+	Select   string
+	Sort     string
+	PageSize int
+}
+
+// applyView fill the param by the view of the context, it should be called before calculating
+// the offset because the page_size of the view changes the limit
+func applyView(c *gin.Context, param *Param) {
+	v, ok := c.Get("VIEW")
+	if !ok {
+		return
+	}
+	view := v.(View)
+
+	switch {
+	case view.Filter == "":
+	case param.Filter == "":
+		param.Filter = view.Filter
+	default:
+		param.Filter = "(" + view.Filter + ")[and](" + param.Filter + ")"
+	}
+
+	if param.Search == "" {
+		param.Search = view.Search
+	}
+
+	if c.Query("select") == "" && view.Select != "" {
+		param.Select = view.Select
+	}
+
+	if param.Sort == "" {
+		param.Sort = view.Sort
+	}
+
+	if c.Query("page_size") == "" && view.PageSize > 0 {
+		param.Limit = view.PageSize
+	}
+}
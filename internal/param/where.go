@@ -4,11 +4,11 @@ import (
 	"strings"
 )
 
-func (p *Param) parseWhere(cols []string) (whereStr string, err error) {
+func (p *Param) parseWhere(cols []string) (whereStr string, args []interface{}, err error) {
 	var whereArr []string
 	var resultFilter string
 
-	if resultFilter, err = p.parseFilter(cols); err != nil {
+	if resultFilter, args, err = p.parseFilter(cols); err != nil {
 		return
 	}
 
@@ -16,6 +16,16 @@ func (p *Param) parseWhere(cols []string) (whereStr string, err error) {
 		whereArr = append(whereArr, resultFilter)
 	}
 
+	if p.search.condition != "" {
+		whereArr = append(whereArr, p.search.condition)
+		args = append(args, p.search.args...)
+	}
+
+	if p.keyset.condition != "" {
+		whereArr = append(whereArr, p.keyset.condition)
+		args = append(args, p.keyset.args...)
+	}
+
 	if p.PreCondition != "" {
 		whereArr = append(whereArr, p.PreCondition)
 	}
@@ -27,22 +37,8 @@ func (p *Param) parseWhere(cols []string) (whereStr string, err error) {
 	return
 }
 
-// ParseWhere combine preConditions and filter with each other
-func (p *Param) ParseWhere(cols []string) (whereStr string, err error) {
+// ParseWhere combine preConditions and filter with each other, the values of the filter are
+// returned as args and they should be passed to the Where beside the whereStr
+func (p *Param) ParseWhere(cols []string) (whereStr string, args []interface{}, err error) {
 	return p.parseWhere(cols)
 }
-
-// ParseWhereDelete is used when the table has deleted_at column
-func (p *Param) ParseWhereDelete(cols []string) (whereStr string, err error) {
-	if whereStr, err = p.parseWhere(cols); err != nil {
-		return
-	}
-
-	if !p.ShowDeletedRows {
-		whereStr = " deleted_at is NULL AND " + whereStr
-	} else {
-		whereStr = " deleted_at is not NULL AND " + whereStr
-	}
-
-	return
-}
@@ -0,0 +1,55 @@
+package response
+
+import (
+	"fmt"
+	"omono/internal/core/corerr"
+	"strings"
+	"time"
+
+	"github.com/syronz/dict"
+	"github.com/syronz/limberr"
+	"gorm.io/gorm"
+)
+
+// ETag of the record is made from its id and updated_at, each save changes the updated_at so the
+// etag of the stale records are different. The database rounds the updated_at to milliseconds,
+// the created records which are not fetched again are rounded the same way
+func ETag(model gorm.Model) string {
+	return fmt.Sprintf(`"%x-%x"`, model.ID, model.UpdatedAt.Round(time.Millisecond).UnixNano()/1e6)
+}
+
+// SetETag put the etag of the record in the header, the clients send it back as If-Match
+func (r *Response) SetETag(model gorm.Model) *Response {
+	r.Context.Header("ETag", ETag(model))
+	return r
+}
+
+// IfMatch compares the If-Match header with the etag of the current record, in case it is
+// missing or stale the error is sent back with the current record and its etag. The "*" matches
+// all records
+func (r *Response) IfMatch(model gorm.Model, current interface{}, code, part string) (err error) {
+	ifMatch := strings.TrimSpace(r.Context.GetHeader("If-Match"))
+
+	switch {
+	case ifMatch == "":
+		err = limberr.New("if-match header is missing", code).
+			Message(corerr.IfMatchIsRequiredForUpdatingV, dict.R(part)).
+			Custom(corerr.PreconditionRequiredErr).Build()
+
+	case ifMatch == "*":
+		return
+
+	default:
+		etag := ETag(model)
+		for _, v := range strings.Split(ifMatch, ",") {
+			if strings.TrimSpace(v) == etag {
+				return
+			}
+		}
+
+		err = corerr.PreconditionFailedHelper(code, part)
+	}
+
+	r.SetETag(model).Error(err).JSON(current)
+	return
+}
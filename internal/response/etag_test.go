@@ -0,0 +1,92 @@
+package response
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"omono/internal/core"
+	"omono/internal/types"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func TestETag(t *testing.T) {
+	updated := time.Date(2021, 5, 6, 7, 8, 9, 0, time.UTC)
+	ms := updated.UnixNano() / 1e6
+
+	samples := []struct {
+		id      uint
+		updated time.Time
+		out     string
+	}{
+		{26, updated, `"1a-` + hex(ms) + `"`},
+		{26, updated.Add(123400 * time.Microsecond), `"1a-` + hex(ms+123) + `"`},
+		{26, updated.Add(123600 * time.Microsecond), `"1a-` + hex(ms+124) + `"`},
+		{27, updated, `"1b-` + hex(ms) + `"`},
+	}
+
+	for _, v := range samples {
+		model := gorm.Model{ID: v.id, UpdatedAt: v.updated}
+		if out := ETag(model); out != v.out {
+			t.Errorf("\nin: %v %v\nout: %v\nshould be: %v", v.id, v.updated, out, v.out)
+		}
+	}
+}
+
+func TestIfMatch(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine := &core.Engine{Envs: types.Envs{}}
+
+	model := gorm.Model{ID: 26, UpdatedAt: time.Date(2021, 5, 6, 7, 8, 9, 0, time.UTC)}
+	// the client holds the record before the database rounds its updated_at
+	fetched := model
+	fetched.UpdatedAt = fetched.UpdatedAt.Add(400 * time.Microsecond)
+	stale := model
+	stale.UpdatedAt = stale.UpdatedAt.Add(-time.Second)
+
+	samples := []struct {
+		ifMatch string
+		status  int
+		passed  bool
+	}{
+		{"", http.StatusPreconditionRequired, false},
+		{"  ", http.StatusPreconditionRequired, false},
+		{ETag(stale), http.StatusPreconditionFailed, false},
+		{`"1a"`, http.StatusPreconditionFailed, false},
+		{ETag(model), http.StatusOK, true},
+		{ETag(fetched), http.StatusOK, true},
+		{ETag(stale) + ", " + ETag(model), http.StatusOK, true},
+		{"*", http.StatusOK, true},
+	}
+
+	for _, v := range samples {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPut, "/api/accounts/26", nil)
+		if v.ifMatch != "" {
+			c.Request.Header.Set("If-Match", v.ifMatch)
+		}
+
+		err := New(engine, c, "test").IfMatch(model, model, "E0000000", "account")
+		if (err == nil) != v.passed {
+			t.Errorf("if-match %q: got %v, passing should be %v", v.ifMatch, err, v.passed)
+			continue
+		}
+
+		if !v.passed {
+			if w.Code != v.status {
+				t.Errorf("if-match %q: status %v, should be %v", v.ifMatch, w.Code, v.status)
+			}
+			if etag := w.Header().Get("ETag"); etag != ETag(model) {
+				t.Errorf("if-match %q: etag %v, should be %v", v.ifMatch, etag, ETag(model))
+			}
+		}
+	}
+}
+
+func hex(n int64) string {
+	return strconv.FormatInt(n, 16)
+}
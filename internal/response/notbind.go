@@ -1,10 +1,14 @@
 package response
 
 import (
+	"encoding/json"
+	"net/http"
 	"omono/internal/core/corerr"
 	"omono/internal/core/corterm"
+	"omono/pkg/helper"
 	"strconv"
 
+	"github.com/gin-gonic/gin/binding"
 	"github.com/syronz/dict"
 	"github.com/syronz/limberr"
 )
@@ -28,6 +32,38 @@ func (r *Response) Bind(st interface{}, code, domain, part string) (err error) {
 	return
 }
 
+// BindUpdate binds the body of the update, in case of PATCH the body is a JSON Merge Patch
+// (RFC 7396) and it is applied on the current record, so the omitted fields are kept
+func (r *Response) BindUpdate(st interface{}, current interface{}, code, domain,
+	part string) (err error) {
+	if r.Context.Request.Method != http.MethodPatch {
+		return r.Bind(st, code, domain, part)
+	}
+
+	var doc, patch, merged []byte
+	if doc, err = json.Marshal(current); err != nil {
+		r.NotBind(err, code, domain, part)
+		return
+	}
+
+	if patch, err = r.Context.GetRawData(); err != nil {
+		r.NotBind(err, code, domain, part)
+		return
+	}
+
+	if merged, err = helper.MergePatch(doc, patch); err != nil {
+		r.NotBind(err, code, domain, part)
+		return
+	}
+
+	if err = binding.JSON.BindBody(merged, st); err != nil {
+		r.NotBind(err, code, domain, part)
+		return
+	}
+
+	return
+}
+
 // GetID returns the ID
 func (r *Response) GetID(idIn, code, part string) (id uint, err error) {
 	tmpID, err := strconv.ParseUint(idIn, 10, 32)
@@ -33,8 +33,8 @@ func (r *Response) RecordCreate(ev types.Event, newData interface{}) {
 func (r *Response) initiateRecordCh(ev types.Event, data ...interface{}) {
 	activityServ := service.ProvideBasActivityService(basrepo.ProvideActivityRepo(r.Engine))
 
-	var userID uint
-	var username string
+	var userID, actorID uint
+	var username, actorUsername string
 
 	recordType := activityServ.FindRecordType(data...)
 	before, after := activityServ.FillBeforeAfter(recordType, data...)
@@ -56,6 +56,10 @@ func (r *Response) initiateRecordCh(ev types.Event, data ...interface{}) {
 	if usernameTmp, ok := r.Context.Get("USERNAME"); ok {
 		username = usernameTmp.(string)
 	}
+	if actorIDtmp, ok := r.Context.Get("ACTOR_ID"); ok {
+		actorID = actorIDtmp.(uint)
+		actorUsername = r.Context.GetString("ACTOR_USERNAME")
+	}
 
 	activity := basmodel.Activity{
 		Event:    ev.String(),
@@ -65,6 +69,9 @@ func (r *Response) initiateRecordCh(ev types.Event, data ...interface{}) {
 		URI:      r.Context.Request.RequestURI,
 		Before:   string(before),
 		After:    string(after),
+
+		ActorID:       actorID,
+		ActorUsername: actorUsername,
 	}
 
 	r.Engine.ActivityCh <- activity
@@ -89,6 +89,19 @@ func (r *Response) MessageT(message string, params ...interface{}) *Response {
 	return r
 }
 
+// Extra add the values to the extra of the result, it is used for the information which is
+// not part of the data like the cursors of the list
+func (r *Response) Extra(extra map[string]interface{}) *Response {
+	if r.Result.Extra == nil && len(extra) > 0 {
+		r.Result.Extra = make(map[string]interface{}, len(extra))
+	}
+
+	for k, v := range extra {
+		r.Result.Extra[k] = v
+	}
+	return r
+}
+
 // Abort prepare response to abort instead of return in last step (JSON)
 func (r *Response) Abort() *Response {
 	r.abort = true
@@ -131,12 +144,14 @@ func (r *Response) JSON(data ...interface{}) {
 			Message: r.Result.Message,
 			Error:   parsedError,
 			Data:    finalData,
+			Extra:   r.Result.Extra,
 		})
 	} else {
 		r.Context.JSON(r.status, &Result{
 			Message: r.Result.Message,
 			Error:   parsedError,
 			Data:    finalData,
+			Extra:   r.Result.Extra,
 			// CustomError: r.Result.Error,
 		})
 	}
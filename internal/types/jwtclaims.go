@@ -11,5 +11,17 @@ type JWTClaims struct {
 	Username string    `json:"username"`
 	ID       uint      `json:"id"`
 	Lang     dict.Lang `json:"language"`
+	// MFAPending tokens are only accepted for completing the two-factor authentication
+	MFAPending bool `json:"mfa_pending,omitempty"`
+	// SessionID refers to the bas_sessions, tokens of the terminated sessions are refused
+	SessionID uint `json:"sid,omitempty"`
+	// ActorID and ActorUsername belong to the real user in the impersonation tokens, ID and
+	// Username are the impersonated user
+	ActorID       uint   `json:"act_id,omitempty"`
+	ActorUsername string `json:"act_username,omitempty"`
+	// CompanyID is the active company of the user, CrossTenant is only issued for the super
+	// admins and it is not limited to any company
+	CompanyID   uint `json:"cid,omitempty"`
+	CrossTenant bool `json:"xt,omitempty"`
 	jwt.StandardClaims
 }
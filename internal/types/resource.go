@@ -2,7 +2,6 @@ package types
 
 import (
 	"fmt"
-	"strings"
 )
 
 // Resource is a special type for checking the access
@@ -11,14 +10,3 @@ type Resource string
 func (p *Resource) String() string {
 	return fmt.Sprint(*p)
 }
-
-// ResourceJoin make a string from an array of resources
-func ResourceJoin(resources []Resource) string {
-	var strArr []string
-
-	for _, v := range resources {
-		strArr = append(strArr, string(v))
-	}
-
-	return strings.Join(strArr, ", ")
-}
@@ -1,5 +1,7 @@
 package types
 
+import "strings"
+
 type Setting string
 
 type SettingMap struct {
@@ -12,3 +14,8 @@ func (p SettingMap) Touint() uint {
 	n, _ := StrToUint(p.Value)
 	return n
 }
+
+// ToBool return true in case the value is true, it is case insensitive
+func (p SettingMap) ToBool() bool {
+	return strings.ToUpper(p.Value) == "TRUE"
+}
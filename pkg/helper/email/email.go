@@ -22,7 +22,7 @@ func Config() *ConfigEmail {
 }
 
 // SendEmail email with body and attachment
-func (c *ConfigEmail) SendEmail(subject, title, attachment string, body interface{}) {
+func (c *ConfigEmail) SendEmail(subject, title, attachment string, body interface{}) error {
 	m := gomail.NewMessage()
 	m.SetHeader("From", c.From)
 	m.SetHeader("To", c.To)
@@ -37,7 +37,5 @@ func (c *ConfigEmail) SendEmail(subject, title, attachment string, body interfac
 
 	d := gomail.NewPlainDialer(c.Host, c.Port, c.Username, c.Password)
 
-	if err := d.DialAndSend(m); err != nil {
-		panic(err)
-	}
+	return d.DialAndSend(m)
 }
@@ -0,0 +1,55 @@
+package jwtkey
+
+import (
+	"crypto/ed25519"
+	"errors"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// SigningMethodEdDSA implements the EdDSA family (Ed25519) which is missing in jwt-go v3
+var SigningMethodEdDSA = &signingMethodEdDSA{}
+
+// ErrEdDSAVerification is returned when the signature is not valid
+var ErrEdDSAVerification = errors.New("eddsa: verification error")
+
+type signingMethodEdDSA struct{}
+
+func init() {
+	jwt.RegisterSigningMethod(SigningMethodEdDSA.Alg(), func() jwt.SigningMethod {
+		return SigningMethodEdDSA
+	})
+}
+
+func (p *signingMethodEdDSA) Alg() string {
+	return "EdDSA"
+}
+
+// Verify the signature, the key should be an ed25519.PublicKey
+func (p *signingMethodEdDSA) Verify(signingString, signature string, key interface{}) error {
+	pub, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return jwt.ErrInvalidKeyType
+	}
+
+	sig, err := jwt.DecodeSegment(signature)
+	if err != nil {
+		return err
+	}
+
+	if !ed25519.Verify(pub, []byte(signingString), sig) {
+		return ErrEdDSAVerification
+	}
+
+	return nil
+}
+
+// Sign the string, the key should be an ed25519.PrivateKey
+func (p *signingMethodEdDSA) Sign(signingString string, key interface{}) (string, error) {
+	priv, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return "", jwt.ErrInvalidKeyType
+	}
+
+	return jwt.EncodeSegment(ed25519.Sign(priv, []byte(signingString))), nil
+}
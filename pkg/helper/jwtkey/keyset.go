@@ -0,0 +1,255 @@
+package jwtkey
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// Ext is the extension of the key files inside the keys directory, the name of the file
+// without extension is used as kid
+const Ext = ".pem"
+
+// HMACKid is the kid of the key created by NewHMAC
+const HMACKid = "default"
+
+// ActiveFile is the file inside the keys directory which holds the kid of the active key, it
+// has priority over the active key which is passed to LoadDir and Reload. Changing it and
+// reloading the keyset rotates the active key without restarting the server
+const ActiveFile = "active"
+
+// errors of the keyset
+var (
+	ErrNoActiveKey    = errors.New("active key is not defined or it doesn't have a private key")
+	ErrUnknownKid     = errors.New("kid of the token is unknown")
+	ErrMethodMismatch = errors.New("signing method of the token doesn't match the key")
+)
+
+// Key holds one of the keys of the keyset. Keys without private part are kept just for
+// verifying the tokens which are signed before the rotation
+type Key struct {
+	ID      string
+	Method  jwt.SigningMethod
+	Private interface{}
+	Public  interface{}
+}
+
+// Keyset is a thread-safe collection of keys, tokens are signed by the active key and
+// verified by the key which its kid is mentioned in the header of the token
+type Keyset struct {
+	mu     sync.RWMutex
+	keys   map[string]*Key
+	active string
+	dir    string
+}
+
+// NewHMAC returns a keyset with a single HS256 key, it is used when no keys directory is
+// defined. Symmetric keys are never published in the JWKS
+func NewHMAC(secret []byte) *Keyset {
+	return &Keyset{
+		keys: map[string]*Key{
+			HMACKid: {ID: HMACKid, Method: jwt.SigningMethodHS256, Private: secret, Public: secret},
+		},
+		active: HMACKid,
+	}
+}
+
+// LoadDir read all PEM files inside the dir. Private keys (PKCS#1 or PKCS#8) are used for
+// signing and verification, public keys (PKIX) just for verification. RSA keys are used with
+// RS256 and Ed25519 keys with EdDSA
+func LoadDir(dir, active string) (*Keyset, error) {
+	ks := &Keyset{dir: dir}
+	if err := ks.load(dir, active); err != nil {
+		return nil, err
+	}
+
+	return ks, nil
+}
+
+// Reload read the directory again, it is used for rotation without restarting the server.
+// In case of error the current keys stay untouched
+func (p *Keyset) Reload(active string) error {
+	if p.dir == "" {
+		return errors.New("keyset is not loaded from a directory")
+	}
+
+	return p.load(p.dir, active)
+}
+
+func (p *Keyset) load(dir, active string) error {
+	kid, err := readActive(dir)
+	if err != nil {
+		return err
+	}
+	if kid != "" {
+		active = kid
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*"+Ext))
+	if err != nil {
+		return err
+	}
+
+	keys := make(map[string]*Key, len(files))
+	for _, f := range files {
+		data, err := ioutil.ReadFile(f)
+		if err != nil {
+			return err
+		}
+
+		kid := strings.TrimSuffix(filepath.Base(f), Ext)
+		if keys[kid], err = ParsePEM(kid, data); err != nil {
+			return fmt.Errorf("%v: %v", f, err)
+		}
+	}
+
+	if key, ok := keys[active]; !ok || key.Private == nil {
+		return ErrNoActiveKey
+	}
+
+	p.mu.Lock()
+	p.keys = keys
+	p.active = active
+	p.mu.Unlock()
+
+	return nil
+}
+
+// readActive returns the kid inside the ActiveFile, it is empty in case the file not exist
+func readActive(dir string) (string, error) {
+	data, err := ioutil.ReadFile(filepath.Join(dir, ActiveFile))
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+
+	return strings.TrimSpace(string(data)), err
+}
+
+// ParsePEM convert the PEM block to the Key
+func ParsePEM(kid string, data []byte) (*Key, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("pem block not found")
+	}
+
+	key := &Key{ID: kid}
+
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		priv, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		key.Private = priv
+		key.Public = &priv.PublicKey
+	case "PRIVATE KEY":
+		priv, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		switch k := priv.(type) {
+		case *rsa.PrivateKey:
+			key.Private = k
+			key.Public = &k.PublicKey
+		case ed25519.PrivateKey:
+			key.Private = k
+			key.Public = k.Public()
+		default:
+			return nil, fmt.Errorf("private key type %T is not supported", priv)
+		}
+	case "PUBLIC KEY":
+		pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		key.Public = pub
+	default:
+		return nil, fmt.Errorf("pem type %v is not supported", block.Type)
+	}
+
+	switch key.Public.(type) {
+	case *rsa.PublicKey:
+		key.Method = jwt.SigningMethodRS256
+	case ed25519.PublicKey:
+		key.Method = SigningMethodEdDSA
+	default:
+		return nil, fmt.Errorf("public key type %T is not supported", key.Public)
+	}
+
+	return key, nil
+}
+
+// Active returns the kid of the signing key
+func (p *Keyset) Active() string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.active
+}
+
+// Sign the claims with the active key and put its kid in the header
+func (p *Keyset) Sign(claims jwt.Claims) (string, error) {
+	p.mu.RLock()
+	key, ok := p.keys[p.active]
+	p.mu.RUnlock()
+
+	if !ok || key.Private == nil {
+		return "", ErrNoActiveKey
+	}
+
+	token := jwt.NewWithClaims(key.Method, claims)
+	token.Header["kid"] = key.ID
+
+	return token.SignedString(key.Private)
+}
+
+// Keyfunc is passed to the jwt.Parse functions, it choose the key based on the kid and
+// refuse the tokens which their alg is not equal to the key's method. Tokens without kid are
+// verified with the active key
+func (p *Keyset) Keyfunc(token *jwt.Token) (interface{}, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		kid = p.active
+	}
+
+	key, ok := p.keys[kid]
+	if !ok {
+		return nil, ErrUnknownKid
+	}
+
+	if token.Method == nil || token.Method.Alg() != key.Method.Alg() {
+		return nil, ErrMethodMismatch
+	}
+
+	return key.Public, nil
+}
+
+// JWKS returns the public part of the asymmetric keys as a JSON Web Key Set (RFC 7517)
+func (p *Keyset) JWKS() JWKS {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	set := JWKS{Keys: []JWK{}}
+	for _, key := range p.keys {
+		if jwk, ok := key.JWK(); ok {
+			set.Keys = append(set.Keys, jwk)
+		}
+	}
+
+	sort.Slice(set.Keys, func(i, j int) bool { return set.Keys[i].Kid < set.Keys[j].Kid })
+
+	return set
+}
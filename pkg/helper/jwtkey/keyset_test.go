@@ -0,0 +1,177 @@
+package jwtkey
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+func writeKey(t *testing.T, dir, kid string, block *pem.Block) {
+	if err := ioutil.WriteFile(filepath.Join(dir, kid+Ext), pem.EncodeToMemory(block), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func keysDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "jwtkey")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeKey(t, dir, "rsa-1", &pem.Block{Type: "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)})
+
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(edKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeKey(t, dir, "ed-1", &pem.Block{Type: "PRIVATE KEY", Bytes: der})
+
+	return dir
+}
+
+func claims() jwt.StandardClaims {
+	return jwt.StandardClaims{ExpiresAt: time.Now().Add(time.Minute).Unix(), Subject: "1"}
+}
+
+func TestSignAndVerify(t *testing.T) {
+	dir := keysDir(t)
+	defer os.RemoveAll(dir)
+
+	for _, kid := range []string{"rsa-1", "ed-1"} {
+		ks, err := LoadDir(dir, kid)
+		if err != nil {
+			t.Fatalf("keyset with active=%v not loaded: %v", kid, err)
+		}
+
+		signed, err := ks.Sign(claims())
+		if err != nil {
+			t.Fatalf("token with kid=%v not signed: %v", kid, err)
+		}
+
+		tkn, err := jwt.ParseWithClaims(signed, &jwt.StandardClaims{}, ks.Keyfunc)
+		if err != nil || !tkn.Valid {
+			t.Errorf("token with kid=%v not verified: %v", kid, err)
+			continue
+		}
+		if tkn.Header["kid"] != kid {
+			t.Errorf("kid of the token is %v, it should be %v", tkn.Header["kid"], kid)
+		}
+	}
+}
+
+func TestRotation(t *testing.T) {
+	dir := keysDir(t)
+	defer os.RemoveAll(dir)
+
+	ks, err := LoadDir(dir, "rsa-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	old, err := ks.Sign(claims())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = ks.Reload("ed-1"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = jwt.ParseWithClaims(old, &jwt.StandardClaims{}, ks.Keyfunc); err != nil {
+		t.Errorf("token signed before rotation should be valid: %v", err)
+	}
+
+	if err = ks.Reload("missing"); err != ErrNoActiveKey {
+		t.Errorf("reload with unknown active key should fail, err is %v", err)
+	}
+	if ks.Active() != "ed-1" {
+		t.Errorf("failed reload changed the active key to %v", ks.Active())
+	}
+}
+
+func TestActiveFile(t *testing.T) {
+	dir := keysDir(t)
+	defer os.RemoveAll(dir)
+
+	ks, err := LoadDir(dir, "rsa-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = ioutil.WriteFile(filepath.Join(dir, ActiveFile), []byte("ed-1\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = ks.Reload("rsa-1"); err != nil {
+		t.Fatal(err)
+	}
+	if ks.Active() != "ed-1" {
+		t.Errorf("active key is %v, the kid of the active file should be used", ks.Active())
+	}
+}
+
+func TestMethodMismatch(t *testing.T) {
+	dir := keysDir(t)
+	defer os.RemoveAll(dir)
+
+	ks, err := LoadDir(dir, "rsa-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the well known attack, an HS256 token which is signed by the RSA public key
+	pub := ks.keys["rsa-1"].Public.(*rsa.PublicKey)
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims())
+	token.Header["kid"] = "rsa-1"
+	forged, err := token.SignedString(x509.MarshalPKCS1PublicKey(pub))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = jwt.ParseWithClaims(forged, &jwt.StandardClaims{}, ks.Keyfunc); err == nil {
+		t.Error("HS256 token with RSA kid should be refused")
+	}
+}
+
+func TestJWKS(t *testing.T) {
+	dir := keysDir(t)
+	defer os.RemoveAll(dir)
+
+	ks, err := LoadDir(dir, "rsa-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	set := ks.JWKS()
+	if len(set.Keys) != 2 {
+		t.Fatalf("jwks has %v keys, it should be 2", len(set.Keys))
+	}
+	if set.Keys[0].Kid != "ed-1" || set.Keys[0].Kty != "OKP" || set.Keys[0].X == "" {
+		t.Errorf("ed25519 key is not correct: %+v", set.Keys[0])
+	}
+	if set.Keys[1].Kid != "rsa-1" || set.Keys[1].Kty != "RSA" || set.Keys[1].E != "AQAB" {
+		t.Errorf("rsa key is not correct: %+v", set.Keys[1])
+	}
+
+	if n := len(NewHMAC([]byte("secret")).JWKS().Keys); n != 0 {
+		t.Errorf("hmac keys should not be published, jwks has %v keys", n)
+	}
+}
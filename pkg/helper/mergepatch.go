@@ -0,0 +1,50 @@
+package helper
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// MergePatch applies the patch on the doc based on the JSON Merge Patch (RFC 7396), the null
+// values remove the keys and the objects are merged recursively, other values replace the keys
+func MergePatch(doc, patch []byte) ([]byte, error) {
+	var docVal, patchVal interface{}
+	if err := decodeJSON(doc, &docVal); err != nil {
+		return nil, err
+	}
+
+	if err := decodeJSON(patch, &patchVal); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(mergeValue(docVal, patchVal))
+}
+
+// decodeJSON uses json.Number for the numbers so the big ids don't lose precision
+func decodeJSON(data []byte, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
+
+func mergeValue(target, patch interface{}) interface{} {
+	patchObj, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+
+	targetObj, ok := target.(map[string]interface{})
+	if !ok {
+		targetObj = make(map[string]interface{}, len(patchObj))
+	}
+
+	for k, v := range patchObj {
+		if v == nil {
+			delete(targetObj, k)
+			continue
+		}
+		targetObj[k] = mergeValue(targetObj[k], v)
+	}
+
+	return targetObj
+}
@@ -0,0 +1,56 @@
+package helper
+
+import (
+	"testing"
+)
+
+func TestMergePatch(t *testing.T) {
+	// the samples are from the appendix of the RFC 7396
+	samples := []struct {
+		doc   string
+		patch string
+		out   string
+	}{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"a":"foo"}`, `null`, `null`},
+		{`{"a":"foo"}`, `"bar"`, `"bar"`},
+		{`{"e":null}`, `{"a":1}`, `{"a":1,"e":null}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+		{`{"id":18446744073709551615}`, `{"name":"x"}`, `{"id":18446744073709551615,"name":"x"}`},
+	}
+
+	for _, v := range samples {
+		out, err := MergePatch([]byte(v.doc), []byte(v.patch))
+		if err != nil || string(out) != v.out {
+			t.Errorf("\nin: %v, %v\nout: %s, err: %v\nshould be: %v", v.doc, v.patch, out, err,
+				v.out)
+		}
+	}
+}
+
+func TestMergePatchErrors(t *testing.T) {
+	samples := []struct {
+		doc   string
+		patch string
+	}{
+		{`{"a":"b"}`, `{"a":`},
+		{`{"a":`, `{"a":"c"}`},
+		{`{"a":"b"}`, ``},
+	}
+
+	for _, v := range samples {
+		if _, err := MergePatch([]byte(v.doc), []byte(v.patch)); err == nil {
+			t.Errorf("in: %v, %v should return error", v.doc, v.patch)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package password
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Rule is the name of a policy rule which is not satisfied
+type Rule string
+
+// list of rules
+const (
+	RuleLength Rule = "length"
+	RuleLower  Rule = "lower"
+	RuleUpper  Rule = "upper"
+	RuleDigit  Rule = "digit"
+	RuleSymbol Rule = "symbol"
+	RuleBanned Rule = "banned"
+)
+
+// Policy for password strength, history and age are checked in the service layer because
+// they need the database
+type Policy struct {
+	MinLength int
+	Lower     bool
+	Upper     bool
+	Digit     bool
+	Symbol    bool
+}
+
+// ParseClasses fill the character classes from a comma separated list like "lower,digit"
+func (p *Policy) ParseClasses(classes string) {
+	for _, v := range strings.Split(classes, ",") {
+		switch Rule(strings.ToLower(strings.TrimSpace(v))) {
+		case RuleLower:
+			p.Lower = true
+		case RuleUpper:
+			p.Upper = true
+		case RuleDigit:
+			p.Digit = true
+		case RuleSymbol:
+			p.Symbol = true
+		}
+	}
+}
+
+// Check returns the list of rules which are not satisfied by the password
+func (p Policy) Check(password string) (rules []Rule) {
+	if len([]rune(password)) < p.MinLength {
+		rules = append(rules, RuleLength)
+	}
+
+	var lower, upper, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			symbol = true
+		}
+	}
+
+	if p.Lower && !lower {
+		rules = append(rules, RuleLower)
+	}
+	if p.Upper && !upper {
+		rules = append(rules, RuleUpper)
+	}
+	if p.Digit && !digit {
+		rules = append(rules, RuleDigit)
+	}
+	if p.Symbol && !symbol {
+		rules = append(rules, RuleSymbol)
+	}
+
+	if IsBanned(password) {
+		rules = append(rules, RuleBanned)
+	}
+
+	return
+}
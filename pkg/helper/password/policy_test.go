@@ -0,0 +1,62 @@
+package password
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPolicyCheck(t *testing.T) {
+	policy := Policy{MinLength: 8}
+	policy.ParseClasses("lower, UPPER,digit,unknown")
+
+	samples := []struct {
+		in  string
+		out []Rule
+	}{
+		{"Str0ngPassw", nil},
+		{"Sh0rt", []Rule{RuleLength}},
+		{"alllower123", []Rule{RuleUpper}},
+		{"NoDigitsHere", []Rule{RuleDigit}},
+		{"24681357", []Rule{RuleLower, RuleUpper}},
+		{"Password1", []Rule{RuleBanned}},
+		{"", []Rule{RuleLength, RuleLower, RuleUpper, RuleDigit}},
+	}
+
+	for _, v := range samples {
+		result := policy.Check(v.in)
+		if !reflect.DeepEqual(result, v.out) {
+			t.Errorf("for %q result is %v, it should be %v", v.in, result, v.out)
+		}
+	}
+}
+
+func TestPolicySymbol(t *testing.T) {
+	policy := Policy{Symbol: true}
+
+	if rules := policy.Check("no symbol"); rules != nil {
+		t.Errorf("space should be accepted as symbol, rules are %v", rules)
+	}
+
+	if rules := policy.Check("nosymbol"); !reflect.DeepEqual(rules, []Rule{RuleSymbol}) {
+		t.Errorf("for %q rules are %v, it should be %v", "nosymbol", rules, []Rule{RuleSymbol})
+	}
+}
+
+func TestIsBanned(t *testing.T) {
+	samples := []struct {
+		in  string
+		out bool
+	}{
+		{"password", true},
+		{"PassWord", true},
+		{"qwerty123", true},
+		{"c0rrect-h0rse-battery", false},
+		{"", false},
+	}
+
+	for _, v := range samples {
+		if result := IsBanned(v.in); result != v.out {
+			t.Errorf("for %q result is %v, it should be %v", v.in, result, v.out)
+		}
+	}
+}
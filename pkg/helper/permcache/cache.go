@@ -0,0 +1,112 @@
+package permcache
+
+import (
+	"sync"
+)
+
+// DefaultTTL is used in case the ttl is not positive
+const DefaultTTL = 300 // in seconds
+
+// Loader fetch the permissions of the user from the source of truth
+type Loader func(userID uint) (interface{}, error)
+
+// Cache keeps the permissions of the users, implementations should be safe for concurrent use
+type Cache interface {
+	// Get returns the cached permissions, in case they are missed or expired the load is
+	// called and its result is cached
+	Get(userID uint, load Loader) (interface{}, error)
+	// Lookup returns the cached permissions without loading them
+	Lookup(userID uint) (interface{}, bool)
+	Invalidate(userIDs ...uint)
+	InvalidateAll()
+}
+
+// Bus carry the invalidations between the instances of the server, an empty list of the users
+// means all of them should be invalidated
+type Bus interface {
+	Publish(userIDs []uint) error
+	Subscribe(handler func(userIDs []uint))
+}
+
+// Shared is a local cache which broadcast its invalidations via the bus and apply the
+// invalidations of the other instances
+type Shared struct {
+	local   Cache
+	bus     Bus
+	onError func(err error)
+}
+
+// NewShared subscribe the local cache to the bus, onError is called in case publishing failed
+func NewShared(local Cache, bus Bus, onError func(err error)) *Shared {
+	bus.Subscribe(func(userIDs []uint) {
+		if len(userIDs) == 0 {
+			local.InvalidateAll()
+			return
+		}
+		local.Invalidate(userIDs...)
+	})
+
+	return &Shared{local: local, bus: bus, onError: onError}
+}
+
+// Get returns the permissions from the local cache
+func (p *Shared) Get(userID uint, load Loader) (interface{}, error) {
+	return p.local.Get(userID, load)
+}
+
+// Lookup returns the permissions from the local cache without loading them
+func (p *Shared) Lookup(userID uint) (interface{}, bool) {
+	return p.local.Lookup(userID)
+}
+
+// Invalidate erase the users locally and publish them for the other instances
+func (p *Shared) Invalidate(userIDs ...uint) {
+	if len(userIDs) == 0 {
+		return
+	}
+	p.local.Invalidate(userIDs...)
+	p.publish(userIDs)
+}
+
+// InvalidateAll erase all users locally and in the other instances
+func (p *Shared) InvalidateAll() {
+	p.local.InvalidateAll()
+	p.publish(nil)
+}
+
+func (p *Shared) publish(userIDs []uint) {
+	if err := p.bus.Publish(userIDs); err != nil && p.onError != nil {
+		p.onError(err)
+	}
+}
+
+// MemoryBus deliver the invalidations to the subscribers inside the same process, it is used
+// for tests and single instance deployments
+type MemoryBus struct {
+	mu       sync.RWMutex
+	handlers []func(userIDs []uint)
+}
+
+// NewMemoryBus initiate an empty bus
+func NewMemoryBus() *MemoryBus {
+	return &MemoryBus{}
+}
+
+// Publish call all handlers synchronously
+func (p *MemoryBus) Publish(userIDs []uint) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	for _, handler := range p.handlers {
+		handler(userIDs)
+	}
+
+	return nil
+}
+
+// Subscribe add the handler to the bus
+func (p *MemoryBus) Subscribe(handler func(userIDs []uint)) {
+	p.mu.Lock()
+	p.handlers = append(p.handlers, handler)
+	p.mu.Unlock()
+}
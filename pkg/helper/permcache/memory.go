@@ -0,0 +1,92 @@
+package permcache
+
+import (
+	"sync"
+	"time"
+)
+
+type entry struct {
+	value     interface{}
+	expiresAt time.Time
+}
+
+// Memory is an in-process cache with ttl, the generation is increased by each invalidation
+// and a loaded value is not stored if an invalidation happened during the loading, so a stale
+// value read before the invalidation can't be cached after it
+type Memory struct {
+	mu         sync.RWMutex
+	ttl        time.Duration
+	entries    map[uint]entry
+	generation uint64
+	now        func() time.Time
+}
+
+// NewMemory initiate the cache, ttl less than or equal to zero is replaced by DefaultTTL
+func NewMemory(ttl time.Duration) *Memory {
+	if ttl <= 0 {
+		ttl = DefaultTTL * time.Second
+	}
+
+	return &Memory{
+		ttl:     ttl,
+		entries: make(map[uint]entry),
+		now:     time.Now,
+	}
+}
+
+// Get returns the cached permissions or load them
+func (p *Memory) Get(userID uint, load Loader) (value interface{}, err error) {
+	var ok bool
+	if value, ok = p.Lookup(userID); ok {
+		return
+	}
+
+	p.mu.RLock()
+	generation := p.generation
+	p.mu.RUnlock()
+
+	if value, err = load(userID); err != nil {
+		return
+	}
+
+	p.mu.Lock()
+	if generation == p.generation {
+		p.entries[userID] = entry{value: value, expiresAt: p.now().Add(p.ttl)}
+	}
+	p.mu.Unlock()
+
+	return
+}
+
+// Lookup returns the permissions if they are cached and not expired
+func (p *Memory) Lookup(userID uint) (interface{}, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	e, ok := p.entries[userID]
+	if !ok || !p.now().Before(e.expiresAt) {
+		return nil, false
+	}
+
+	return e.value, true
+}
+
+// Invalidate erase the users from the cache
+func (p *Memory) Invalidate(userIDs ...uint) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.generation++
+	for _, v := range userIDs {
+		delete(p.entries, v)
+	}
+}
+
+// InvalidateAll erase all users from the cache
+func (p *Memory) InvalidateAll() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.generation++
+	p.entries = make(map[uint]entry)
+}
@@ -0,0 +1,174 @@
+package permcache
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func counter(resources string, count *int) Loader {
+	return func(userID uint) (interface{}, error) {
+		*count++
+		return resources, nil
+	}
+}
+
+func TestMemoryGet(t *testing.T) {
+	cache := NewMemory(time.Minute)
+	var count int
+
+	for i := 0; i < 3; i++ {
+		resources, err := cache.Get(1, counter("user:read", &count))
+		if err != nil || resources != "user:read" {
+			t.Fatalf("expected user:read, got %q, %v", resources, err)
+		}
+	}
+
+	if count != 1 {
+		t.Errorf("loader should be called once, called %v times", count)
+	}
+}
+
+func TestMemoryTTL(t *testing.T) {
+	now := time.Now()
+	cache := NewMemory(time.Minute)
+	cache.now = func() time.Time { return now }
+	var count int
+
+	cache.Get(1, counter("user:read", &count))
+	if _, ok := cache.Lookup(1); !ok {
+		t.Fatal("resources should be cached")
+	}
+
+	now = now.Add(time.Minute)
+	if _, ok := cache.Lookup(1); ok {
+		t.Error("resources should be expired")
+	}
+
+	cache.Get(1, counter("user:read", &count))
+	if count != 2 {
+		t.Errorf("expired resources should be loaded again, loaded %v times", count)
+	}
+}
+
+func TestMemoryInvalidate(t *testing.T) {
+	cache := NewMemory(time.Minute)
+	var count int
+
+	cache.Get(1, counter("user:read", &count))
+	cache.Get(2, counter("user:read", &count))
+
+	cache.Invalidate(1)
+	if _, ok := cache.Lookup(1); ok {
+		t.Error("user 1 should be invalidated")
+	}
+	if _, ok := cache.Lookup(2); !ok {
+		t.Error("user 2 should be kept")
+	}
+
+	cache.InvalidateAll()
+	if _, ok := cache.Lookup(2); ok {
+		t.Error("user 2 should be invalidated")
+	}
+}
+
+func TestMemoryLoadError(t *testing.T) {
+	cache := NewMemory(time.Minute)
+	_, err := cache.Get(1, func(userID uint) (interface{}, error) {
+		return nil, errors.New("db is down")
+	})
+
+	if err == nil {
+		t.Fatal("error should be returned")
+	}
+	if _, ok := cache.Lookup(1); ok {
+		t.Error("failed load should not be cached")
+	}
+}
+
+func TestMemoryInvalidateDuringLoad(t *testing.T) {
+	cache := NewMemory(time.Minute)
+
+	resources, _ := cache.Get(1, func(userID uint) (interface{}, error) {
+		cache.Invalidate(userID)
+		return "stale", nil
+	})
+
+	if resources != "stale" {
+		t.Errorf("loaded value should be returned, got %q", resources)
+	}
+	if _, ok := cache.Lookup(1); ok {
+		t.Error("value loaded before the invalidation should not be cached")
+	}
+}
+
+func TestMemoryConcurrent(t *testing.T) {
+	cache := NewMemory(time.Minute)
+	load := func(userID uint) (interface{}, error) { return "user:read", nil }
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				userID := uint(j % 10)
+				cache.Get(userID, load)
+				if j%7 == 0 {
+					cache.Invalidate(userID)
+				}
+				if i == 0 && j == 50 {
+					cache.InvalidateAll()
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestSharedInvalidation(t *testing.T) {
+	bus := NewMemoryBus()
+	first := NewShared(NewMemory(time.Minute), bus, nil)
+	second := NewShared(NewMemory(time.Minute), bus, nil)
+	var count int
+
+	first.Get(1, counter("user:read", &count))
+	second.Get(1, counter("user:read", &count))
+	second.Get(2, counter("user:read", &count))
+
+	first.Invalidate(1)
+	if _, ok := second.Lookup(1); ok {
+		t.Error("invalidation should be delivered to the other instance")
+	}
+	if _, ok := second.Lookup(2); !ok {
+		t.Error("other users should be kept")
+	}
+
+	first.InvalidateAll()
+	if _, ok := second.Lookup(2); ok {
+		t.Error("invalidate all should be delivered to the other instance")
+	}
+}
+
+type failedBus struct{ MemoryBus }
+
+func (p *failedBus) Publish(userIDs []uint) error {
+	return errors.New("bus is down")
+}
+
+func TestSharedPublishError(t *testing.T) {
+	var reported error
+	cache := NewShared(NewMemory(time.Minute), &failedBus{}, func(err error) { reported = err })
+	var count int
+
+	cache.Get(1, counter("user:read", &count))
+	cache.Invalidate(1)
+
+	if reported == nil {
+		t.Error("publish error should be reported")
+	}
+	if _, ok := cache.Lookup(1); ok {
+		t.Error("local cache should be invalidated even if publishing failed")
+	}
+}
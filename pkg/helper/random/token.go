@@ -0,0 +1,17 @@
+package random
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// Token create a cryptographically secure random string, it is hex encoded so the length of
+// output is twice of the size
+func Token(size int) (string, error) {
+	b := make([]byte, size)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
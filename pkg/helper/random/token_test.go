@@ -0,0 +1,32 @@
+package random
+
+import (
+	"testing"
+)
+
+func TestToken(t *testing.T) {
+	samples := []struct {
+		size   int
+		length int
+	}{
+		{16, 32},
+		{32, 64},
+		{0, 0},
+	}
+
+	for _, v := range samples {
+		result, err := Token(v.size)
+		if err != nil {
+			t.Fatalf("for size=%v token not generated: %v", v.size, err)
+		}
+		if len(result) != v.length {
+			t.Errorf("for size=%v length is %v, it should be %v", v.size, len(result), v.length)
+		}
+	}
+
+	first, _ := Token(32)
+	second, _ := Token(32)
+	if first == second {
+		t.Errorf("two generated tokens are equal: %q", first)
+	}
+}
@@ -0,0 +1,56 @@
+package helper
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// Searchable keeps the columns which are used for the search parameter, FullText columns should
+// be covered by a FULLTEXT index with the same columns in the same order
+type Searchable struct {
+	FullText []string
+	Like     []string
+}
+
+// SearchExtracter read the search tag of the fields, the value is fulltext or like. For the
+// joined fields the expression of the table tag is used
+func SearchExtracter(t reflect.Type, table string) (s Searchable) {
+	re := regexp.MustCompile(`\w+`)
+	searchTag(t, table, re, &s)
+	return
+}
+
+func searchTag(t reflect.Type, table string, re *regexp.Regexp, s *Searchable) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		externalTable := field.Tag.Get("table")
+
+		if field.Type.Kind() == reflect.Struct && externalTable == "" &&
+			field.Tag.Get("search") == "" {
+			searchTag(field.Type, table, re, s)
+			continue
+		}
+
+		kind := field.Tag.Get("search")
+		column := re.FindString(field.Tag.Get("json"))
+		if kind == "" || column == "" || externalTable == "-" {
+			continue
+		}
+
+		switch {
+		case externalTable != "":
+			column = strings.TrimSpace(strings.Split(externalTable, " as ")[0])
+		default:
+			column = fmt.Sprintf("%v.%v", table, column)
+		}
+
+		switch kind {
+		case "fulltext":
+			s.FullText = append(s.FullText, column)
+		case "like":
+			s.Like = append(s.Like, column)
+		}
+	}
+}
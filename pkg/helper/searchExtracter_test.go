@@ -0,0 +1,31 @@
+package helper
+
+import (
+	"reflect"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+type searchSample struct {
+	gorm.Model
+	Title   string `json:"title,omitempty" search:"fulltext"`
+	Notes   string `json:"notes" search:"fulltext"`
+	Code    string `json:"code,omitempty" search:"like"`
+	Owner   string `json:"owner,omitempty" table:"bas_users.username as owner" search:"like"`
+	Hidden  string `json:"hidden" table:"-" search:"like"`
+	Ignored string `json:"ignored"`
+}
+
+func TestSearchExtracter(t *testing.T) {
+	s := SearchExtracter(reflect.TypeOf(searchSample{}), "sam_notes")
+
+	expected := Searchable{
+		FullText: []string{"sam_notes.title", "sam_notes.notes"},
+		Like:     []string{"sam_notes.code", "bas_users.username"},
+	}
+
+	if !reflect.DeepEqual(s, expected) {
+		t.Errorf("got %+v, should be %+v", s, expected)
+	}
+}
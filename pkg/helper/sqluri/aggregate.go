@@ -0,0 +1,120 @@
+package sqluri
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/syronz/limberr"
+)
+
+// Aggregate is the select and the group by of the aggregate mode, the rows are ordered by the
+// groups
+type Aggregate struct {
+	Select string
+	Group  string
+}
+
+// functions are the accepted aggregate functions
+var functions = map[string]string{
+	"count": "COUNT",
+	"sum":   "SUM",
+	"avg":   "AVG",
+	"min":   "MIN",
+	"max":   "MAX",
+}
+
+// buckets truncate the times for grouping them by the day, month or year
+var buckets = map[string]string{
+	"day":   "DATE(%v)",
+	"month": "DATE_FORMAT(%v, '%%Y-%%m')",
+	"year":  "YEAR(%v)",
+}
+
+// ParseAggregate resolve the group_by like type,created_at:day and the agg like
+// sum:credit,count:id to the columns of the cols. The result of each function is named like
+// sum_credit and the groups are named by their field, a count without column counts the rows.
+// In case agg is empty the rows are counted
+func ParseAggregate(groupBy, agg string, cols []string) (a Aggregate, err error) {
+	var selects, groups []string
+	exist := make(map[string]bool)
+
+	add := func(expr, alias string) error {
+		if exist[alias] {
+			return aggErr("agg", "%v is repeated in the aggregate", alias)
+		}
+		exist[alias] = true
+		selects = append(selects, fmt.Sprintf("%v AS `%v`", expr, alias))
+		return nil
+	}
+
+	if strings.TrimSpace(groupBy) != "" {
+		for _, v := range strings.Split(groupBy, ",") {
+			name, bucket := splitPair(v)
+
+			column, field, ok := Column(name, cols)
+			if !ok {
+				return a, aggErr("group_by", "column %v can't be grouped", name)
+			}
+
+			if bucket != "" {
+				pattern, ok := buckets[strings.ToLower(bucket)]
+				if !ok {
+					return a, aggErr("group_by", "group %v is not valid", strings.TrimSpace(v))
+				}
+				column = fmt.Sprintf(pattern, column)
+			}
+
+			if err = add(column, field); err != nil {
+				return
+			}
+			groups = append(groups, column)
+		}
+	}
+
+	if strings.TrimSpace(agg) == "" {
+		agg = "count"
+	}
+
+	for _, v := range strings.Split(agg, ",") {
+		fn, name := splitPair(v)
+
+		function, ok := functions[strings.ToLower(fn)]
+		if !ok {
+			return a, aggErr("agg", "aggregate %v is not valid", strings.TrimSpace(v))
+		}
+
+		expr, alias := "COUNT(*)", "count"
+		if name != "" && name != "*" {
+			column, field, ok := Column(name, cols)
+			if !ok {
+				return a, aggErr("agg", "column %v can't be aggregated", name)
+			}
+			expr, alias = fmt.Sprintf("%v(%v)", function, column), strings.ToLower(fn)+"_"+field
+		} else if function != "COUNT" {
+			return a, aggErr("agg", "aggregate %v is not valid", strings.TrimSpace(v))
+		}
+
+		if err = add(expr, alias); err != nil {
+			return
+		}
+	}
+
+	a.Select = strings.Join(selects, ", ")
+	a.Group = strings.Join(groups, ", ")
+	return
+}
+
+// splitPair break name:option to its parts
+func splitPair(str string) (name, option string) {
+	name = strings.TrimSpace(str)
+	if i := strings.Index(name, ":"); i >= 0 {
+		name, option = strings.TrimSpace(name[:i]), strings.TrimSpace(name[i+1:])
+	}
+
+	return
+}
+
+func aggErr(field, term string, params ...interface{}) error {
+	err := fmt.Errorf(term, params...)
+	return limberr.AddInvalidParam(err, field, term, params...)
+}
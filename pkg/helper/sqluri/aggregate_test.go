@@ -0,0 +1,93 @@
+package sqluri
+
+import (
+	"omono/pkg/helper"
+	"reflect"
+	"testing"
+)
+
+func TestParseAggregate(t *testing.T) {
+	cols := []string{"sub_accounts.id", "sub_accounts.created_at", "sub_accounts.type",
+		"sub_accounts.credit", "seg_companies.name as company"}
+
+	samples := []struct {
+		groupBy string
+		agg     string
+		sel     string
+		group   string
+	}{
+		{"type", "", "sub_accounts.type AS `type`, COUNT(*) AS `count`", "sub_accounts.type"},
+		{"company", "sum:credit,count:id",
+			"seg_companies.name AS `company`, SUM(sub_accounts.credit) AS `sum_credit`, " +
+				"COUNT(sub_accounts.id) AS `count_id`", "seg_companies.name"},
+		{"type, created_at:day", "count",
+			"sub_accounts.type AS `type`, DATE(sub_accounts.created_at) AS `created_at`, " +
+				"COUNT(*) AS `count`", "sub_accounts.type, DATE(sub_accounts.created_at)"},
+		{"created_at:month", "max:credit",
+			"DATE_FORMAT(sub_accounts.created_at, '%Y-%m') AS `created_at`, " +
+				"MAX(sub_accounts.credit) AS `max_credit`",
+			"DATE_FORMAT(sub_accounts.created_at, '%Y-%m')"},
+		{"", "avg:credit", "AVG(sub_accounts.credit) AS `avg_credit`", ""},
+	}
+
+	for _, v := range samples {
+		a, err := ParseAggregate(v.groupBy, v.agg, cols)
+		if err != nil || a.Select != v.sel || a.Group != v.group {
+			t.Errorf("\nin: %q, %q\nout: %q, %q, err: %v\nshould be: %q, %q",
+				v.groupBy, v.agg, a.Select, a.Group, err, v.sel, v.group)
+		}
+	}
+}
+
+func TestParseAggregateErrors(t *testing.T) {
+	cols := []string{"sub_accounts.id", "sub_accounts.type", "sub_accounts.credit"}
+
+	samples := []struct {
+		groupBy string
+		agg     string
+	}{
+		{"password", ""},
+		{"type:week", ""},
+		{"type", "sum:password"},
+		{"type", "median:credit"},
+		{"type", "sum"},
+		{"type", "count,count:*"},
+		{"type,type", ""},
+		{"type", "sum:credit) FROM bas_users --"},
+		{"type;DROP TABLE sub_accounts", ""},
+	}
+
+	for _, v := range samples {
+		if a, err := ParseAggregate(v.groupBy, v.agg, cols); err == nil {
+			t.Errorf("%q, %q should be refused, got %+v", v.groupBy, v.agg, a)
+		}
+	}
+}
+
+func TestParseAggregateSecretColumns(t *testing.T) {
+	type user struct {
+		ID       uint   `json:"id"`
+		Username string `json:"username"`
+		Password string `json:"password" table:"-"`
+	}
+	cols := helper.TagExtracter(reflect.TypeOf(user{}), "bas_users")
+
+	samples := []struct {
+		groupBy string
+		agg     string
+	}{
+		{"password", ""},
+		{"username", "max:password"},
+		{"username", "min:password"},
+	}
+
+	for _, v := range samples {
+		if a, err := ParseAggregate(v.groupBy, v.agg, cols); err == nil {
+			t.Errorf("%q, %q should be refused, got %+v", v.groupBy, v.agg, a)
+		}
+	}
+
+	if _, err := ParseAggregate("username", "count", cols); err != nil {
+		t.Errorf("group by the username should be accepted, got %v", err)
+	}
+}
@@ -0,0 +1,139 @@
+package sqluri
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/syronz/limberr"
+)
+
+// comparisons are the SQL of the operators, the values are bound to the placeholders
+var comparisons = map[string]string{
+	"eq":   " = ?",
+	"ne":   " != ?",
+	"gt":   " > ?",
+	"lt":   " < ?",
+	"gte":  " >= ?",
+	"lte":  " <= ?",
+	"like": " LIKE ?",
+}
+
+// Compile parse the filter and generate the condition with placeholders beside its arguments,
+// the values never reach the query. The cols are the Cols of the repos, like
+// bas_users.username or bas_roles.name as role, a column in the filter can be the full name,
+// the name after the dot or the alias and it is replaced by the column of the cols
+func Compile(str string, cols []string) (query string, args []interface{}, err error) {
+	var node Node
+	if node, err = Parse(str); err != nil {
+		return
+	}
+
+	c := compiler{columns: columnsOf(cols)}
+	if err = c.compile(node); err != nil {
+		return
+	}
+
+	return c.sb.String(), c.args, nil
+}
+
+// columnsOf map the accepted names to the columns, in case two columns have the same name
+// after the dot the first one which belongs to the main table is kept
+func columnsOf(cols []string) map[string]string {
+	columns := make(map[string]string, len(cols)*2)
+	add := func(name, column string) {
+		if _, ok := columns[name]; !ok {
+			columns[name] = column
+		}
+	}
+
+	for _, v := range cols {
+		column, alias := splitColumn(v)
+
+		add(column, column)
+		switch {
+		case alias != "":
+			add(alias, column)
+		case strings.Contains(column, "."):
+			add(column[strings.LastIndex(column, ".")+1:], column)
+		}
+	}
+
+	return columns
+}
+
+// Column find the column of the name in the cols like the filter, the field is the alias or the
+// name after the dot which is the name of the column in the json of the model
+func Column(name string, cols []string) (column, field string, ok bool) {
+	for _, v := range cols {
+		column, alias := splitColumn(v)
+		field = alias
+		if field == "" {
+			field = column[strings.LastIndex(column, ".")+1:]
+		}
+
+		if name == column || name == field {
+			return column, field, true
+		}
+	}
+
+	return "", "", false
+}
+
+func splitColumn(col string) (column, alias string) {
+	column = strings.TrimSpace(col)
+	if i := strings.Index(strings.ToLower(column), " as "); i >= 0 {
+		column, alias = strings.TrimSpace(column[:i]), strings.TrimSpace(column[i+4:])
+	}
+
+	return
+}
+
+type compiler struct {
+	columns map[string]string
+	sb      strings.Builder
+	args    []interface{}
+}
+
+func (p *compiler) compile(node Node) (err error) {
+	switch n := node.(type) {
+	case Logical:
+		p.sb.WriteString("(")
+		if err = p.compile(n.Left); err != nil {
+			return
+		}
+		p.sb.WriteString(" " + n.Op + " ")
+		if err = p.compile(n.Right); err != nil {
+			return
+		}
+		p.sb.WriteString(")")
+
+	case Not:
+		p.sb.WriteString("NOT (")
+		if err = p.compile(n.Expr); err != nil {
+			return
+		}
+		p.sb.WriteString(")")
+
+	case Comparison:
+		column, ok := p.columns[n.Column]
+		if !ok {
+			err = fmt.Errorf("col '%s' not exist", n.Column)
+			return limberr.AddInvalidParam(err, n.Column, "column %v does not exist", n.Column)
+		}
+		p.sb.WriteString(column)
+
+		switch n.Op {
+		case "in":
+			p.sb.WriteString(" IN (?" + strings.Repeat(", ?", len(n.Values)-1) + ")")
+		case "between":
+			p.sb.WriteString(" BETWEEN ? AND ?")
+		case "null":
+			p.sb.WriteString(" IS NULL")
+		default:
+			p.sb.WriteString(comparisons[n.Op])
+		}
+		p.args = append(p.args, n.Values...)
+	}
+
+	return
+}
@@ -0,0 +1,162 @@
+package sqluri
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/syronz/limberr"
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokString
+	tokNumber
+	tokOp
+	tokLParen
+	tokRParen
+	tokComma
+)
+
+// token is a piece of the filter, pos is the offset of its beginning and it is used in the
+// errors
+type token struct {
+	kind  tokenKind
+	value string
+	pos   int
+}
+
+func (p token) String() string {
+	switch p.kind {
+	case tokEOF:
+		return "end of the filter"
+	case tokString:
+		return "'" + p.value + "'"
+	case tokOp:
+		return "[" + p.value + "]"
+	}
+
+	return p.value
+}
+
+// operators are the accepted words inside the brackets
+var operators = map[string]bool{
+	"eq": true, "ne": true, "gt": true, "lt": true, "gte": true, "lte": true, "like": true,
+	"in": true, "between": true, "null": true, "and": true, "or": true, "not": true,
+}
+
+// tokenize break the filter into the tokens, values are never part of the generated SQL so
+// the lexer just needs to recognize their boundaries
+func tokenize(str string) (tokens []token, err error) {
+	runes := []rune(str)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+
+		case r == '(':
+			tokens = append(tokens, token{kind: tokLParen, value: "(", pos: i})
+			i++
+
+		case r == ')':
+			tokens = append(tokens, token{kind: tokRParen, value: ")", pos: i})
+			i++
+
+		case r == ',':
+			tokens = append(tokens, token{kind: tokComma, value: ",", pos: i})
+			i++
+
+		case r == '[':
+			end := indexRune(runes, i+1, ']')
+			if end < 0 {
+				return nil, unexpected(token{kind: tokEOF, pos: len(runes)})
+			}
+			op := strings.ToLower(strings.TrimSpace(string(runes[i+1 : end])))
+			if !operators[op] {
+				return nil, invalidOperator(op, i)
+			}
+			tokens = append(tokens, token{kind: tokOp, value: op, pos: i})
+			i = end + 1
+
+		case r == '\'':
+			// a quote inside the string is escaped by doubling it like SQL
+			var sb strings.Builder
+			j := i + 1
+			for ; j < len(runes); j++ {
+				if runes[j] == '\'' {
+					if j+1 < len(runes) && runes[j+1] == '\'' {
+						sb.WriteRune('\'')
+						j++
+						continue
+					}
+					break
+				}
+				sb.WriteRune(runes[j])
+			}
+			if j >= len(runes) {
+				return nil, unexpected(token{kind: tokEOF, pos: len(runes)})
+			}
+			tokens = append(tokens, token{kind: tokString, value: sb.String(), pos: i})
+			i = j + 1
+
+		case r == '-' || r == '.' || unicode.IsDigit(r):
+			j := i + 1
+			for j < len(runes) && (unicode.IsDigit(runes[j]) || runes[j] == '.') {
+				j++
+			}
+			tokens = append(tokens, token{kind: tokNumber, value: string(runes[i:j]), pos: i})
+			i = j
+
+		case r == '_' || unicode.IsLetter(r):
+			j := i + 1
+			for j < len(runes) && (runes[j] == '_' || runes[j] == '.' ||
+				unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j])) {
+				j++
+			}
+			tokens = append(tokens, token{kind: tokIdent, value: string(runes[i:j]), pos: i})
+			i = j
+
+		default:
+			return nil, unexpected(token{kind: tokIdent, value: string(r), pos: i})
+		}
+	}
+
+	tokens = append(tokens, token{kind: tokEOF, pos: len(runes)})
+	return
+}
+
+func indexRune(runes []rune, from int, r rune) int {
+	for i := from; i < len(runes); i++ {
+		if runes[i] == r {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// errors are described with the position of the token for helping the client to find it
+func unexpected(tok token) error {
+	return filterErr("%v is not expected at %v in the filter", tok.String(), tok.pos)
+}
+
+func invalidOperator(op string, pos int) error {
+	return filterErr("operator %v at %v in the filter is not valid", "["+op+"]", pos)
+}
+
+func filterErr(term string, params ...interface{}) error {
+	err := fmt.Errorf(term, params...)
+	return limberr.AddInvalidParam(err, "filter", term, params...)
+}
+
+// Quote make a string value of the filter language, it is used in case the filter is built
+// from another parameter
+func Quote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
@@ -1,69 +1,231 @@
 package sqluri
 
 import (
-	"fmt"
-	"omono/pkg/helper"
-	"regexp"
-	"strings"
-
-	"github.com/syronz/limberr"
+	"strconv"
 )
 
-//Parser will break the filter into the sub-query
-func Parser(str string, cols []string) (string, error) {
-	swap := make(map[string]string)
-	swap["[eq]"] = " = "
-	swap["[ne]"] = " != "
-	swap["[gt]"] = " > "
-	swap["[lt]"] = " < "
-	swap["[gte]"] = " >= "
-	swap["[lte]"] = " <= "
-	swap["[like]"] = " LIKE "
-	swap["[and]"] = " AND "
-	swap["[or]"] = " OR "
-
-	ops := []string{"eq", "ne", "gt", "lt", "gte", "lte", "like"}
-
-	regCol := regexp.MustCompile(`\w+[\.\w+]*`)
-	arr := regCol.FindAllString(str, -1)
-
-	regAfterDot := regexp.MustCompile(`\w+$`)
-	var reducedCols []string
-	for _, v := range cols {
-		if strings.Contains(v, ".") {
-			reducedCols = append(reducedCols, regAfterDot.FindString(v))
+// Node is an element of the parsed filter
+type Node interface {
+	node()
+}
+
+// Logical joins two conditions with AND or OR
+type Logical struct {
+	Op    string
+	Left  Node
+	Right Node
+}
+
+// Not negates the condition
+type Not struct {
+	Expr Node
+}
+
+// Comparison is a condition on a column, Values are bound to the placeholders of the query.
+// [eq] has one value, [in] at least one, [between] two and [null] nothing
+type Comparison struct {
+	Column string
+	Op     string
+	Values []interface{}
+}
+
+func (Logical) node()    {}
+func (Not) node()        {}
+func (Comparison) node() {}
+
+// Parse convert the filter to the tree of the conditions, the grammar is
+//
+//	expr       = and { "[or]" and }
+//	and        = unary { "[and]" unary }
+//	unary      = "[not]" unary | "(" expr ")" | comparison
+//	comparison = column ( op value | "[in]" "(" value { "," value } ")" |
+//	             "[between]" value "[and]" value | "[null]" )
+//
+// op is one of the [eq], [ne], [gt], [lt], [gte], [lte] and [like]. A value is a number or a
+// string inside single quotes, a quote inside the string is written twice
+func Parse(str string) (node Node, err error) {
+	var tokens []token
+	if tokens, err = tokenize(str); err != nil {
+		return
+	}
+
+	p := parser{tokens: tokens}
+	if p.peek().kind == tokEOF {
+		err = filterErr("filter is not valid")
+		return
+	}
+
+	if node, err = p.expr(); err != nil {
+		return
+	}
+
+	if tok := p.peek(); tok.kind != tokEOF {
+		err = unexpected(tok)
+	}
+
+	return
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *parser) isOp(op string) bool {
+	tok := p.peek()
+	return tok.kind == tokOp && tok.value == op
+}
+
+func (p *parser) expect(kind tokenKind) (tok token, err error) {
+	if tok = p.next(); tok.kind != kind {
+		err = unexpected(tok)
+	}
+	return
+}
+
+func (p *parser) expr() (node Node, err error) {
+	if node, err = p.and(); err != nil {
+		return
+	}
+
+	for p.isOp("or") {
+		p.next()
+		var right Node
+		if right, err = p.and(); err != nil {
+			return
 		}
-		if strings.Contains(v, "as") {
-			splitString := strings.Split(v, " ")
-			reducedCols = append(reducedCols, splitString[0])
+		node = Logical{Op: "OR", Left: node, Right: right}
+	}
+
+	return
+}
+
+func (p *parser) and() (node Node, err error) {
+	if node, err = p.unary(); err != nil {
+		return
+	}
+
+	for p.isOp("and") {
+		p.next()
+		var right Node
+		if right, err = p.unary(); err != nil {
+			return
 		}
+		node = Logical{Op: "AND", Left: node, Right: right}
 	}
 
-	cols = append(cols, reducedCols...)
+	return
+}
+
+func (p *parser) unary() (node Node, err error) {
+	switch tok := p.peek(); {
+	case tok.kind == tokOp && tok.value == "not":
+		p.next()
+		if node, err = p.unary(); err != nil {
+			return
+		}
+		node = Not{Expr: node}
 
-	if len(arr) == 0 {
-		return "", fmt.Errorf("filter is not valid")
+	case tok.kind == tokLParen:
+		p.next()
+		if node, err = p.expr(); err != nil {
+			return
+		}
+		_, err = p.expect(tokRParen)
+
+	default:
+		node, err = p.comparison()
+	}
+
+	return
+}
+
+func (p *parser) comparison() (node Node, err error) {
+	var col, op token
+	if col, err = p.expect(tokIdent); err != nil {
+		return
 	}
+	if op, err = p.expect(tokOp); err != nil {
+		return
+	}
+
+	cmp := Comparison{Column: col.value, Op: op.value}
 
-	pre := arr[0]
-	for _, v := range arr {
-		if ok, _ := helper.Includes(ops, v); ok {
-			if ok, err := helper.Includes(cols, pre); !ok || err != nil {
-				if err != nil {
-					return "", err
-				}
-				err := fmt.Errorf("col '%s' not exist", pre)
-				err = limberr.AddInvalidParam(err, pre,
-					"column %v not not exist", pre)
-				return "", err
+	switch op.value {
+	case "eq", "ne", "gt", "lt", "gte", "lte", "like":
+		err = p.value(&cmp)
+
+	case "between":
+		if err = p.value(&cmp); err != nil {
+			return
+		}
+		if !p.isOp("and") {
+			err = unexpected(p.peek())
+			return
+		}
+		p.next()
+		err = p.value(&cmp)
+
+	case "in":
+		if _, err = p.expect(tokLParen); err != nil {
+			return
+		}
+		for {
+			if err = p.value(&cmp); err != nil {
+				return
+			}
+
+			if p.peek().kind != tokComma {
+				break
 			}
+			p.next()
 		}
-		pre = v
+		_, err = p.expect(tokRParen)
+
+	case "null":
+
+	default:
+		err = unexpected(op)
 	}
 
-	for k, v := range swap {
-		str = strings.Replace(str, k, v, -1)
+	node = cmp
+	return
+}
+
+// value read a value and add it to the comparison, numbers are converted to int64 or float64
+func (p *parser) value(cmp *Comparison) (err error) {
+	tok := p.next()
+
+	switch tok.kind {
+	case tokString:
+		cmp.Values = append(cmp.Values, tok.value)
+
+	case tokNumber:
+		if n, errParse := strconv.ParseInt(tok.value, 10, 64); errParse == nil {
+			cmp.Values = append(cmp.Values, n)
+			return
+		}
+		f, errParse := strconv.ParseFloat(tok.value, 64)
+		if errParse != nil {
+			return unexpected(tok)
+		}
+		cmp.Values = append(cmp.Values, f)
+
+	default:
+		err = unexpected(tok)
 	}
 
-	return str, nil
+	return
 }
@@ -1,58 +1,107 @@
 package sqluri
 
 import (
-	"fmt"
+	"reflect"
 	"testing"
 )
 
-func TestParser(t *testing.T) {
+func TestCompile(t *testing.T) {
 	samples := []struct {
 		inStr  string
 		inCols []string
 		out    string
-		err    error
+		args   []interface{}
 	}{
 		{
 			inStr:  "username[eq]'diako'",
 			inCols: []string{"username"},
-			out:    "username = 'diako'",
+			out:    "username = ?",
+			args:   []interface{}{"diako"},
 		},
 		{
 			inStr:  "created_by[eq]'makwan'[and]name[eq]'ako'",
 			inCols: []string{"created_by", "users.name"},
-			out:    "created_by = 'makwan' AND name = 'ako'",
+			out:    "(created_by = ? AND users.name = ?)",
+			args:   []interface{}{"makwan", "ako"},
 		},
 		{
 			inStr:  "users.name[eq]'diako'[and](age[gte]25[or]role[eq]'admin')",
 			inCols: []string{"users.name", "users.age", "users.role"},
-			out:    "users.name = 'diako' AND (age >= 25 OR role = 'admin')",
+			out:    "(users.name = ? AND (users.age >= ? OR users.role = ?))",
+			args:   []interface{}{"diako", int64(25), "admin"},
 		},
 		{
-			inStr:  "users.name[eq]'diako'[and](age[gte]25[or]role[eq]'admin')",
-			inCols: []string{"users.age", "users.role"},
-			out:    "",
-			err:    fmt.Errorf("column 'users.name' not exist"),
+			inStr:  "a[eq]1[or]b[eq]2[and]c[eq]3",
+			inCols: []string{"t.a", "t.b", "t.c"},
+			out:    "(t.a = ? OR (t.b = ? AND t.c = ?))",
+			args:   []interface{}{int64(1), int64(2), int64(3)},
+		},
+		{
+			inStr:  "status[in]('active', 'inactive')[and][not]deleted_at[null]",
+			inCols: []string{"bas_users.status", "bas_users.deleted_at"},
+			out:    "(bas_users.status IN (?, ?) AND NOT (bas_users.deleted_at IS NULL))",
+			args:   []interface{}{"active", "inactive"},
+		},
+		{
+			inStr:  "price[between]1.5[and]10",
+			inCols: []string{"items.price"},
+			out:    "items.price BETWEEN ? AND ?",
+			args:   []interface{}{1.5, int64(10)},
 		},
 		{
-			inStr:  "'; select * from bas_users",
-			inCols: []string{"users.age", "users.role"},
-			out:    "",
-			err:    fmt.Errorf("column 'users.name' not exist"),
+			inStr:  "role[like]'adm%'",
+			inCols: []string{"bas_users.id", "bas_roles.name as role"},
+			out:    "bas_roles.name LIKE ?",
+			args:   []interface{}{"adm%"},
 		},
 		{
-			inStr:  "bas_roles.name[eq]'admin'",
-			inCols: []string{"name"},
-			out:    "",
+			inStr:  "name[eq]'x'' OR ''1''=''1'",
+			inCols: []string{"users.name"},
+			out:    "users.name = ?",
+			args:   []interface{}{"x' OR '1'='1"},
 		},
 	}
 
 	for _, v := range samples {
-		result, err := Parser(v.inStr, v.inCols)
-		if result != v.out {
-			t.Errorf("\nin: %q, %q\nout: %q, err:%q\nshould be: %q",
-				v.inStr, v.inCols, result, err, v.out)
+		query, args, err := Compile(v.inStr, v.inCols)
+		if err != nil || query != v.out || !reflect.DeepEqual(args, v.args) {
+			t.Errorf("\nin: %q, %q\nout: %q, %v, err: %v\nshould be: %q, %v",
+				v.inStr, v.inCols, query, args, err, v.out, v.args)
 		}
+	}
+}
 
+func TestCompileErrors(t *testing.T) {
+	samples := []struct {
+		inStr  string
+		inCols []string
+	}{
+		{"users.name[eq]'diako'[and](age[gte]25[or]role[eq]'admin')",
+			[]string{"users.age", "users.role"}},
+		{"'; select * from bas_users", []string{"users.age", "users.role"}},
+		{"age[eq]25; DROP TABLE bas_users", []string{"users.age"}},
+		{"age[eq]25 OR 1=1", []string{"users.age"}},
+		{"age[eq](SELECT 1)", []string{"users.age"}},
+		{"bas_roles.name[eq]'admin'", []string{"name"}},
+		{"name[eq]'open", []string{"name"}},
+		{"name[drop]'x'", []string{"name"}},
+		{"(name[eq]'x'", []string{"name"}},
+		{"age[between]1", []string{"age"}},
+		{"age[in]()", []string{"age"}},
+		{"age[eq]1.2.3", []string{"age"}},
+		{"", []string{"age"}},
+	}
+
+	for _, v := range samples {
+		if query, args, err := Compile(v.inStr, v.inCols); err == nil {
+			t.Errorf("%q should be refused, got %q, %v", v.inStr, query, args)
+		}
 	}
+}
 
+func TestQuote(t *testing.T) {
+	_, args, err := Compile("name[eq]"+Quote("o'brien"), []string{"users.name"})
+	if err != nil || !reflect.DeepEqual(args, []interface{}{"o'brien"}) {
+		t.Errorf("quoted value should be bound as it is, got %v, %v", args, err)
+	}
 }
@@ -0,0 +1,94 @@
+package sqluri
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/syronz/limberr"
+)
+
+// SortKey is a column of the sort, Field is the name of the column in the json of the model.
+// Nulls is empty for the default of the database, first or last
+type SortKey struct {
+	Column string
+	Field  string
+	Desc   bool
+	Nulls  string
+}
+
+// ParseSort resolve the sort like -created_at,name_en:nulls_last to the columns of the cols,
+// a minus before the name is the descending order and a plus or nothing is the ascending. The
+// names are accepted like the columns of the filter
+func ParseSort(str string, cols []string) (keys []SortKey, err error) {
+	exist := make(map[string]bool)
+
+	for _, v := range strings.Split(str, ",") {
+		item := strings.TrimSpace(v)
+		var key SortKey
+
+		if i := strings.Index(item, ":"); i >= 0 {
+			switch strings.ToLower(item[i+1:]) {
+			case "nulls_first":
+				key.Nulls = "first"
+			case "nulls_last":
+				key.Nulls = "last"
+			default:
+				return nil, sortErr("sort %v is not valid", item)
+			}
+			item = item[:i]
+		}
+
+		switch {
+		case strings.HasPrefix(item, "-"):
+			key.Desc = true
+			item = item[1:]
+		case strings.HasPrefix(item, "+"):
+			item = item[1:]
+		}
+
+		if item == "" {
+			return nil, sortErr("sort %v is not valid", strings.TrimSpace(v))
+		}
+
+		var ok bool
+		if key.Column, key.Field, ok = Column(item, cols); !ok {
+			return nil, sortErr("column %v is not sortable", item)
+		}
+
+		if exist[key.Column] {
+			return nil, sortErr("column %v is repeated in the sort", item)
+		}
+		exist[key.Column] = true
+
+		keys = append(keys, key)
+	}
+
+	return
+}
+
+// OrderOf generate the ORDER BY of the keys, MySQL doesn't have the NULLS FIRST and NULLS LAST
+// so they are applied by sorting on the IS NULL of the column before the column itself
+func OrderOf(keys []SortKey) string {
+	arr := make([]string, 0, len(keys))
+	for _, v := range keys {
+		switch v.Nulls {
+		case "first":
+			arr = append(arr, v.Column+" IS NULL DESC")
+		case "last":
+			arr = append(arr, v.Column+" IS NULL ASC")
+		}
+
+		dir := " ASC"
+		if v.Desc {
+			dir = " DESC"
+		}
+		arr = append(arr, v.Column+dir)
+	}
+
+	return strings.Join(arr, ", ")
+}
+
+func sortErr(term string, params ...interface{}) error {
+	err := fmt.Errorf(term, params...)
+	return limberr.AddInvalidParam(err, "sort", term, params...)
+}
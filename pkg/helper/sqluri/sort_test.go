@@ -0,0 +1,51 @@
+package sqluri
+
+import (
+	"testing"
+)
+
+func TestParseSort(t *testing.T) {
+	cols := []string{"sub_accounts.id", "sub_accounts.created_at", "sub_accounts.name_en",
+		"bas_users.username as creator"}
+
+	samples := []struct {
+		in  string
+		out string
+	}{
+		{"name_en", "sub_accounts.name_en ASC"},
+		{"-created_at,name_en", "sub_accounts.created_at DESC, sub_accounts.name_en ASC"},
+		{"+sub_accounts.id", "sub_accounts.id ASC"},
+		{"creator:nulls_last,-id", "bas_users.username IS NULL ASC, bas_users.username ASC, " +
+			"sub_accounts.id DESC"},
+		{"-name_en:NULLS_FIRST", "sub_accounts.name_en IS NULL DESC, sub_accounts.name_en DESC"},
+	}
+
+	for _, v := range samples {
+		keys, err := ParseSort(v.in, cols)
+		if out := OrderOf(keys); err != nil || out != v.out {
+			t.Errorf("\nin: %q\nout: %q, err: %v\nshould be: %q", v.in, out, err, v.out)
+		}
+	}
+}
+
+func TestParseSortErrors(t *testing.T) {
+	cols := []string{"sub_accounts.id", "sub_accounts.name_en"}
+
+	samples := []string{
+		"",
+		"-",
+		"name_en,",
+		"password",
+		"name_en desc",
+		"name_en;DROP TABLE sub_accounts",
+		"(SELECT 1)",
+		"name_en:nulls",
+		"name_en,-name_en",
+	}
+
+	for _, v := range samples {
+		if keys, err := ParseSort(v, cols); err == nil {
+			t.Errorf("%q should be refused, got %+v", v, keys)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package totp
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// secret is the base32 of "12345678901234567890" which is used in RFC 6238 test vectors
+const secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestCode(t *testing.T) {
+	samples := []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	}
+
+	for _, v := range samples {
+		result, err := Code(secret, time.Unix(v.unix, 0))
+		if err != nil {
+			t.Fatalf("for time=%v code not generated: %v", v.unix, err)
+		}
+		if result != v.code {
+			t.Errorf("for time=%v code is %v, it should be %v", v.unix, result, v.code)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+
+	samples := []struct {
+		code string
+		at   time.Time
+		out  bool
+	}{
+		{"050471", now, true},
+		{"050471", now.Add(Period * time.Second), true},
+		{"050471", now.Add(-Period * time.Second), true},
+		{"050471", now.Add(3 * Period * time.Second), false},
+		{"050472", now, false},
+		{"50471", now, false},
+		{"", now, false},
+	}
+
+	for _, v := range samples {
+		if result := Validate(secret, v.code, v.at); result != v.out {
+			t.Errorf("for code=%q at %v result is %v, it should be %v", v.code, v.at.Unix(), result, v.out)
+		}
+	}
+
+	if Validate("not-base32!", "050471", now) {
+		t.Errorf("invalid secret should not be accepted")
+	}
+}
+
+func TestMatch(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	counter := int64(1111111111) / Period
+
+	samples := []struct {
+		code string
+		at   time.Time
+		step int64
+		ok   bool
+	}{
+		{"050471", now, counter, true},
+		{"050471", now.Add(Period * time.Second), counter, true},
+		{"050471", now.Add(-Period * time.Second), counter, true},
+		{"050471", now.Add(3 * Period * time.Second), 0, false},
+		{"050472", now, 0, false},
+	}
+
+	for _, v := range samples {
+		step, ok := Match(secret, v.code, v.at)
+		if step != v.step || ok != v.ok {
+			t.Errorf("for code=%q at %v result is %v, %v, it should be %v, %v", v.code,
+				v.at.Unix(), step, ok, v.step, v.ok)
+		}
+	}
+}
+
+func TestGenerateSecret(t *testing.T) {
+	first, err := GenerateSecret()
+	if err != nil {
+		t.Fatalf("secret not generated: %v", err)
+	}
+
+	second, _ := GenerateSecret()
+	if first == second {
+		t.Errorf("two generated secrets are equal: %q", first)
+	}
+
+	code, err := Code(first, time.Now())
+	if err != nil {
+		t.Fatalf("generated secret is not valid: %v", err)
+	}
+	if !Validate(first, code, time.Now()) {
+		t.Errorf("code of generated secret is not accepted")
+	}
+}
+
+func TestURI(t *testing.T) {
+	uri := URI("omono", "admin", secret)
+	if !strings.HasPrefix(uri, "otpauth://totp/omono:admin?") {
+		t.Errorf("uri has wrong prefix: %v", uri)
+	}
+	if !strings.Contains(uri, "secret="+secret) || !strings.Contains(uri, "issuer=omono") {
+		t.Errorf("uri doesn't have secret or issuer: %v", uri)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"omono/domain/base"
 	"omono/internal/core"
 	"omono/internal/types"
+	"omono/pkg/helper/jwtkey"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -42,6 +43,8 @@ type TestEnvs struct {
 		PasswordSalt         string `json:"password_salt"`
 		JWTSecretKey         string `json:"jwt_secret_key"`
 		JWTExpiration        string `json:"jwt_expiration"`
+		JWTRefreshExpiration string `json:"jwt_refresh_expiration"`
+		PasswordResetExp     string `json:"password_reset_expiration"`
 		AdminUsername        string `json:"admin_username"`
 		AdminPassword        string `json:"admin_password"`
 		DefaultUsersParentID string `json:"default_user_parent_id"`
@@ -101,10 +104,13 @@ func LoadTestEnv() *core.Engine {
 	envs[base.PasswordSalt] = testEnvs.Base.PasswordSalt
 	envs[base.JWTSecretKey] = testEnvs.Base.JWTSecretKey
 	envs[base.JWTExpiration] = testEnvs.Base.JWTExpiration
+	envs[base.JWTRefreshExpiration] = testEnvs.Base.JWTRefreshExpiration
+	envs[base.PasswordResetExpiration] = testEnvs.Base.PasswordResetExp
 	envs[base.AdminUsername] = testEnvs.Base.AdminUsername
 	envs[base.AdminPassword] = testEnvs.Base.AdminPassword
 
 	engine.Envs = envs
+	engine.JWTKeys = jwtkey.NewHMAC(envs.ToByte(base.JWTSecretKey))
 
 	return &engine
 }